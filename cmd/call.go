@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/call"
+	"github.com/Rapid-Vision/rRPC/internal/protocol"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var callCmd = &cobra.Command{
+	Use:   "call [schema] [rpc] [--param value]...",
+	Short: "Call an RPC of a running server with parameters given as flags",
+	Long: `Call an RPC of a running server with parameters given as flags.
+
+Parameters follow the RPC name: --id 42 sets parameter id, --text.title foo
+sets field title of the model parameter text. Use -d to start from a JSON
+body, and --describe to list the flags of an RPC.`,
+	Example: `  rrpc call --url http://localhost:8080 api.rrpc GetText --id 42
+  rrpc call api.rrpc CreateText --text.title foo --text.body bar
+  rrpc call api.rrpc CreateText -d @body.json
+  rrpc call --list api.rrpc
+  rrpc call --describe CreateText api.rrpc`,
+	// Parameter flags depend on the schema, so flags are split by hand.
+	DisableFlagParsing: true,
+	RunE:               RunCallCmd,
+}
+
+var (
+	callURL      string
+	callPrefix   string
+	callHeaders  []string
+	callData     string
+	callList     bool
+	callDescribe string
+)
+
+func init() {
+	rootCmd.AddCommand(callCmd)
+	callCmd.Flags().StringVar(&callURL, "url", "http://localhost:8080", "Base URL of the server")
+	callCmd.Flags().StringVar(&callPrefix, "prefix", "rpc", "URL path prefix (empty for none)")
+	callCmd.Flags().StringArrayVarP(&callHeaders, "header", "H", nil, "Extra request header in \"Key: Value\" form (repeatable)")
+	callCmd.Flags().StringVarP(&callData, "data", "d", "", "JSON request body, @file to read it from a file or @- from stdin")
+	callCmd.Flags().BoolVar(&callList, "list", false, "List the RPCs of the schema")
+	callCmd.Flags().StringVar(&callDescribe, "describe", "", "Describe an RPC and the flags that set its parameters")
+}
+
+func RunCallCmd(cmd *cobra.Command, args []string) error {
+	known, positional, params, late, err := splitCallArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	// ParseFlags is a no-op with flag parsing disabled.
+	flags := cmd.Flags()
+	flags.AddFlagSet(cmd.InheritedFlags())
+	if err := flags.Parse(known); err != nil {
+		return err
+	}
+	if help, _ := cmd.Flags().GetBool("help"); help {
+		return cmd.Help()
+	}
+	if err := checkRootFlags(cmd, nil); err != nil {
+		return err
+	}
+
+	if callList || callDescribe != "" {
+		if len(positional) != 1 || len(params) > 0 {
+			return fmt.Errorf("expected schema path argument")
+		}
+		schema, err := loadSchema(cmd, positional[0])
+		if err != nil {
+			return err
+		}
+		if callList {
+			return call.List(cmd.OutOrStdout(), schema)
+		}
+		return call.Describe(cmd.OutOrStdout(), schema, callDescribe, callPrefix)
+	}
+
+	if len(positional) != 2 {
+		return fmt.Errorf("expected schema path and rpc name arguments")
+	}
+	headers, err := parseHeaders(callHeaders)
+	if err != nil {
+		return err
+	}
+	schema, err := loadSchema(cmd, positional[0])
+	if err != nil {
+		return err
+	}
+	rpc, err := call.FindRPC(schema, positional[1])
+	if err != nil {
+		return err
+	}
+	for _, name := range late {
+		for _, param := range rpc.Parameters {
+			if param.Name == name || protocol.JSONName(param.Name) == name {
+				return fmt.Errorf("--%s after the rpc name is ambiguous: it is a flag of rrpc call and a parameter of %s (put it before the schema path, or parameters after \"--\")", name, rpc.Name)
+			}
+		}
+	}
+	body, err := readCallData(cmd)
+	if err != nil {
+		return err
+	}
+	values, err := call.Params(rpc, protocol.ModelIndex(schema), body, params)
+	if err != nil {
+		return err
+	}
+	resp, err := call.Send(cmd.Context(), rpc, values, call.Options{
+		BaseURL: callURL,
+		Prefix:  callPrefix,
+		Headers: headers,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := cmd.OutOrStdout().Write(resp.Body); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	if resp.Error != nil {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%s error (status %d): %s", resp.Error.Type, resp.Status, resp.Error.Message)
+	}
+	return nil
+}
+
+// splitCallArgs separates the flags of the command, positional arguments
+// and parameter flags. Parameters start at the first long flag the command
+// does not know. Like in call.Params, a parameter flag without "=" takes
+// the next argument as its value unless that starts with "--", and
+// arguments after "--" are all parameters. late names the long command
+// flags given after the rpc name, which could also be meant as parameters.
+func splitCallArgs(cmd *cobra.Command, args []string) (known, positional, params, late []string, err error) {
+	pending := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			params = append(params, args[i+1:]...)
+			break
+		}
+		if pending && !strings.HasPrefix(arg, "--") {
+			params = append(params, arg)
+			pending = false
+			continue
+		}
+		pending = false
+		var flag *pflag.Flag
+		switch {
+		case strings.HasPrefix(arg, "--"):
+			name, _, _ := strings.Cut(arg[2:], "=")
+			flag = lookupCallFlag(cmd, name, "")
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			flag = lookupCallFlag(cmd, "", arg[1:2])
+			if flag == nil {
+				return nil, nil, nil, nil, fmt.Errorf("unknown shorthand flag: %q in %s", arg[1:2], arg)
+			}
+		}
+		switch {
+		case flag != nil:
+			known = append(known, arg)
+			if len(positional) >= 2 && strings.HasPrefix(arg, "--") {
+				late = append(late, flag.Name)
+			}
+			// The value is the next argument, unless it is attached.
+			attached := strings.Contains(arg, "=") || !strings.HasPrefix(arg, "--") && len(arg) > 2
+			if flag.NoOptDefVal == "" && !attached && i+1 < len(args) {
+				i++
+				known = append(known, args[i])
+			}
+		case strings.HasPrefix(arg, "--"):
+			params = append(params, arg)
+			pending = !strings.Contains(arg, "=")
+		case len(params) > 0:
+			params = append(params, arg)
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	return known, positional, params, late, nil
+}
+
+func lookupCallFlag(cmd *cobra.Command, name, shorthand string) *pflag.Flag {
+	for _, flags := range []*pflag.FlagSet{cmd.Flags(), cmd.InheritedFlags()} {
+		if name != "" {
+			if flag := flags.Lookup(name); flag != nil {
+				return flag
+			}
+		} else if flag := flags.ShorthandLookup(shorthand); flag != nil {
+			return flag
+		}
+	}
+	return nil
+}
+
+func readCallData(cmd *cobra.Command) (map[string]any, error) {
+	if callData == "" {
+		return nil, nil
+	}
+	raw := []byte(callData)
+	if path, ok := strings.CutPrefix(callData, "@"); ok {
+		var err error
+		if path == "-" {
+			raw, err = io.ReadAll(cmd.InOrStdin())
+		} else {
+			raw, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+	}
+	value, err := protocol.Decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request body: %w", err)
+	}
+	body, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid request body: expected JSON object")
+	}
+	return body, nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Rapid-Vision/rRPC/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Fail when generated code is out of date with the schema",
+	Long: `Regenerate all targets described in rrpc.yaml in memory and compare
+the result with the files on disk. Nothing is written. Out-of-date files are
+printed as unified diffs and make the command exit non-zero.`,
+	Args: cobra.NoArgs,
+	RunE: RunCheckCmd,
+}
+
+var (
+	checkConfig  string
+	checkTargets []string
+	checkQuiet   bool
+)
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+	checkCmd.Flags().StringVarP(&checkConfig, "config", "c", project.FileName, "Path to the project config file")
+	checkCmd.Flags().StringArrayVarP(&checkTargets, "target", "t", nil, "Only check the named target (repeatable)")
+	checkCmd.Flags().BoolVarP(&checkQuiet, "quiet", "q", false, "List out-of-date files without diffs")
+}
+
+func RunCheckCmd(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	cfg, err := project.Load(checkConfig)
+	if err != nil {
+		return err
+	}
+	targets, err := selectTargets(cfg, checkTargets)
+	if err != nil {
+		return err
+	}
+	schema, err := loadSchema(cmd, cfg.SchemaPath())
+	if err != nil {
+		return err
+	}
+
+	stale := 0
+	out := cmd.OutOrStdout()
+	for _, target := range targets {
+		files, err := project.Generate(schema, cfg.URLPrefix(), target)
+		if err != nil {
+			return fmt.Errorf("%s: %w", target, err)
+		}
+		drifts, err := project.Compare(cfg.TargetDir(target), target.Generator(), files)
+		if err != nil {
+			return fmt.Errorf("%s: %w", target, err)
+		}
+		for _, drift := range drifts {
+			stale++
+			status := "modified"
+			if drift.Missing {
+				status = "missing"
+			} else if drift.Stale {
+				status = "stale"
+			}
+			if checkQuiet {
+				fmt.Fprintf(out, "%s: %s %s\n", target, status, drift.Path)
+				continue
+			}
+			fmt.Fprint(out, drift.Diff)
+		}
+	}
+	if stale > 0 {
+		return fmt.Errorf("%d generated file(s) out of date, run rrpc generate", stale)
+	}
+	return nil
+}
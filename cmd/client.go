@@ -2,13 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
+	"strings"
 
-	gogen "github.com/Rapid-Vision/rRPC/internal/gen/go"
-	pygen "github.com/Rapid-Vision/rRPC/internal/gen/python"
-	tsgen "github.com/Rapid-Vision/rRPC/internal/gen/typescript"
-	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/project"
 	"github.com/spf13/cobra"
 )
 
@@ -19,135 +15,75 @@ var clientCmd = &cobra.Command{
 }
 
 var (
-	clientLang   string
-	clientPkg    string
-	clientOut    string
-	clientForce  bool
-	clientPrefix string
-	clientZod    bool
-	clientPyd    bool
+	clientLang      string
+	clientPkg       string
+	clientOut       string
+	clientForce     bool
+	clientPrefix    string
+	clientTemplates string
+	clientZod       bool
+	clientPyd       bool
+	clientPlugin    []string
 )
 
 func init() {
 	rootCmd.AddCommand(clientCmd)
-	clientCmd.Flags().StringVar(&clientLang, "lang", "python", "Output language")
+	clientCmd.Flags().StringVar(&clientLang, "lang", "python", "Output language, or plugin:<name> to run the rrpc-gen-<name> plugin")
 	clientCmd.Flags().StringVarP(&clientPkg, "pkg", "p", "rpcclient", "Output package/directory name for generated code")
 	clientCmd.Flags().StringVarP(&clientOut, "output", "o", ".", "Output base directory")
-	clientCmd.Flags().BoolVarP(&clientForce, "force", "f", false, "Overwrite output file if it exists")
+	clientCmd.Flags().BoolVarP(&clientForce, "force", "f", false, "Overwrite files that were edited by hand or not generated by rrpc")
 	clientCmd.Flags().StringVar(&clientPrefix, "prefix", "rpc", "URL path prefix (empty for none)")
+	clientCmd.Flags().StringVar(&clientTemplates, "templates", "", "Directory of templates overriding the embedded ones by file name")
 	clientCmd.Flags().BoolVar(&clientZod, "ts-zod", false, "Generate TypeScript client with zod input validation")
 	clientCmd.Flags().BoolVar(&clientPyd, "py-pydantic", false, "Generate Python client with pydantic input validation")
+	clientCmd.Flags().StringArrayVar(&clientPlugin, "plugin-opt", nil, "Pass key=value to a plugin generator (repeatable)")
 }
 
 func RunClientCmd(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
 		return fmt.Errorf("expected schema path argument")
 	}
-	if clientLang != "python" && clientLang != "py" && clientLang != "go" && clientLang != "ts" && clientLang != "typescript" {
-		return fmt.Errorf("unsupported language %q for client", clientLang)
+	pluginOptions, err := parsePluginOptions(clientPlugin)
+	if err != nil {
+		return err
+	}
+	target := project.Target{
+		Kind:      project.KindClient,
+		Lang:      clientLang,
+		Package:   clientPkg,
+		Options:   project.Options{TSZod: clientZod, PyPydantic: clientPyd, Plugin: pluginOptions},
+		Templates: clientTemplates,
+	}
+	if err := target.Validate(); err != nil {
+		return err
 	}
-	schemaPath := args[0]
-	data, err := os.ReadFile(schemaPath)
+	schema, err := loadSchema(cmd, args[0])
 	if err != nil {
-		return fmt.Errorf("read schema: %w", err)
+		return err
 	}
-	schema, err := parser.Parse(string(data))
+	files, err := project.Generate(schema, clientPrefix, target)
 	if err != nil {
-		return fmt.Errorf("parse schema: %w", err)
+		return err
 	}
 	outputDir := clientOut
 	if outputDir == "" {
 		outputDir = "."
 	}
-	baseDir := filepath.Join(outputDir, clientPkg)
-	if clientLang == "go" {
-		files, err := gogen.GenerateClientWithPrefix(schema, clientPkg, clientPrefix)
-		if err != nil {
-			return fmt.Errorf("generate code: %w", err)
-		}
-		filePaths := make([]string, 0, len(files))
-		for name := range files {
-			filePaths = append(filePaths, filepath.Join(baseDir, name))
-		}
-		if !clientForce {
-			for _, path := range filePaths {
-				if _, statErr := os.Stat(path); statErr == nil {
-					return fmt.Errorf("output file exists: %s (use --force to overwrite)", path)
-				}
-			}
-		}
-		if err := os.MkdirAll(baseDir, 0o755); err != nil {
-			return fmt.Errorf("create output dir: %w", err)
-		}
-		for name, contents := range files {
-			outPath := filepath.Join(baseDir, name)
-			if err := os.WriteFile(outPath, []byte(contents), 0o644); err != nil {
-				return fmt.Errorf("write output: %w", err)
-			}
-		}
-		return nil
-	}
-	if clientLang == "ts" || clientLang == "typescript" {
-		files, err := tsgen.GenerateClientWithPrefixAndZod(schema, clientPrefix, clientZod)
-		if err != nil {
-			return fmt.Errorf("generate code: %w", err)
-		}
-		indexPath := filepath.Join(baseDir, "index.ts")
-		filePaths := make([]string, 0, len(files)+1)
-		for name := range files {
-			filePaths = append(filePaths, filepath.Join(baseDir, name))
-		}
-		filePaths = append(filePaths, indexPath)
-		if !clientForce {
-			for _, path := range filePaths {
-				if _, statErr := os.Stat(path); statErr == nil {
-					return fmt.Errorf("output file exists: %s (use --force to overwrite)", path)
-				}
-			}
-		}
-		if err := os.MkdirAll(baseDir, 0o755); err != nil {
-			return fmt.Errorf("create output dir: %w", err)
-		}
-		for name, contents := range files {
-			outPath := filepath.Join(baseDir, name)
-			if err := os.WriteFile(outPath, []byte(contents), 0o644); err != nil {
-				return fmt.Errorf("write output: %w", err)
-			}
-		}
-		if err := os.WriteFile(indexPath, []byte(tsgen.GenerateTypeScriptIndexWithZod(schema, clientZod)), 0o644); err != nil {
-			return fmt.Errorf("write output: %w", err)
-		}
-		return nil
-	}
+	return project.WriteFiles(target.Dir(outputDir), target.Generator(), files, clientForce)
+}
 
-	files, err := pygen.GenerateClientWithPrefixAndPydantic(schema, clientPrefix, clientPyd)
-	if err != nil {
-		return fmt.Errorf("generate code: %w", err)
+// parsePluginOptions parses repeated --plugin-opt key=value flags.
+func parsePluginOptions(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
 	}
-	filePaths := make([]string, 0, len(files)+1)
-	for name := range files {
-		filePaths = append(filePaths, filepath.Join(baseDir, name))
-	}
-	initPath := filepath.Join(baseDir, "__init__.py")
-	filePaths = append(filePaths, initPath)
-	if !clientForce {
-		for _, path := range filePaths {
-			if _, statErr := os.Stat(path); statErr == nil {
-				return fmt.Errorf("output file exists: %s (use --force to overwrite)", path)
-			}
+	options := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --plugin-opt %q (expected key=value)", value)
 		}
+		options[key] = val
 	}
-	if err := os.MkdirAll(baseDir, 0o755); err != nil {
-		return fmt.Errorf("create output dir: %w", err)
-	}
-	for name, contents := range files {
-		outPath := filepath.Join(baseDir, name)
-		if err := os.WriteFile(outPath, []byte(contents), 0o644); err != nil {
-			return fmt.Errorf("write output: %w", err)
-		}
-	}
-	if err := os.WriteFile(initPath, []byte(pygen.GeneratePythonInit(schema)), 0o644); err != nil {
-		return fmt.Errorf("write output: %w", err)
-	}
-	return nil
+	return options, nil
 }
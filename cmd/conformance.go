@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/conformance"
+	"github.com/spf13/cobra"
+)
+
+var conformanceCmd = &cobra.Command{
+	Use:   "conformance",
+	Short: "Check an implementation against the rRPC wire protocol",
+}
+
+var conformanceServerCmd = &cobra.Command{
+	Use:   "server [schema]",
+	Short: "Send protocol edge cases to a running server and report violations",
+	RunE:  RunConformanceServerCmd,
+}
+
+var (
+	conformanceURL     string
+	conformancePrefix  string
+	conformanceHeaders []string
+)
+
+func init() {
+	rootCmd.AddCommand(conformanceCmd)
+	conformanceCmd.AddCommand(conformanceServerCmd)
+	conformanceServerCmd.Flags().StringVar(&conformanceURL, "url", "http://localhost:8080", "Base URL of the server under test")
+	conformanceServerCmd.Flags().StringVar(&conformancePrefix, "prefix", "rpc", "URL path prefix (empty for none)")
+	conformanceServerCmd.Flags().StringArrayVarP(&conformanceHeaders, "header", "H", nil, "Extra request header in \"Key: Value\" form (repeatable)")
+}
+
+func RunConformanceServerCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected schema path argument")
+	}
+	headers, err := parseHeaders(conformanceHeaders)
+	if err != nil {
+		return err
+	}
+	schema, err := loadSchema(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	results, err := conformance.Run(cmd.Context(), schema, conformance.Options{
+		BaseURL: conformanceURL,
+		Prefix:  conformancePrefix,
+		Headers: headers,
+	})
+	if err != nil {
+		return fmt.Errorf("run conformance: %w", err)
+	}
+	failed, err := conformance.WriteReport(cmd.OutOrStdout(), results)
+	if err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d conformance checks failed", failed, len(results))
+	}
+	return nil
+}
+
+func parseHeaders(values []string) (map[string]string, error) {
+	headers := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid header %q (expected \"Key: Value\")", value)
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return headers, nil
+}
@@ -6,23 +6,26 @@ import (
 	"text/tabwriter"
 
 	"github.com/Rapid-Vision/rRPC/internal/lexer"
-	"github.com/Rapid-Vision/rRPC/internal/parser"
 	"github.com/spf13/cobra"
 )
 
 var debugCmd = &cobra.Command{
 	Use:   "debug",
-	Short: "Dump lexer tokens or parser AST for a schema",
+	Short: "Dump lexer tokens, parser AST or JSON IR for a schema",
 	RunE:  RunDebugCmd,
 }
 
 var (
-	debugStage string
+	debugStage  string
+	debugFormat string
+	debugPrefix string
 )
 
 func init() {
 	rootCmd.AddCommand(debugCmd)
-	debugCmd.Flags().StringVar(&debugStage, "stage", "tokens", "Debug stage: tokens or ast")
+	debugCmd.Flags().StringVar(&debugStage, "stage", "tokens", "Debug stage: tokens, ast or ir")
+	debugCmd.Flags().StringVar(&debugFormat, "format", "text", "Output format: text (tokens, ast) or json (ir)")
+	debugCmd.Flags().StringVar(&debugPrefix, "prefix", "rpc", "URL path prefix used for RPC paths in the ir stage")
 }
 
 func RunDebugCmd(cmd *cobra.Command, args []string) error {
@@ -35,8 +38,25 @@ func RunDebugCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("read schema: %w", err)
 	}
 
-	switch debugStage {
+	stage := debugStage
+	switch stage {
 	case "tokens", "tok", "lex", "lexer":
+		stage = "tokens"
+	case "ast", "parser":
+		stage = "ast"
+	case "ir":
+		if !cmd.Flags().Changed("format") {
+			debugFormat = "json"
+		}
+	default:
+		return fmt.Errorf("unsupported stage %q (use tokens, ast or ir)", debugStage)
+	}
+	if want := stageFormat(stage); debugFormat != want {
+		return fmt.Errorf("stage %s only supports --format %s", stage, want)
+	}
+
+	switch stage {
+	case "tokens":
 		tokens, err := lexer.NewLexer(string(data)).Tokenize()
 		if err != nil {
 			return err
@@ -49,8 +69,8 @@ func RunDebugCmd(cmd *cobra.Command, args []string) error {
 			}
 		}
 		w.Flush()
-	case "ast", "parser":
-		schema, err := parser.Parse(string(data))
+	case "ast":
+		schema, err := parseSchema(cmd, schemaPath, string(data))
 		if err != nil {
 			return err
 		}
@@ -58,8 +78,19 @@ func RunDebugCmd(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("write output: %w", err)
 		}
-	default:
-		return fmt.Errorf("unsupported stage %q (use tokens or ast)", debugStage)
+	case "ir":
+		schema, err := parseSchema(cmd, schemaPath, string(data))
+		if err != nil {
+			return err
+		}
+		return writeIR(cmd, schema, debugPrefix)
 	}
 	return nil
 }
+
+func stageFormat(stage string) string {
+	if stage == "ir" {
+		return "json"
+	}
+	return "text"
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Rapid-Vision/rRPC/internal/gen/diagram"
+	"github.com/spf13/cobra"
+)
+
+var diagramCmd = &cobra.Command{
+	Use:   "diagram [schema]",
+	Short: "Draw the models and RPCs of a schema as a Mermaid or Graphviz diagram",
+	RunE:  RunDiagramCmd,
+}
+
+var (
+	diagramOut    string
+	diagramForce  bool
+	diagramFormat string
+	diagramRPC    string
+)
+
+func init() {
+	rootCmd.AddCommand(diagramCmd)
+	diagramCmd.Flags().StringVarP(&diagramOut, "output", "o", "", "Output file (default stdout)")
+	diagramCmd.Flags().BoolVarP(&diagramForce, "force", "f", false, "Overwrite the output file if it exists")
+	diagramCmd.Flags().StringVar(&diagramFormat, "format", diagram.FormatMermaid, "Diagram format: mermaid or dot")
+	diagramCmd.Flags().StringVar(&diagramRPC, "rpc", "", "Only draw this RPC and the models reachable from it")
+}
+
+func RunDiagramCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected schema path argument")
+	}
+	schema, err := loadSchema(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	out, err := diagram.Generate(schema, diagram.Options{Format: diagramFormat, Focus: diagramRPC})
+	if err != nil {
+		return fmt.Errorf("generate diagram: %w", err)
+	}
+	if diagramOut == "" {
+		if _, err := io.WriteString(cmd.OutOrStdout(), out); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+		return nil
+	}
+	if err := checkOutputFile(diagramOut, diagramForce); err != nil {
+		return err
+	}
+	if err := os.WriteFile(diagramOut, []byte(out), 0o644); err != nil {
+		return fmt.Errorf("write diagram: %w", err)
+	}
+	return nil
+}
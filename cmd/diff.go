@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/diff"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [old] [new]",
+	Short: "Report changes between two schema versions and fail on breaking ones",
+	Long: `Report changes between two schema versions and fail on breaking ones.
+
+With --git-ref, a single schema path is compared against its version at the
+given git revision:
+
+  rrpc diff --git-ref main api.rrpc`,
+	RunE: RunDiffCmd,
+}
+
+var (
+	diffFormat string
+	diffGitRef string
+)
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVar(&diffFormat, "format", "text", "Output format: text or json")
+	diffCmd.Flags().StringVar(&diffGitRef, "git-ref", "", "Compare the schema against its version at this git revision")
+}
+
+func RunDiffCmd(cmd *cobra.Command, args []string) error {
+	if diffFormat != "text" && diffFormat != "json" {
+		return fmt.Errorf("unsupported format: %s", diffFormat)
+	}
+	var oldData, newData []byte
+	var oldName, newName string
+	var err error
+	if diffGitRef != "" {
+		if len(args) != 1 {
+			return fmt.Errorf("expected schema path argument")
+		}
+		oldName, newName = diffGitRef+":"+args[0], args[0]
+		oldData, err = readGitRevision(diffGitRef, args[0])
+		if err != nil {
+			return fmt.Errorf("read old schema: %w", err)
+		}
+		newData, err = os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("read new schema: %w", err)
+		}
+	} else {
+		if len(args) != 2 {
+			return fmt.Errorf("expected old and new schema path arguments")
+		}
+		oldName, newName = args[0], args[1]
+		oldData, err = os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("read old schema: %w", err)
+		}
+		newData, err = os.ReadFile(args[1])
+		if err != nil {
+			return fmt.Errorf("read new schema: %w", err)
+		}
+	}
+	oldSchema, oldProblems := parser.ParseWithDiagnostics(string(oldData))
+	newSchema, newProblems := parser.ParseWithDiagnostics(string(newData))
+	if len(oldProblems)+len(newProblems) > 0 {
+		problems := append(parser.Diagnostics(oldProblems).WithFile(oldName), parser.Diagnostics(newProblems).WithFile(newName)...)
+		return reportDiagnostics(cmd, problems, map[string]string{oldName: string(oldData), newName: string(newData)})
+	}
+
+	changes := diff.Compare(oldSchema, newSchema)
+	if diffFormat == "json" {
+		err = diff.WriteJSON(cmd.OutOrStdout(), changes)
+	} else {
+		err = diff.WriteText(cmd.OutOrStdout(), changes)
+	}
+	if err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	if breaking := diff.Breaking(changes); breaking > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("found %d breaking change(s)", breaking)
+	}
+	return nil
+}
+
+// readGitRevision returns the contents of path at the given revision of the
+// repository containing it.
+func readGitRevision(rev, path string) ([]byte, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(abs)
+	top, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	topDir := strings.TrimSpace(string(top))
+	if resolved, err := filepath.EvalSymlinks(topDir); err == nil {
+		topDir = resolved
+	}
+	rel, err := filepath.Rel(topDir, abs)
+	if err != nil {
+		return nil, err
+	}
+	return runGit(dir, "show", rev+":"+filepath.ToSlash(rel))
+}
+
+func runGit(dir string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	git := exec.Command("git", args...)
+	git.Dir = dir
+	git.Stderr = &stderr
+	out, err := git.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
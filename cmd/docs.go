@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	docsgen "github.com/Rapid-Vision/rRPC/internal/gen/docs"
+	"github.com/Rapid-Vision/rRPC/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var docsCmd = &cobra.Command{
+	Use:   "docs [schema]",
+	Short: "Generate a static API reference site from a schema",
+	RunE:  RunDocsCmd,
+}
+
+var (
+	docsOut    string
+	docsForce  bool
+	docsFormat string
+	docsTitle  string
+	docsPrefix string
+)
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+	docsCmd.Flags().StringVarP(&docsOut, "output", "o", "site", "Output directory")
+	docsCmd.Flags().BoolVarP(&docsForce, "force", "f", false, "Overwrite files that were edited by hand or not generated by rrpc")
+	docsCmd.Flags().StringVar(&docsFormat, "format", docsgen.FormatHTML, "Site format: html or markdown")
+	docsCmd.Flags().StringVar(&docsTitle, "title", "API reference", "Site title")
+	docsCmd.Flags().StringVar(&docsPrefix, "prefix", "rpc", "URL path prefix (empty for none)")
+}
+
+func RunDocsCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected schema path argument")
+	}
+	schema, err := loadSchema(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	files, err := docsgen.Generate(schema, docsgen.Options{
+		Title:  docsTitle,
+		Prefix: docsPrefix,
+		Format: docsFormat,
+	})
+	if err != nil {
+		return fmt.Errorf("generate docs: %w", err)
+	}
+	outputDir := docsOut
+	if outputDir == "" {
+		outputDir = "."
+	}
+	return project.WriteFiles(outputDir, "docs", files, docsForce)
+}
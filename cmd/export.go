@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/gen/collection"
+	graphqlgen "github.com/Rapid-Vision/rRPC/internal/gen/graphql"
+	"github.com/Rapid-Vision/rRPC/internal/gen/override"
+	protogen "github.com/Rapid-Vision/rRPC/internal/gen/proto"
+	"github.com/Rapid-Vision/rRPC/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Convert an rRPC schema to another format",
+}
+
+var exportProtoCmd = &cobra.Command{
+	Use:   "proto [schema]",
+	Short: "Generate a proto3 file from a schema",
+	RunE:  RunExportProtoCmd,
+}
+
+var exportGraphQLCmd = &cobra.Command{
+	Use:   "graphql [schema]",
+	Short: "Generate a GraphQL schema and a Go resolver adapter from a schema",
+	RunE:  RunExportGraphQLCmd,
+}
+
+var exportCollectionCmd = &cobra.Command{
+	Use:   "collection [schema]",
+	Short: "Generate a Postman, Insomnia or .http request collection from a schema",
+	RunE:  RunExportCollectionCmd,
+}
+
+var (
+	exportProtoOut       string
+	exportProtoForce     bool
+	exportProtoLock      string
+	exportProtoPackage   string
+	exportProtoService   string
+	exportProtoGoPackage string
+
+	exportGraphQLOut          string
+	exportGraphQLForce        bool
+	exportGraphQLPackage      string
+	exportGraphQLServerImport string
+	exportGraphQLTemplates    string
+
+	exportCollectionOut     string
+	exportCollectionForce   bool
+	exportCollectionFormat  string
+	exportCollectionName    string
+	exportCollectionPrefix  string
+	exportCollectionBaseURL string
+)
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportProtoCmd)
+	exportCmd.AddCommand(exportGraphQLCmd)
+	exportCmd.AddCommand(exportCollectionCmd)
+	exportProtoCmd.Flags().StringVarP(&exportProtoOut, "output", "o", "", "Output proto file (default stdout)")
+	exportProtoCmd.Flags().BoolVarP(&exportProtoForce, "force", "f", false, "Overwrite the output file if it exists")
+	exportProtoCmd.Flags().StringVar(&exportProtoLock, "lock", "", "Field number lock file (default <output>.lock, or <schema>.proto.lock when writing to stdout)")
+	exportProtoCmd.Flags().StringVar(&exportProtoPackage, "package", "rrpc", "Proto package name")
+	exportProtoCmd.Flags().StringVar(&exportProtoService, "service", "RPCService", "Name of the service holding the RPCs")
+	exportProtoCmd.Flags().StringVar(&exportProtoGoPackage, "go-package", "", "Value of the go_package option (omitted if empty)")
+
+	exportGraphQLCmd.Flags().StringVarP(&exportGraphQLOut, "output", "o", "graphqlapi", "Output directory")
+	exportGraphQLCmd.Flags().BoolVarP(&exportGraphQLForce, "force", "f", false, "Overwrite files that were edited by hand or not generated by rrpc")
+	exportGraphQLCmd.Flags().StringVar(&exportGraphQLPackage, "package", "graphqlapi", "Go package name of the resolver adapter")
+	exportGraphQLCmd.Flags().StringVar(&exportGraphQLServerImport, "server-import", "", "Import path of the generated Go server package (the adapter is only generated when set)")
+	exportGraphQLCmd.Flags().StringVar(&exportGraphQLTemplates, "templates", "", "Directory of templates overriding the embedded ones of the Go adapter by file name")
+
+	exportCollectionCmd.Flags().StringVarP(&exportCollectionOut, "output", "o", "", "Output file (default stdout)")
+	exportCollectionCmd.Flags().BoolVarP(&exportCollectionForce, "force", "f", false, "Overwrite the output file if it exists")
+	exportCollectionCmd.Flags().StringVar(&exportCollectionFormat, "format", collection.FormatPostman, "Collection format: postman, insomnia or http")
+	exportCollectionCmd.Flags().StringVar(&exportCollectionName, "name", "", "Collection name (default the schema file name)")
+	exportCollectionCmd.Flags().StringVar(&exportCollectionPrefix, "prefix", "rpc", "URL path prefix (empty for none)")
+	exportCollectionCmd.Flags().StringVar(&exportCollectionBaseURL, "base-url", "http://localhost:8080", "Default value of the base URL variable")
+}
+
+func RunExportProtoCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected schema path argument")
+	}
+	schema, err := loadSchema(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	lockPath := exportProtoLock
+	if lockPath == "" {
+		if exportProtoOut != "" {
+			lockPath = exportProtoOut + ".lock"
+		} else {
+			lockPath = strings.TrimSuffix(args[0], filepath.Ext(args[0])) + ".proto.lock"
+		}
+	}
+	lock, err := protogen.ReadLock(lockPath)
+	if err != nil {
+		return err
+	}
+	proto, err := protogen.Generate(schema, lock, protogen.Options{
+		Package:   exportProtoPackage,
+		Service:   exportProtoService,
+		GoPackage: exportProtoGoPackage,
+	})
+	if err != nil {
+		return fmt.Errorf("generate proto: %w", err)
+	}
+	if exportProtoOut == "" {
+		if _, err := io.WriteString(cmd.OutOrStdout(), proto); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+	} else {
+		if err := checkOutputFile(exportProtoOut, exportProtoForce); err != nil {
+			return err
+		}
+		if err := os.WriteFile(exportProtoOut, []byte(proto), 0o644); err != nil {
+			return fmt.Errorf("write proto: %w", err)
+		}
+	}
+	return lock.Write(lockPath)
+}
+
+func RunExportGraphQLCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected schema path argument")
+	}
+	schema, err := loadSchema(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	var templates override.Templates
+	if exportGraphQLTemplates != "" {
+		if templates, err = override.Load(exportGraphQLTemplates); err != nil {
+			return err
+		}
+	}
+	files, err := graphqlgen.Generate(schema, graphqlgen.Options{
+		Package:      exportGraphQLPackage,
+		ServerImport: exportGraphQLServerImport,
+		Templates:    templates,
+	})
+	if err != nil {
+		return fmt.Errorf("generate graphql: %w", err)
+	}
+	outputDir := exportGraphQLOut
+	if outputDir == "" {
+		outputDir = "."
+	}
+	return project.WriteFiles(outputDir, "graphql", files, exportGraphQLForce)
+}
+
+func RunExportCollectionCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected schema path argument")
+	}
+	schema, err := loadSchema(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	name := exportCollectionName
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+	}
+	out, err := collection.Generate(schema, collection.Options{
+		Format:  exportCollectionFormat,
+		Name:    name,
+		Prefix:  exportCollectionPrefix,
+		BaseURL: exportCollectionBaseURL,
+	})
+	if err != nil {
+		return fmt.Errorf("generate collection: %w", err)
+	}
+	if exportCollectionOut == "" {
+		if _, err := io.WriteString(cmd.OutOrStdout(), out); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+		return nil
+	}
+	if err := checkOutputFile(exportCollectionOut, exportCollectionForce); err != nil {
+		return err
+	}
+	if err := os.WriteFile(exportCollectionOut, []byte(out), 0o644); err != nil {
+		return fmt.Errorf("write collection: %w", err)
+	}
+	return nil
+}
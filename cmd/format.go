@@ -6,7 +6,6 @@ import (
 	"os"
 
 	"github.com/Rapid-Vision/rRPC/internal/formatter"
-	"github.com/Rapid-Vision/rRPC/internal/parser"
 	"github.com/spf13/cobra"
 )
 
@@ -34,7 +33,9 @@ func RunFormatCmd(cmd *cobra.Command, args []string) error {
 		data []byte
 		err  error
 	)
+	name := "<stdin>"
 	if len(args) == 1 {
+		name = args[0]
 		data, err = os.ReadFile(args[0])
 	} else {
 		data, err = io.ReadAll(os.Stdin)
@@ -43,9 +44,9 @@ func RunFormatCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("read schema: %w", err)
 	}
 
-	schema, err := parser.Parse(string(data))
+	schema, err := parseSchema(cmd, name, string(data))
 	if err != nil {
-		return fmt.Errorf("parse schema: %w", err)
+		return err
 	}
 
 	formatted, err := formatter.FormatSchema(schema)
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Rapid-Vision/rRPC/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate all targets described in rrpc.yaml",
+	Long: `Generate all targets described in a project config file.
+
+Every target is generated even when an earlier one fails; failures are
+reported per target and make the command exit non-zero.`,
+	Args: cobra.NoArgs,
+	RunE: RunGenerateCmd,
+}
+
+var (
+	generateConfig  string
+	generateTargets []string
+	generateForce   bool
+)
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+	generateCmd.Flags().StringVarP(&generateConfig, "config", "c", project.FileName, "Path to the project config file")
+	generateCmd.Flags().StringArrayVarP(&generateTargets, "target", "t", nil, "Only generate the named target (repeatable)")
+	generateCmd.Flags().BoolVarP(&generateForce, "force", "f", false, "Overwrite files that were edited by hand or not generated by rrpc")
+}
+
+func RunGenerateCmd(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	cfg, err := project.Load(generateConfig)
+	if err != nil {
+		return err
+	}
+	targets, err := selectTargets(cfg, generateTargets)
+	if err != nil {
+		return err
+	}
+	schema, err := loadSchema(cmd, cfg.SchemaPath())
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, target := range targets {
+		dir := cfg.TargetDir(target)
+		files, err := project.Generate(schema, cfg.URLPrefix(), target)
+		if err == nil {
+			err = project.WriteFiles(dir, target.Generator(), files, generateForce)
+		}
+		if err != nil {
+			failed++
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s: %v\n", target, err)
+			continue
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: wrote %d file(s) to %s\n", target, len(files), dir)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d target(s) failed", failed, len(targets))
+	}
+	return nil
+}
+
+// selectTargets returns the targets of cfg whose names are listed, or all
+// targets when names is empty.
+func selectTargets(cfg *project.Config, names []string) ([]project.Target, error) {
+	if len(names) == 0 {
+		return cfg.Targets, nil
+	}
+	byName := make(map[string]project.Target, len(cfg.Targets))
+	for _, target := range cfg.Targets {
+		byName[target.String()] = target
+	}
+	targets := make([]project.Target, 0, len(names))
+	for _, name := range names {
+		target, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown target %q", name)
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/Rapid-Vision/rRPC/internal/importer"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Convert a schema from another format to rRPC",
+}
+
+var importOpenAPICmd = &cobra.Command{
+	Use:   "openapi [spec]",
+	Short: "Convert an OpenAPI 3 document (JSON or YAML) to an rRPC schema",
+	RunE:  RunImportOpenAPICmd,
+}
+
+var importProtoCmd = &cobra.Command{
+	Use:   "proto [file]",
+	Short: "Convert a proto3 file to an rRPC schema",
+	RunE:  RunImportProtoCmd,
+}
+
+var (
+	importOut   string
+	importForce bool
+)
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importOpenAPICmd)
+	importCmd.AddCommand(importProtoCmd)
+	importCmd.PersistentFlags().StringVarP(&importOut, "output", "o", "", "Output schema file (default stdout)")
+	importCmd.PersistentFlags().BoolVarP(&importForce, "force", "f", false, "Overwrite the output file if it exists")
+}
+
+func RunImportOpenAPICmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected spec path argument")
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("read spec: %w", err)
+	}
+	result, err := importer.OpenAPI(data)
+	if err != nil {
+		return err
+	}
+	return writeImport(cmd, result)
+}
+
+func RunImportProtoCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected proto file argument")
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("read proto: %w", err)
+	}
+	result, err := importer.Proto(data)
+	if err != nil {
+		return err
+	}
+	return writeImport(cmd, result)
+}
+
+// writeImport writes the imported schema and reports warnings on stderr.
+func writeImport(cmd *cobra.Command, result *importer.Result) error {
+	if importOut == "" {
+		if _, err := io.WriteString(cmd.OutOrStdout(), result.Schema); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+	} else {
+		if err := checkOutputFile(importOut, importForce); err != nil {
+			return err
+		}
+		if err := os.WriteFile(importOut, []byte(result.Schema), 0o644); err != nil {
+			return fmt.Errorf("write schema: %w", err)
+		}
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s\n", warning)
+	}
+	if n := len(result.Warnings); n > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "%d warning(s), review the schema before use\n", n)
+	}
+	return nil
+}
+
+// checkOutputFile refuses to overwrite an existing output file unless force
+// is set.
+func checkOutputFile(path string, force bool) error {
+	if force {
+		return nil
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("output file exists: %s (use --force to overwrite)", path)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("stat output: %w", err)
+	}
+	return nil
+}
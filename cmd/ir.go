@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Rapid-Vision/rRPC/internal/ir"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var irCmd = &cobra.Command{
+	Use:   "ir",
+	Short: "Print the versioned JSON representation of a schema",
+	RunE:  RunIRCmd,
+}
+
+var (
+	irPrefix string
+)
+
+func init() {
+	rootCmd.AddCommand(irCmd)
+	irCmd.Flags().StringVar(&irPrefix, "prefix", "rpc", "URL path prefix used for RPC paths (empty for none)")
+}
+
+func RunIRCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected schema path argument")
+	}
+	schema, err := loadSchema(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	return writeIR(cmd, schema, irPrefix)
+}
+
+func writeIR(cmd *cobra.Command, schema *parser.Schema, prefix string) error {
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(ir.FromSchema(schema, prefix)); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Rapid-Vision/rRPC/internal/gen/jsonschema"
+	"github.com/Rapid-Vision/rRPC/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var jsonschemaCmd = &cobra.Command{
+	Use:   "jsonschema [schema]",
+	Short: "Generate JSON Schema documents for models and RPC payloads",
+	RunE:  RunJSONSchemaCmd,
+}
+
+var (
+	jsonschemaOut     string
+	jsonschemaForce   bool
+	jsonschemaBaseURL string
+)
+
+func init() {
+	rootCmd.AddCommand(jsonschemaCmd)
+	jsonschemaCmd.Flags().StringVarP(&jsonschemaOut, "output", "o", "jsonschema", "Output directory")
+	jsonschemaCmd.Flags().BoolVarP(&jsonschemaForce, "force", "f", false, "Overwrite files that were edited by hand or not generated by rrpc")
+	jsonschemaCmd.Flags().StringVar(&jsonschemaBaseURL, "base-url", "", "URL the documents are published under, used for their $id (omitted if empty)")
+}
+
+func RunJSONSchemaCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected schema path argument")
+	}
+	schema, err := loadSchema(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	files, err := jsonschema.Generate(schema, jsonschemaBaseURL)
+	if err != nil {
+		return fmt.Errorf("generate json schema: %w", err)
+	}
+	outputDir := jsonschemaOut
+	if outputDir == "" {
+		outputDir = "."
+	}
+	return project.WriteFiles(outputDir, "jsonschema", files, jsonschemaForce)
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Rapid-Vision/rRPC/internal/lint"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [schema...]",
+	Short: "Check schemas against style and API-design rules",
+	Long: `Check schemas against style and API-design rules.
+
+Rule severities can be changed with --rule name=off|info|warning|error.
+Findings on a line are suppressed by a "# rrpc:ignore rule-name" comment at
+the end of that line or on the line before it.`,
+	RunE: RunLintCmd,
+}
+
+var (
+	lintFormat    string
+	lintRules     []string
+	lintFailOn    string
+	lintListRules bool
+)
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().StringVar(&lintFormat, "format", "text", "Output format: text or json")
+	lintCmd.Flags().StringArrayVar(&lintRules, "rule", nil, "Set a rule severity as name=off|info|warning|error (repeatable)")
+	lintCmd.Flags().StringVar(&lintFailOn, "fail-on", "error", "Exit non-zero on findings at or above this severity: info, warning, error or none")
+	lintCmd.Flags().BoolVar(&lintListRules, "list-rules", false, "List available rules and exit")
+}
+
+func RunLintCmd(cmd *cobra.Command, args []string) error {
+	if lintListRules {
+		return writeLintRules(cmd)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("expected schema path argument")
+	}
+	if lintFormat != "text" && lintFormat != "json" {
+		return fmt.Errorf("unsupported format: %s", lintFormat)
+	}
+	if lintFailOn != "none" {
+		if _, err := lint.ParseSeverity(lintFailOn); err != nil || lintFailOn == string(lint.SeverityOff) {
+			return fmt.Errorf("unsupported --fail-on value: %s", lintFailOn)
+		}
+	}
+	cfg, err := parseLintRules(lintRules)
+	if err != nil {
+		return err
+	}
+
+	sources := make(map[string]string, len(args))
+	var findings []parser.Diagnostic
+	for _, path := range args {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read schema: %w", err)
+		}
+		schema, err := parseSchema(cmd, path, string(data))
+		if err != nil {
+			return err
+		}
+		sources[path] = string(data)
+		findings = append(findings, parser.Diagnostics(lint.Run(schema, string(data), cfg)).WithFile(path)...)
+	}
+
+	if lintFormat == "json" {
+		err = parser.WriteDiagnosticsJSON(cmd.OutOrStdout(), findings)
+	} else {
+		err = writeLintText(cmd, findings, sources)
+	}
+	if err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	if lintFailOn == "none" {
+		return nil
+	}
+	if failed := lint.Count(findings, parser.Severity(lintFailOn)); failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d lint finding(s) at %s severity or above", failed, lintFailOn)
+	}
+	return nil
+}
+
+func parseLintRules(values []string) (lint.Config, error) {
+	cfg := make(lint.Config, len(values))
+	for _, value := range values {
+		name, level, ok := strings.Cut(value, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --rule %q (expected name=severity)", value)
+		}
+		severity, err := lint.ParseSeverity(strings.TrimSpace(level))
+		if err != nil {
+			return nil, fmt.Errorf("invalid --rule %q: %w", value, err)
+		}
+		cfg[strings.TrimSpace(name)] = severity
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func writeLintText(cmd *cobra.Command, findings []parser.Diagnostic, sources map[string]string) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	out := cmd.OutOrStdout()
+	if err := parser.WriteDiagnosticsText(out, findings, sources); err != nil {
+		return err
+	}
+	counts := make(map[parser.Severity]int)
+	for _, finding := range findings {
+		counts[finding.Severity]++
+	}
+	_, err := fmt.Fprintf(out, "\n%d problem(s): %d error(s), %d warning(s), %d info\n",
+		len(findings), counts[parser.SeverityError], counts[parser.SeverityWarning], counts[parser.SeverityInfo])
+	return err
+}
+
+func writeLintRules(cmd *cobra.Command) error {
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	for _, rule := range lint.Rules() {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", rule.Name, rule.Severity, rule.Description); err != nil {
+			return fmt.Errorf("write output: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
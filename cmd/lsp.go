@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/Rapid-Vision/rRPC/internal/lsp"
+	"github.com/spf13/cobra"
+)
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Run the language server for .rrpc files over stdio",
+	RunE:  RunLSPCmd,
+}
+
+var (
+	lspStdio bool
+)
+
+func init() {
+	rootCmd.AddCommand(lspCmd)
+	lspCmd.Flags().BoolVar(&lspStdio, "stdio", true, "Communicate over stdin and stdout (the only supported transport)")
+}
+
+func RunLSPCmd(cmd *cobra.Command, args []string) error {
+	return lsp.NewServer(os.Stdin, os.Stdout).Run()
+}
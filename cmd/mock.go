@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Rapid-Vision/rRPC/internal/mock"
+	"github.com/spf13/cobra"
+)
+
+var mockCmd = &cobra.Command{
+	Use:   "mock [schema]",
+	Short: "Serve fake responses for every RPC in a schema",
+	RunE:  RunMockCmd,
+}
+
+var (
+	mockHost     string
+	mockPort     int
+	mockPrefix   string
+	mockSeed     int64
+	mockRandom   bool
+	mockFixtures string
+)
+
+func init() {
+	rootCmd.AddCommand(mockCmd)
+	mockCmd.Flags().StringVar(&mockHost, "host", "127.0.0.1", "Address to listen on")
+	mockCmd.Flags().IntVar(&mockPort, "port", 8080, "Port to listen on")
+	mockCmd.Flags().StringVar(&mockPrefix, "prefix", "rpc", "URL path prefix (empty for none)")
+	mockCmd.Flags().Int64Var(&mockSeed, "seed", 1, "Seed for deterministic fake data")
+	mockCmd.Flags().BoolVar(&mockRandom, "random", false, "Generate different fake data for every call")
+	mockCmd.Flags().StringVar(&mockFixtures, "fixtures", "", "JSON or YAML file with per-RPC response overrides")
+}
+
+func RunMockCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected schema path argument")
+	}
+	schema, err := loadSchema(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	opts := mock.Options{
+		Prefix: mockPrefix,
+		Seed:   mockSeed,
+		Random: mockRandom,
+	}
+	if mockRandom {
+		opts.Seed = time.Now().UnixNano()
+	}
+	if mockFixtures != "" {
+		raw, err := os.ReadFile(mockFixtures)
+		if err != nil {
+			return fmt.Errorf("read fixtures: %w", err)
+		}
+		opts.Fixtures, err = mock.ParseFixtures(mockFixtures, raw)
+		if err != nil {
+			return err
+		}
+	}
+	handler, err := mock.NewHandler(schema, opts)
+	if err != nil {
+		return fmt.Errorf("create mock server: %w", err)
+	}
+	addr := net.JoinHostPort(mockHost, strconv.Itoa(mockPort))
+	fmt.Fprintf(cmd.ErrOrStderr(), "mock server listening on http://%s\n", addr)
+	return http.ListenAndServe(addr, logRequests(cmd, handler))
+}
+
+func logRequests(cmd *cobra.Command, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		fmt.Fprintf(cmd.ErrOrStderr(), "%s %s %d\n", r.Method, r.URL.Path, rec.status)
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -2,11 +2,8 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 
-	"github.com/Rapid-Vision/rRPC/internal/gen/openapi"
-	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/project"
 	"github.com/spf13/cobra"
 )
 
@@ -17,11 +14,12 @@ var openapiCmd = &cobra.Command{
 }
 
 var (
-	openapiTitle   string
-	openapiVersion string
-	openapiOut     string
-	openapiForce   bool
-	openapiPrefix  string
+	openapiTitle     string
+	openapiVersion   string
+	openapiOut       string
+	openapiForce     bool
+	openapiPrefix    string
+	openapiTemplates string
 )
 
 func init() {
@@ -29,42 +27,31 @@ func init() {
 	openapiCmd.Flags().StringVar(&openapiTitle, "title", "rRPC API", "OpenAPI title")
 	openapiCmd.Flags().StringVar(&openapiVersion, "version", "0.1.0", "OpenAPI version")
 	openapiCmd.Flags().StringVarP(&openapiOut, "output", "o", ".", "Output base directory")
-	openapiCmd.Flags().BoolVarP(&openapiForce, "force", "f", false, "Overwrite output file if it exists")
+	openapiCmd.Flags().BoolVarP(&openapiForce, "force", "f", false, "Overwrite files that were edited by hand or not generated by rrpc")
 	openapiCmd.Flags().StringVar(&openapiPrefix, "prefix", "rpc", "URL path prefix (empty for none)")
+	openapiCmd.Flags().StringVar(&openapiTemplates, "templates", "", "Directory of templates overriding the embedded ones by file name")
 }
 
 func RunOpenAPICmd(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
 		return fmt.Errorf("expected schema path argument")
 	}
-	schemaPath := args[0]
-	data, err := os.ReadFile(schemaPath)
+	schema, err := loadSchema(cmd, args[0])
 	if err != nil {
-		return fmt.Errorf("read schema: %w", err)
+		return err
 	}
-	schema, err := parser.Parse(string(data))
-	if err != nil {
-		return fmt.Errorf("parse schema: %w", err)
+	target := project.Target{
+		Kind:      project.KindOpenAPI,
+		Options:   project.Options{Title: openapiTitle, Version: openapiVersion},
+		Templates: openapiTemplates,
 	}
-	spec, err := openapi.GenerateWithPrefix(schema, openapiTitle, openapiVersion, openapiPrefix)
+	files, err := project.Generate(schema, openapiPrefix, target)
 	if err != nil {
-		return fmt.Errorf("generate openapi: %w", err)
+		return err
 	}
 	outputDir := openapiOut
 	if outputDir == "" {
 		outputDir = "."
 	}
-	outputPath := filepath.Join(outputDir, "openapi.json")
-	if !openapiForce {
-		if _, statErr := os.Stat(outputPath); statErr == nil {
-			return fmt.Errorf("output file exists: %s (use --force to overwrite)", outputPath)
-		}
-	}
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
-		return fmt.Errorf("create output dir: %w", err)
-	}
-	if err := os.WriteFile(outputPath, []byte(spec), 0o644); err != nil {
-		return fmt.Errorf("write output: %w", err)
-	}
-	return nil
+	return project.WriteFiles(target.Dir(outputDir), target.Generator(), files, openapiForce)
 }
@@ -10,13 +10,15 @@ import (
 const version = "0.0.7"
 
 var (
-	rootVersionFlag bool
+	rootVersionFlag   bool
+	diagnosticsFormat string
 )
 
 var rootCmd = &cobra.Command{
-	Use:   "rrpc",
-	Short: "rRPC is a code generation tool for creating an RPC API from a schema",
-	RunE:  RunRootCmd,
+	Use:               "rrpc",
+	Short:             "rRPC is a code generation tool for creating an RPC API from a schema",
+	RunE:              RunRootCmd,
+	PersistentPreRunE: checkRootFlags,
 }
 
 func Execute() {
@@ -28,6 +30,14 @@ func Execute() {
 
 func init() {
 	rootCmd.Flags().BoolVarP(&rootVersionFlag, "version", "v", false, "Print version")
+	rootCmd.PersistentFlags().StringVar(&diagnosticsFormat, "diagnostics-format", "text", "Format of schema errors on stderr: text or json")
+}
+
+func checkRootFlags(cmd *cobra.Command, args []string) error {
+	if diagnosticsFormat != "text" && diagnosticsFormat != "json" {
+		return fmt.Errorf("unsupported diagnostics format: %s", diagnosticsFormat)
+	}
+	return nil
 }
 
 func RunRootCmd(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+// loadSchema reads and parses the schema at path. Problems are reported
+// through reportDiagnostics.
+func loadSchema(cmd *cobra.Command, path string) (*parser.Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read schema: %w", err)
+	}
+	return parseSchema(cmd, path, string(data))
+}
+
+func parseSchema(cmd *cobra.Command, name, text string) (*parser.Schema, error) {
+	schema, diagnostics := parser.ParseWithDiagnostics(text)
+	if len(diagnostics) > 0 {
+		return nil, reportDiagnostics(cmd, parser.Diagnostics(diagnostics).WithFile(name), map[string]string{name: text})
+	}
+	return schema, nil
+}
+
+// reportDiagnostics writes diagnostics to stderr in the format selected by
+// --diagnostics-format and returns the error the command should fail with.
+// In JSON mode the error itself is not printed, so stderr stays valid JSON.
+func reportDiagnostics(cmd *cobra.Command, diagnostics []parser.Diagnostic, sources map[string]string) error {
+	cmd.SilenceUsage = true
+	var err error
+	if diagnosticsFormat == "json" {
+		cmd.SilenceErrors = true
+		err = parser.WriteDiagnosticsJSON(cmd.ErrOrStderr(), diagnostics)
+	} else {
+		err = parser.WriteDiagnosticsText(cmd.ErrOrStderr(), diagnostics, sources)
+	}
+	if err != nil {
+		return fmt.Errorf("write diagnostics: %w", err)
+	}
+	if len(diagnostics) == 1 {
+		return fmt.Errorf("parse schema: 1 error")
+	}
+	return fmt.Errorf("parse schema: %d errors", len(diagnostics))
+}
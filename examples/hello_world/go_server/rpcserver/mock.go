@@ -0,0 +1,39 @@
+// THIS CODE IS GENERATED
+
+package rpcserver
+
+import (
+	"context"
+	"sync"
+)
+
+// MockRPCHandler is a test double for RPCHandler. Set the function field of
+// every method the test exercises; methods without one return a
+// NotImplementedError. Calls are recorded and can be inspected with the
+// <Method>Calls accessors.
+type MockRPCHandler struct {
+	HelloWorldFunc func(ctx context.Context, params HelloWorldParams) (HelloWorldResult, error)
+
+	mu              sync.Mutex
+	helloWorldCalls []HelloWorldParams
+}
+
+var _ RPCHandler = (*MockRPCHandler)(nil)
+
+func (m *MockRPCHandler) HelloWorld(ctx context.Context, params HelloWorldParams) (HelloWorldResult, error) {
+	m.mu.Lock()
+	m.helloWorldCalls = append(m.helloWorldCalls, params)
+	fn := m.HelloWorldFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return HelloWorldResult{}, NotImplementedError{Message: "MockRPCHandler.HelloWorldFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// HelloWorldCalls returns the parameters of every HelloWorld call so far.
+func (m *MockRPCHandler) HelloWorldCalls() []HelloWorldParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]HelloWorldParams(nil), m.helloWorldCalls...)
+}
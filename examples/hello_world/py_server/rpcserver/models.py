@@ -4,13 +4,17 @@ from __future__ import annotations
 
 from typing import Any, Dict, List, Optional
 
-from pydantic import BaseModel
+from pydantic import BaseModel, ConfigDict
 
 
 class GreetingMessageModel(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     message: str
 
 
 class HelloWorldParams(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     name: str
     surname: Optional[str] = None
@@ -0,0 +1,59 @@
+// THIS CODE IS GENERATED
+
+package rpcserver
+
+import (
+	"context"
+	"sync"
+)
+
+// MockRPCHandler is a test double for RPCHandler. Set the function field of
+// every method the test exercises; methods without one return a
+// NotImplementedError. Calls are recorded and can be inspected with the
+// <Method>Calls accessors.
+type MockRPCHandler struct {
+	SubmitTextFunc   func(ctx context.Context, params SubmitTextParams) (SubmitTextResult, error)
+	ComputeStatsFunc func(ctx context.Context, params ComputeStatsParams) (ComputeStatsResult, error)
+
+	mu                sync.Mutex
+	submitTextCalls   []SubmitTextParams
+	computeStatsCalls []ComputeStatsParams
+}
+
+var _ RPCHandler = (*MockRPCHandler)(nil)
+
+func (m *MockRPCHandler) SubmitText(ctx context.Context, params SubmitTextParams) (SubmitTextResult, error) {
+	m.mu.Lock()
+	m.submitTextCalls = append(m.submitTextCalls, params)
+	fn := m.SubmitTextFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return SubmitTextResult{}, NotImplementedError{Message: "MockRPCHandler.SubmitTextFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// SubmitTextCalls returns the parameters of every SubmitText call so far.
+func (m *MockRPCHandler) SubmitTextCalls() []SubmitTextParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]SubmitTextParams(nil), m.submitTextCalls...)
+}
+
+func (m *MockRPCHandler) ComputeStats(ctx context.Context, params ComputeStatsParams) (ComputeStatsResult, error) {
+	m.mu.Lock()
+	m.computeStatsCalls = append(m.computeStatsCalls, params)
+	fn := m.ComputeStatsFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return ComputeStatsResult{}, NotImplementedError{Message: "MockRPCHandler.ComputeStatsFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// ComputeStatsCalls returns the parameters of every ComputeStats call so far.
+func (m *MockRPCHandler) ComputeStatsCalls() []ComputeStatsParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ComputeStatsParams(nil), m.computeStatsCalls...)
+}
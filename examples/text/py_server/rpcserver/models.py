@@ -4,20 +4,26 @@ from __future__ import annotations
 
 from typing import Any, Dict, List, Optional
 
-from pydantic import BaseModel
+from pydantic import BaseModel, ConfigDict
 
 
 class TextModel(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     title: Optional[str] = None
     data: str
 
 
 class SliceModel(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     begin: int
     end: int
 
 
 class StatsModel(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     ascii: bool
     word_count: Dict[str, int]
     total_words: int
@@ -25,8 +31,12 @@ class StatsModel(BaseModel):
 
 
 class SubmitTextParams(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     text: TextModel
 
 
 class ComputeStatsParams(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     text_id: int
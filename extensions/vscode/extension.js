@@ -0,0 +1,57 @@
+const fs = require("fs");
+const path = require("path");
+const vscode = require("vscode");
+const { LanguageClient } = require("vscode-languageclient/node");
+
+let client;
+
+function activate(context) {
+  const config = vscode.workspace.getConfiguration("rrpc");
+  if (!config.get("languageServer.enabled", true)) {
+    return;
+  }
+  const command = config.get("languageServer.path", "") || findBinary(["rRPC", "rrpc"]);
+  const serverOptions = {
+    command,
+    args: ["lsp"],
+  };
+  const clientOptions = {
+    documentSelector: [{ scheme: "file", language: "rrpc" }],
+  };
+  client = new LanguageClient("rrpc", "rRPC Language Server", serverOptions, clientOptions);
+  context.subscriptions.push(client);
+  client.start().catch((err) => {
+    vscode.window.showErrorMessage(
+      `Failed to start the rRPC language server (${command} lsp): ${err.message}. ` +
+        "Install rRPC or set rrpc.languageServer.path."
+    );
+  });
+}
+
+// findBinary returns the first of names found on PATH, or the first name
+// when none is found so that the error message names it.
+function findBinary(names) {
+  const dirs = (process.env.PATH || "").split(path.delimiter).filter(Boolean);
+  const exts = process.platform === "win32" ? [".exe", ""] : [""];
+  for (const name of names) {
+    for (const dir of dirs) {
+      for (const ext of exts) {
+        const candidate = path.join(dir, name + ext);
+        try {
+          if (fs.statSync(candidate).isFile()) {
+            return candidate;
+          }
+        } catch {
+          // Not in this directory.
+        }
+      }
+    }
+  }
+  return names[0];
+}
+
+function deactivate() {
+  return client ? client.stop() : undefined;
+}
+
+module.exports = { activate, deactivate };
@@ -0,0 +1,83 @@
+#!/usr/bin/env python3
+
+import argparse
+import http.client
+import json
+import sys
+from pathlib import Path
+from urllib.parse import urlsplit
+
+CASES_PATH = Path(__file__).resolve().parent / "conformance" / "cases.json"
+
+
+def load_cases(path: Path) -> dict:
+    with path.open(encoding="utf-8") as f:
+        return json.load(f)
+
+
+def send(base_url: str, path: str, headers: dict[str, str], body: str | None):
+    parts = urlsplit(base_url)
+    conn = http.client.HTTPConnection(parts.hostname, parts.port or 80, timeout=5.0)
+    try:
+        payload = None if body is None else body.encode("utf-8")
+        request_headers = {"Content-Type": "application/json", **headers}
+        conn.request("POST", parts.path.rstrip("/") + path, body=payload, headers=request_headers)
+        resp = conn.getresponse()
+        return resp.status, resp.headers, resp.read()
+    finally:
+        conn.close()
+
+
+def check_case(base_url: str, prefix: str, case: dict) -> list[str]:
+    path = f"/{prefix}/{case['rpc']}" if prefix else f"/{case['rpc']}"
+    status, headers, body = send(base_url, path, case.get("headers", {}), case.get("body"))
+    expect = case["expect"]
+    failures = []
+    if status != expect["status"]:
+        failures.append(f"status: got {status}, want {expect['status']}")
+    for key, value in expect.get("headers", {}).items():
+        got = headers.get(key)
+        if got != value:
+            failures.append(f"header {key}: got {got!r}, want {value!r}")
+    if "body" in expect:
+        want = expect["body"].encode("utf-8")
+        if body != want:
+            failures.append(f"body: got {body!r}, want {want!r}")
+    if "error_type" in expect:
+        try:
+            parsed = json.loads(body.decode("utf-8"))
+        except (UnicodeDecodeError, json.JSONDecodeError):
+            parsed = None
+        if not isinstance(parsed, dict) or set(parsed) != {"type", "message"}:
+            failures.append(f"body: expected error envelope, got {body!r}")
+        elif parsed["type"] != expect["error_type"]:
+            failures.append(f"error type: got {parsed['type']!r}, want {expect['error_type']!r}")
+    return failures
+
+
+def run_conformance(base_url: str, cases_path: Path = CASES_PATH) -> bool:
+    suite = load_cases(cases_path)
+    prefix = suite.get("prefix", "").strip("/")
+    passed = True
+    for case in suite["cases"]:
+        failures = check_case(base_url, prefix, case)
+        if failures:
+            passed = False
+            print(f"FAIL {case['name']}")
+            for failure in failures:
+                print(f"    {failure}")
+        else:
+            print(f"ok   {case['name']}")
+    return passed
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(description="Check an rRPC server against protocol conformance cases")
+    parser.add_argument("--url", default="http://localhost:8080", help="Base URL of the running server")
+    parser.add_argument("--cases", default=str(CASES_PATH), help="Path to the cases file")
+    args = parser.parse_args()
+    return 0 if run_conformance(args.url, Path(args.cases)) else 1
+
+
+if __name__ == "__main__":
+    raise SystemExit(main())
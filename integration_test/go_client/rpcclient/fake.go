@@ -0,0 +1,327 @@
+// THIS CODE IS GENERATED
+
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// FakeRPCClient is a test double for RPCClientInterface. Set the function
+// field of every method the code under test calls; methods without one
+// return a NotImplementedRPCError. Calls are recorded and can be inspected
+// with the <Method>Calls accessors.
+type FakeRPCClient struct {
+	TestEmptyFunc               func(ctx context.Context, params TestEmptyParams) (EmptyModel, error)
+	TestNoReturnFunc            func(ctx context.Context, params TestNoReturnParams) error
+	TestBasicFunc               func(ctx context.Context, params TestBasicParams) (TextModel, error)
+	TestListMapFunc             func(ctx context.Context, params TestListMapParams) (NestedModel, error)
+	TestOptionalFunc            func(ctx context.Context, params TestOptionalParams) (FlagsModel, error)
+	TestValidationErrorFunc     func(ctx context.Context, params TestValidationErrorParams) (TextModel, error)
+	TestUnauthorizedErrorFunc   func(ctx context.Context, params TestUnauthorizedErrorParams) (EmptyModel, error)
+	TestForbiddenErrorFunc      func(ctx context.Context, params TestForbiddenErrorParams) (EmptyModel, error)
+	TestNotImplementedErrorFunc func(ctx context.Context, params TestNotImplementedErrorParams) (EmptyModel, error)
+	TestCustomErrorFunc         func(ctx context.Context, params TestCustomErrorParams) (EmptyModel, error)
+	TestMapReturnFunc           func(ctx context.Context, params TestMapReturnParams) (map[string]TextModel, error)
+	TestJsonFunc                func(ctx context.Context, params TestJsonParams) (any, error)
+	TestRawFunc                 func(ctx context.Context, params TestRawParams) (json.RawMessage, error)
+	TestMixedPayloadFunc        func(ctx context.Context, params TestMixedPayloadParams) (PayloadModel, error)
+
+	mu                           sync.Mutex
+	testEmptyCalls               []TestEmptyParams
+	testNoReturnCalls            []TestNoReturnParams
+	testBasicCalls               []TestBasicParams
+	testListMapCalls             []TestListMapParams
+	testOptionalCalls            []TestOptionalParams
+	testValidationErrorCalls     []TestValidationErrorParams
+	testUnauthorizedErrorCalls   []TestUnauthorizedErrorParams
+	testForbiddenErrorCalls      []TestForbiddenErrorParams
+	testNotImplementedErrorCalls []TestNotImplementedErrorParams
+	testCustomErrorCalls         []TestCustomErrorParams
+	testMapReturnCalls           []TestMapReturnParams
+	testJsonCalls                []TestJsonParams
+	testRawCalls                 []TestRawParams
+	testMixedPayloadCalls        []TestMixedPayloadParams
+}
+
+var _ RPCClientInterface = (*FakeRPCClient)(nil)
+
+func (f *FakeRPCClient) TestEmpty(ctx context.Context) (EmptyModel, error) {
+	var params TestEmptyParams
+	f.mu.Lock()
+	f.testEmptyCalls = append(f.testEmptyCalls, params)
+	fn := f.TestEmptyFunc
+	f.mu.Unlock()
+	if fn == nil {
+		var zero EmptyModel
+		return zero, fakeNotImplemented("TestEmpty")
+	}
+	return fn(ctx, params)
+}
+
+// TestEmptyCalls returns the parameters of every TestEmpty call so far.
+func (f *FakeRPCClient) TestEmptyCalls() []TestEmptyParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]TestEmptyParams(nil), f.testEmptyCalls...)
+}
+
+func (f *FakeRPCClient) TestNoReturn(ctx context.Context) error {
+	var params TestNoReturnParams
+	f.mu.Lock()
+	f.testNoReturnCalls = append(f.testNoReturnCalls, params)
+	fn := f.TestNoReturnFunc
+	f.mu.Unlock()
+	if fn == nil {
+		return fakeNotImplemented("TestNoReturn")
+	}
+	return fn(ctx, params)
+}
+
+// TestNoReturnCalls returns the parameters of every TestNoReturn call so far.
+func (f *FakeRPCClient) TestNoReturnCalls() []TestNoReturnParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]TestNoReturnParams(nil), f.testNoReturnCalls...)
+}
+
+func (f *FakeRPCClient) TestBasic(ctx context.Context, params TestBasicParams) (TextModel, error) {
+	f.mu.Lock()
+	f.testBasicCalls = append(f.testBasicCalls, params)
+	fn := f.TestBasicFunc
+	f.mu.Unlock()
+	if fn == nil {
+		var zero TextModel
+		return zero, fakeNotImplemented("TestBasic")
+	}
+	return fn(ctx, params)
+}
+
+// TestBasicCalls returns the parameters of every TestBasic call so far.
+func (f *FakeRPCClient) TestBasicCalls() []TestBasicParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]TestBasicParams(nil), f.testBasicCalls...)
+}
+
+func (f *FakeRPCClient) TestListMap(ctx context.Context, params TestListMapParams) (NestedModel, error) {
+	f.mu.Lock()
+	f.testListMapCalls = append(f.testListMapCalls, params)
+	fn := f.TestListMapFunc
+	f.mu.Unlock()
+	if fn == nil {
+		var zero NestedModel
+		return zero, fakeNotImplemented("TestListMap")
+	}
+	return fn(ctx, params)
+}
+
+// TestListMapCalls returns the parameters of every TestListMap call so far.
+func (f *FakeRPCClient) TestListMapCalls() []TestListMapParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]TestListMapParams(nil), f.testListMapCalls...)
+}
+
+func (f *FakeRPCClient) TestOptional(ctx context.Context, params TestOptionalParams) (FlagsModel, error) {
+	f.mu.Lock()
+	f.testOptionalCalls = append(f.testOptionalCalls, params)
+	fn := f.TestOptionalFunc
+	f.mu.Unlock()
+	if fn == nil {
+		var zero FlagsModel
+		return zero, fakeNotImplemented("TestOptional")
+	}
+	return fn(ctx, params)
+}
+
+// TestOptionalCalls returns the parameters of every TestOptional call so far.
+func (f *FakeRPCClient) TestOptionalCalls() []TestOptionalParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]TestOptionalParams(nil), f.testOptionalCalls...)
+}
+
+func (f *FakeRPCClient) TestValidationError(ctx context.Context, params TestValidationErrorParams) (TextModel, error) {
+	f.mu.Lock()
+	f.testValidationErrorCalls = append(f.testValidationErrorCalls, params)
+	fn := f.TestValidationErrorFunc
+	f.mu.Unlock()
+	if fn == nil {
+		var zero TextModel
+		return zero, fakeNotImplemented("TestValidationError")
+	}
+	return fn(ctx, params)
+}
+
+// TestValidationErrorCalls returns the parameters of every TestValidationError call so far.
+func (f *FakeRPCClient) TestValidationErrorCalls() []TestValidationErrorParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]TestValidationErrorParams(nil), f.testValidationErrorCalls...)
+}
+
+func (f *FakeRPCClient) TestUnauthorizedError(ctx context.Context) (EmptyModel, error) {
+	var params TestUnauthorizedErrorParams
+	f.mu.Lock()
+	f.testUnauthorizedErrorCalls = append(f.testUnauthorizedErrorCalls, params)
+	fn := f.TestUnauthorizedErrorFunc
+	f.mu.Unlock()
+	if fn == nil {
+		var zero EmptyModel
+		return zero, fakeNotImplemented("TestUnauthorizedError")
+	}
+	return fn(ctx, params)
+}
+
+// TestUnauthorizedErrorCalls returns the parameters of every TestUnauthorizedError call so far.
+func (f *FakeRPCClient) TestUnauthorizedErrorCalls() []TestUnauthorizedErrorParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]TestUnauthorizedErrorParams(nil), f.testUnauthorizedErrorCalls...)
+}
+
+func (f *FakeRPCClient) TestForbiddenError(ctx context.Context) (EmptyModel, error) {
+	var params TestForbiddenErrorParams
+	f.mu.Lock()
+	f.testForbiddenErrorCalls = append(f.testForbiddenErrorCalls, params)
+	fn := f.TestForbiddenErrorFunc
+	f.mu.Unlock()
+	if fn == nil {
+		var zero EmptyModel
+		return zero, fakeNotImplemented("TestForbiddenError")
+	}
+	return fn(ctx, params)
+}
+
+// TestForbiddenErrorCalls returns the parameters of every TestForbiddenError call so far.
+func (f *FakeRPCClient) TestForbiddenErrorCalls() []TestForbiddenErrorParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]TestForbiddenErrorParams(nil), f.testForbiddenErrorCalls...)
+}
+
+func (f *FakeRPCClient) TestNotImplementedError(ctx context.Context) (EmptyModel, error) {
+	var params TestNotImplementedErrorParams
+	f.mu.Lock()
+	f.testNotImplementedErrorCalls = append(f.testNotImplementedErrorCalls, params)
+	fn := f.TestNotImplementedErrorFunc
+	f.mu.Unlock()
+	if fn == nil {
+		var zero EmptyModel
+		return zero, fakeNotImplemented("TestNotImplementedError")
+	}
+	return fn(ctx, params)
+}
+
+// TestNotImplementedErrorCalls returns the parameters of every TestNotImplementedError call so far.
+func (f *FakeRPCClient) TestNotImplementedErrorCalls() []TestNotImplementedErrorParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]TestNotImplementedErrorParams(nil), f.testNotImplementedErrorCalls...)
+}
+
+func (f *FakeRPCClient) TestCustomError(ctx context.Context) (EmptyModel, error) {
+	var params TestCustomErrorParams
+	f.mu.Lock()
+	f.testCustomErrorCalls = append(f.testCustomErrorCalls, params)
+	fn := f.TestCustomErrorFunc
+	f.mu.Unlock()
+	if fn == nil {
+		var zero EmptyModel
+		return zero, fakeNotImplemented("TestCustomError")
+	}
+	return fn(ctx, params)
+}
+
+// TestCustomErrorCalls returns the parameters of every TestCustomError call so far.
+func (f *FakeRPCClient) TestCustomErrorCalls() []TestCustomErrorParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]TestCustomErrorParams(nil), f.testCustomErrorCalls...)
+}
+
+func (f *FakeRPCClient) TestMapReturn(ctx context.Context) (map[string]TextModel, error) {
+	var params TestMapReturnParams
+	f.mu.Lock()
+	f.testMapReturnCalls = append(f.testMapReturnCalls, params)
+	fn := f.TestMapReturnFunc
+	f.mu.Unlock()
+	if fn == nil {
+		var zero map[string]TextModel
+		return zero, fakeNotImplemented("TestMapReturn")
+	}
+	return fn(ctx, params)
+}
+
+// TestMapReturnCalls returns the parameters of every TestMapReturn call so far.
+func (f *FakeRPCClient) TestMapReturnCalls() []TestMapReturnParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]TestMapReturnParams(nil), f.testMapReturnCalls...)
+}
+
+func (f *FakeRPCClient) TestJson(ctx context.Context, params TestJsonParams) (any, error) {
+	f.mu.Lock()
+	f.testJsonCalls = append(f.testJsonCalls, params)
+	fn := f.TestJsonFunc
+	f.mu.Unlock()
+	if fn == nil {
+		var zero any
+		return zero, fakeNotImplemented("TestJson")
+	}
+	return fn(ctx, params)
+}
+
+// TestJsonCalls returns the parameters of every TestJson call so far.
+func (f *FakeRPCClient) TestJsonCalls() []TestJsonParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]TestJsonParams(nil), f.testJsonCalls...)
+}
+
+func (f *FakeRPCClient) TestRaw(ctx context.Context, params TestRawParams) (json.RawMessage, error) {
+	f.mu.Lock()
+	f.testRawCalls = append(f.testRawCalls, params)
+	fn := f.TestRawFunc
+	f.mu.Unlock()
+	if fn == nil {
+		var zero json.RawMessage
+		return zero, fakeNotImplemented("TestRaw")
+	}
+	return fn(ctx, params)
+}
+
+// TestRawCalls returns the parameters of every TestRaw call so far.
+func (f *FakeRPCClient) TestRawCalls() []TestRawParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]TestRawParams(nil), f.testRawCalls...)
+}
+
+func (f *FakeRPCClient) TestMixedPayload(ctx context.Context, params TestMixedPayloadParams) (PayloadModel, error) {
+	f.mu.Lock()
+	f.testMixedPayloadCalls = append(f.testMixedPayloadCalls, params)
+	fn := f.TestMixedPayloadFunc
+	f.mu.Unlock()
+	if fn == nil {
+		var zero PayloadModel
+		return zero, fakeNotImplemented("TestMixedPayload")
+	}
+	return fn(ctx, params)
+}
+
+// TestMixedPayloadCalls returns the parameters of every TestMixedPayload call so far.
+func (f *FakeRPCClient) TestMixedPayloadCalls() []TestMixedPayloadParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]TestMixedPayloadParams(nil), f.testMixedPayloadCalls...)
+}
+
+func fakeNotImplemented(method string) error {
+	return NotImplementedRPCError{RPCError: RPCError{
+		Type:    RPCErrorNotImplemented,
+		Message: "FakeRPCClient." + method + "Func is not set",
+	}}
+}
@@ -0,0 +1,78 @@
+// THIS CODE IS GENERATED
+
+package rpcclient
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// InMemoryTransport is an http.RoundTripper that serves requests with an
+// http.Handler in the same process, without opening a socket.
+type InMemoryTransport struct {
+	Handler http.Handler
+}
+
+func (t InMemoryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must close the request body, even on errors.
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+	serverReq := req.Clone(req.Context())
+	serverReq.RequestURI = req.URL.RequestURI()
+	if serverReq.Body == nil {
+		serverReq.Body = http.NoBody
+	}
+	w := &memoryResponseWriter{header: make(http.Header)}
+	t.Handler.ServeHTTP(w, serverReq)
+	// Handlers that write nothing reply 200, like over the network.
+	w.WriteHeader(http.StatusOK)
+	return &http.Response{
+		Status:        strconv.Itoa(w.status) + " " + http.StatusText(w.status),
+		StatusCode:    w.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        w.written,
+		Body:          io.NopCloser(bytes.NewReader(w.body.Bytes())),
+		ContentLength: int64(w.body.Len()),
+		Request:       req,
+	}, nil
+}
+
+// memoryResponseWriter buffers the response of a handler. The header is
+// copied when it is written, so later changes by the handler are ignored.
+type memoryResponseWriter struct {
+	header  http.Header
+	written http.Header
+	status  int
+	body    bytes.Buffer
+}
+
+func (w *memoryResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *memoryResponseWriter) WriteHeader(status int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = status
+	w.written = w.header.Clone()
+}
+
+func (w *memoryResponseWriter) Write(data []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(data)
+}
+
+// NewInMemoryRPCClient returns a client whose requests are served by handler,
+// typically the result of the generated server's CreateHTTPHandler. Requests
+// go through the same encoding and decoding as over the network.
+func NewInMemoryRPCClient(handler http.Handler) *RPCClient {
+	return NewRPCClient("http://in-memory").WithHTTPClient(&http.Client{
+		Transport: InMemoryTransport{Handler: handler},
+	})
+}
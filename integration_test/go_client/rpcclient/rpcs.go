@@ -251,3 +251,23 @@ func (c *RPCClient) TestMixedPayload(ctx context.Context, params TestMixedPayloa
 	}
 	return res.Payload, nil
 }
+
+// RPCClientInterface is implemented by *RPCClient and *FakeRPCClient.
+type RPCClientInterface interface {
+	TestEmpty(ctx context.Context) (EmptyModel, error)
+	TestNoReturn(ctx context.Context) error
+	TestBasic(ctx context.Context, params TestBasicParams) (TextModel, error)
+	TestListMap(ctx context.Context, params TestListMapParams) (NestedModel, error)
+	TestOptional(ctx context.Context, params TestOptionalParams) (FlagsModel, error)
+	TestValidationError(ctx context.Context, params TestValidationErrorParams) (TextModel, error)
+	TestUnauthorizedError(ctx context.Context) (EmptyModel, error)
+	TestForbiddenError(ctx context.Context) (EmptyModel, error)
+	TestNotImplementedError(ctx context.Context) (EmptyModel, error)
+	TestCustomError(ctx context.Context) (EmptyModel, error)
+	TestMapReturn(ctx context.Context) (map[string]TextModel, error)
+	TestJson(ctx context.Context, params TestJsonParams) (any, error)
+	TestRaw(ctx context.Context, params TestRawParams) (json.RawMessage, error)
+	TestMixedPayload(ctx context.Context, params TestMixedPayloadParams) (PayloadModel, error)
+}
+
+var _ RPCClientInterface = (*RPCClient)(nil)
@@ -0,0 +1,299 @@
+// THIS CODE IS GENERATED
+
+package rpcserver
+
+import (
+	"context"
+	"sync"
+)
+
+// MockRPCHandler is a test double for RPCHandler. Set the function field of
+// every method the test exercises; methods without one return a
+// NotImplementedError. Calls are recorded and can be inspected with the
+// <Method>Calls accessors.
+type MockRPCHandler struct {
+	TestEmptyFunc               func(ctx context.Context, params TestEmptyParams) (TestEmptyResult, error)
+	TestNoReturnFunc            func(ctx context.Context, params TestNoReturnParams) error
+	TestBasicFunc               func(ctx context.Context, params TestBasicParams) (TestBasicResult, error)
+	TestListMapFunc             func(ctx context.Context, params TestListMapParams) (TestListMapResult, error)
+	TestOptionalFunc            func(ctx context.Context, params TestOptionalParams) (TestOptionalResult, error)
+	TestValidationErrorFunc     func(ctx context.Context, params TestValidationErrorParams) (TestValidationErrorResult, error)
+	TestUnauthorizedErrorFunc   func(ctx context.Context, params TestUnauthorizedErrorParams) (TestUnauthorizedErrorResult, error)
+	TestForbiddenErrorFunc      func(ctx context.Context, params TestForbiddenErrorParams) (TestForbiddenErrorResult, error)
+	TestNotImplementedErrorFunc func(ctx context.Context, params TestNotImplementedErrorParams) (TestNotImplementedErrorResult, error)
+	TestCustomErrorFunc         func(ctx context.Context, params TestCustomErrorParams) (TestCustomErrorResult, error)
+	TestMapReturnFunc           func(ctx context.Context, params TestMapReturnParams) (TestMapReturnResult, error)
+	TestJsonFunc                func(ctx context.Context, params TestJsonParams) (TestJsonResult, error)
+	TestRawFunc                 func(ctx context.Context, params TestRawParams) (TestRawResult, error)
+	TestMixedPayloadFunc        func(ctx context.Context, params TestMixedPayloadParams) (TestMixedPayloadResult, error)
+
+	mu                           sync.Mutex
+	testEmptyCalls               []TestEmptyParams
+	testNoReturnCalls            []TestNoReturnParams
+	testBasicCalls               []TestBasicParams
+	testListMapCalls             []TestListMapParams
+	testOptionalCalls            []TestOptionalParams
+	testValidationErrorCalls     []TestValidationErrorParams
+	testUnauthorizedErrorCalls   []TestUnauthorizedErrorParams
+	testForbiddenErrorCalls      []TestForbiddenErrorParams
+	testNotImplementedErrorCalls []TestNotImplementedErrorParams
+	testCustomErrorCalls         []TestCustomErrorParams
+	testMapReturnCalls           []TestMapReturnParams
+	testJsonCalls                []TestJsonParams
+	testRawCalls                 []TestRawParams
+	testMixedPayloadCalls        []TestMixedPayloadParams
+}
+
+var _ RPCHandler = (*MockRPCHandler)(nil)
+
+func (m *MockRPCHandler) TestEmpty(ctx context.Context, params TestEmptyParams) (TestEmptyResult, error) {
+	m.mu.Lock()
+	m.testEmptyCalls = append(m.testEmptyCalls, params)
+	fn := m.TestEmptyFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return TestEmptyResult{}, NotImplementedError{Message: "MockRPCHandler.TestEmptyFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// TestEmptyCalls returns the parameters of every TestEmpty call so far.
+func (m *MockRPCHandler) TestEmptyCalls() []TestEmptyParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TestEmptyParams(nil), m.testEmptyCalls...)
+}
+
+func (m *MockRPCHandler) TestNoReturn(ctx context.Context, params TestNoReturnParams) error {
+	m.mu.Lock()
+	m.testNoReturnCalls = append(m.testNoReturnCalls, params)
+	fn := m.TestNoReturnFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return NotImplementedError{Message: "MockRPCHandler.TestNoReturnFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// TestNoReturnCalls returns the parameters of every TestNoReturn call so far.
+func (m *MockRPCHandler) TestNoReturnCalls() []TestNoReturnParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TestNoReturnParams(nil), m.testNoReturnCalls...)
+}
+
+func (m *MockRPCHandler) TestBasic(ctx context.Context, params TestBasicParams) (TestBasicResult, error) {
+	m.mu.Lock()
+	m.testBasicCalls = append(m.testBasicCalls, params)
+	fn := m.TestBasicFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return TestBasicResult{}, NotImplementedError{Message: "MockRPCHandler.TestBasicFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// TestBasicCalls returns the parameters of every TestBasic call so far.
+func (m *MockRPCHandler) TestBasicCalls() []TestBasicParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TestBasicParams(nil), m.testBasicCalls...)
+}
+
+func (m *MockRPCHandler) TestListMap(ctx context.Context, params TestListMapParams) (TestListMapResult, error) {
+	m.mu.Lock()
+	m.testListMapCalls = append(m.testListMapCalls, params)
+	fn := m.TestListMapFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return TestListMapResult{}, NotImplementedError{Message: "MockRPCHandler.TestListMapFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// TestListMapCalls returns the parameters of every TestListMap call so far.
+func (m *MockRPCHandler) TestListMapCalls() []TestListMapParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TestListMapParams(nil), m.testListMapCalls...)
+}
+
+func (m *MockRPCHandler) TestOptional(ctx context.Context, params TestOptionalParams) (TestOptionalResult, error) {
+	m.mu.Lock()
+	m.testOptionalCalls = append(m.testOptionalCalls, params)
+	fn := m.TestOptionalFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return TestOptionalResult{}, NotImplementedError{Message: "MockRPCHandler.TestOptionalFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// TestOptionalCalls returns the parameters of every TestOptional call so far.
+func (m *MockRPCHandler) TestOptionalCalls() []TestOptionalParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TestOptionalParams(nil), m.testOptionalCalls...)
+}
+
+func (m *MockRPCHandler) TestValidationError(ctx context.Context, params TestValidationErrorParams) (TestValidationErrorResult, error) {
+	m.mu.Lock()
+	m.testValidationErrorCalls = append(m.testValidationErrorCalls, params)
+	fn := m.TestValidationErrorFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return TestValidationErrorResult{}, NotImplementedError{Message: "MockRPCHandler.TestValidationErrorFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// TestValidationErrorCalls returns the parameters of every TestValidationError call so far.
+func (m *MockRPCHandler) TestValidationErrorCalls() []TestValidationErrorParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TestValidationErrorParams(nil), m.testValidationErrorCalls...)
+}
+
+func (m *MockRPCHandler) TestUnauthorizedError(ctx context.Context, params TestUnauthorizedErrorParams) (TestUnauthorizedErrorResult, error) {
+	m.mu.Lock()
+	m.testUnauthorizedErrorCalls = append(m.testUnauthorizedErrorCalls, params)
+	fn := m.TestUnauthorizedErrorFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return TestUnauthorizedErrorResult{}, NotImplementedError{Message: "MockRPCHandler.TestUnauthorizedErrorFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// TestUnauthorizedErrorCalls returns the parameters of every TestUnauthorizedError call so far.
+func (m *MockRPCHandler) TestUnauthorizedErrorCalls() []TestUnauthorizedErrorParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TestUnauthorizedErrorParams(nil), m.testUnauthorizedErrorCalls...)
+}
+
+func (m *MockRPCHandler) TestForbiddenError(ctx context.Context, params TestForbiddenErrorParams) (TestForbiddenErrorResult, error) {
+	m.mu.Lock()
+	m.testForbiddenErrorCalls = append(m.testForbiddenErrorCalls, params)
+	fn := m.TestForbiddenErrorFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return TestForbiddenErrorResult{}, NotImplementedError{Message: "MockRPCHandler.TestForbiddenErrorFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// TestForbiddenErrorCalls returns the parameters of every TestForbiddenError call so far.
+func (m *MockRPCHandler) TestForbiddenErrorCalls() []TestForbiddenErrorParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TestForbiddenErrorParams(nil), m.testForbiddenErrorCalls...)
+}
+
+func (m *MockRPCHandler) TestNotImplementedError(ctx context.Context, params TestNotImplementedErrorParams) (TestNotImplementedErrorResult, error) {
+	m.mu.Lock()
+	m.testNotImplementedErrorCalls = append(m.testNotImplementedErrorCalls, params)
+	fn := m.TestNotImplementedErrorFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return TestNotImplementedErrorResult{}, NotImplementedError{Message: "MockRPCHandler.TestNotImplementedErrorFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// TestNotImplementedErrorCalls returns the parameters of every TestNotImplementedError call so far.
+func (m *MockRPCHandler) TestNotImplementedErrorCalls() []TestNotImplementedErrorParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TestNotImplementedErrorParams(nil), m.testNotImplementedErrorCalls...)
+}
+
+func (m *MockRPCHandler) TestCustomError(ctx context.Context, params TestCustomErrorParams) (TestCustomErrorResult, error) {
+	m.mu.Lock()
+	m.testCustomErrorCalls = append(m.testCustomErrorCalls, params)
+	fn := m.TestCustomErrorFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return TestCustomErrorResult{}, NotImplementedError{Message: "MockRPCHandler.TestCustomErrorFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// TestCustomErrorCalls returns the parameters of every TestCustomError call so far.
+func (m *MockRPCHandler) TestCustomErrorCalls() []TestCustomErrorParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TestCustomErrorParams(nil), m.testCustomErrorCalls...)
+}
+
+func (m *MockRPCHandler) TestMapReturn(ctx context.Context, params TestMapReturnParams) (TestMapReturnResult, error) {
+	m.mu.Lock()
+	m.testMapReturnCalls = append(m.testMapReturnCalls, params)
+	fn := m.TestMapReturnFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return TestMapReturnResult{}, NotImplementedError{Message: "MockRPCHandler.TestMapReturnFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// TestMapReturnCalls returns the parameters of every TestMapReturn call so far.
+func (m *MockRPCHandler) TestMapReturnCalls() []TestMapReturnParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TestMapReturnParams(nil), m.testMapReturnCalls...)
+}
+
+func (m *MockRPCHandler) TestJson(ctx context.Context, params TestJsonParams) (TestJsonResult, error) {
+	m.mu.Lock()
+	m.testJsonCalls = append(m.testJsonCalls, params)
+	fn := m.TestJsonFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return TestJsonResult{}, NotImplementedError{Message: "MockRPCHandler.TestJsonFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// TestJsonCalls returns the parameters of every TestJson call so far.
+func (m *MockRPCHandler) TestJsonCalls() []TestJsonParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TestJsonParams(nil), m.testJsonCalls...)
+}
+
+func (m *MockRPCHandler) TestRaw(ctx context.Context, params TestRawParams) (TestRawResult, error) {
+	m.mu.Lock()
+	m.testRawCalls = append(m.testRawCalls, params)
+	fn := m.TestRawFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return TestRawResult{}, NotImplementedError{Message: "MockRPCHandler.TestRawFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// TestRawCalls returns the parameters of every TestRaw call so far.
+func (m *MockRPCHandler) TestRawCalls() []TestRawParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TestRawParams(nil), m.testRawCalls...)
+}
+
+func (m *MockRPCHandler) TestMixedPayload(ctx context.Context, params TestMixedPayloadParams) (TestMixedPayloadResult, error) {
+	m.mu.Lock()
+	m.testMixedPayloadCalls = append(m.testMixedPayloadCalls, params)
+	fn := m.TestMixedPayloadFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return TestMixedPayloadResult{}, NotImplementedError{Message: "MockRPCHandler.TestMixedPayloadFunc is not set"}
+	}
+	return fn(ctx, params)
+}
+
+// TestMixedPayloadCalls returns the parameters of every TestMixedPayload call so far.
+func (m *MockRPCHandler) TestMixedPayloadCalls() []TestMixedPayloadParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]TestMixedPayloadParams(nil), m.testMixedPayloadCalls...)
+}
@@ -3,11 +3,11 @@
 from __future__ import annotations
 
 import inspect
-from typing import Any
+from typing import Any, Optional
 
 from fastapi import FastAPI
 from fastapi.exceptions import RequestValidationError
-from fastapi.responses import JSONResponse, Response
+from fastapi.responses import JSONResponse
 from pydantic import BaseModel, ValidationError
 
 from .errors import (
@@ -114,7 +114,7 @@ def create_app(handlers: RPCHandlers, prefix: str = "/rpc") -> FastAPI:
                 status_code=500,
                 content=error_payload(ERROR_TYPE_CUSTOM, str(err)),
             )
-        return Response(status_code=204)
+        return JSONResponse(content={})
     @app.post(f"{prefix}/test_basic")
     async def test_basic(params: TestBasicParams):
         try:
@@ -164,7 +164,9 @@ def create_app(handlers: RPCHandlers, prefix: str = "/rpc") -> FastAPI:
             content={"nested": _encode_payload(result)}
         )
     @app.post(f"{prefix}/test_optional")
-    async def test_optional(params: TestOptionalParams):
+    async def test_optional(params: Optional[TestOptionalParams] = None):
+        if params is None:
+            params = TestOptionalParams()
         try:
             result = handlers.test_optional(text=params.text, flag=params.flag, )
             if inspect.isawaitable(result):
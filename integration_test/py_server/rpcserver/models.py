@@ -4,19 +4,23 @@ from __future__ import annotations
 
 from typing import Any, Dict, List, Optional
 
-from pydantic import BaseModel
+from pydantic import BaseModel, ConfigDict
 
 
 class EmptyModel(BaseModel):
-    pass
+    model_config = ConfigDict(extra="forbid")
 
 
 class TextModel(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     title: Optional[str] = None
     body: str
 
 
 class FlagsModel(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     enabled: bool
     retries: int
     labels: List[str]
@@ -24,6 +28,8 @@ class FlagsModel(BaseModel):
 
 
 class NestedModel(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     text: TextModel
     flags: Optional[FlagsModel] = None
     items: List[TextModel]
@@ -31,11 +37,15 @@ class NestedModel(BaseModel):
 
 
 class PayloadModel(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     data: Any
     raw_data: Any
 
 
 class TestBasicParams(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     text: TextModel
     flag: bool
     count: int
@@ -43,26 +53,38 @@ class TestBasicParams(BaseModel):
 
 
 class TestListMapParams(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     texts: List[TextModel]
     flags: Dict[str, str]
 
 
 class TestOptionalParams(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     text: Optional[TextModel] = None
     flag: Optional[bool] = None
 
 
 class TestValidationErrorParams(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     text: TextModel
 
 
 class TestJsonParams(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     data: Any
 
 
 class TestRawParams(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     payload: Any
 
 
 class TestMixedPayloadParams(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+
     payload: PayloadModel
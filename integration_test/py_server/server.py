@@ -4,7 +4,7 @@ import json
 from typing import Any, Dict, List, Optional
 
 from fastapi import Request
-from fastapi.responses import JSONResponse, Response
+from fastapi.responses import JSONResponse
 
 from rpcserver import (
     CustomRPCError,
@@ -117,10 +117,7 @@ async def auth_middleware(request: Request, call_next):
             status_code=401,
             content={"type": "unauthorized", "message": "missing or invalid token"},
         )
-    response = await call_next(request)
-    if response.status_code == 204:
-        return Response(status_code=204)
-    return response
+    return await call_next(request)
 
 
 if __name__ == "__main__":
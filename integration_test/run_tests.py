@@ -9,6 +9,8 @@ import sys
 import time
 from pathlib import Path
 
+from conformance import run_conformance
+
 
 def run(cmd: list[str], cwd: Path) -> None:
     subprocess.run(cmd, cwd=cwd, check=True)
@@ -31,10 +33,10 @@ def parse_tests() -> set[str]:
         "--test",
         action="append",
         default=[],
-        help="Comma-separated list of test suites: go, py, ts-all, ts-bare, ts-zod",
+        help="Comma-separated list of test suites: go, py, ts-all, ts-bare, ts-zod, conformance",
     )
     args = parser.parse_args()
-    all_tests = {"go", "py", "ts-all", "ts-bare", "ts-zod"}
+    all_tests = {"go", "py", "ts-all", "ts-bare", "ts-zod", "conformance"}
     if not args.test:
         return all_tests
 
@@ -130,6 +132,7 @@ def run_with_server(
     run_ts_all: bool,
     run_ts_bare: bool,
     run_ts_zod: bool,
+    run_conf: bool,
 ) -> None:
     if server_lang == "go":
         server_cmd = ["go", "run", "."]
@@ -147,6 +150,12 @@ def run_with_server(
     server = subprocess.Popen(server_cmd, cwd=server_cwd, start_new_session=True)
     try:
         wait_for_port("127.0.0.1", 8080, timeout=5.0)
+        if run_conf:
+            print(f"Running conformance checks (server={server_lang}):")
+            if not run_conformance("http://localhost:8080"):
+                raise RuntimeError(f"conformance checks failed (server={server_lang})")
+            print("\n")
+
         if run_go:
             print(f"Running go tests (server={server_lang}):")
             run(["go", "test", "."], cwd=workdir / "go_client")
@@ -201,6 +210,7 @@ def main() -> int:
     run_ts_all = "ts-all" in selected
     run_ts_bare = run_ts_all or "ts-bare" in selected
     run_ts_zod = run_ts_all or "ts-zod" in selected
+    run_conf = "conformance" in selected
 
     root = Path(__file__).resolve().parents[1]
     workdir = Path(__file__).resolve().parent
@@ -216,6 +226,7 @@ def main() -> int:
         run_ts_all=run_ts_all,
         run_ts_bare=run_ts_bare,
         run_ts_zod=run_ts_zod,
+        run_conf=run_conf,
     )
     run_with_server(
         workdir=workdir,
@@ -225,6 +236,7 @@ def main() -> int:
         run_ts_all=run_ts_all,
         run_ts_bare=run_ts_bare,
         run_ts_zod=run_ts_zod,
+        run_conf=run_conf,
     )
     return 0
 
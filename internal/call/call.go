@@ -0,0 +1,337 @@
+// Package call sends a single RPC to a running server, building the
+// request body from command line flags named after the RPC parameters.
+package call
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Rapid-Vision/rRPC/internal/ir"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/protocol"
+)
+
+type Options struct {
+	BaseURL string
+	Prefix  string
+	Headers map[string]string
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Response is the reply of a server. Body is indented JSON; Error is set
+// when the server replied with an error envelope.
+type Response struct {
+	Status int
+	Body   []byte
+	Error  *protocol.Error
+}
+
+// FindRPC looks an RPC up by its schema name or its snake_case route name.
+func FindRPC(schema *parser.Schema, name string) (parser.RPC, error) {
+	for _, rpc := range schema.RPCs {
+		if rpc.Name == name || protocol.JSONName(rpc.Name) == name {
+			return rpc, nil
+		}
+	}
+	return parser.RPC{}, fmt.Errorf("unknown rpc %q", name)
+}
+
+// Params builds the request body of an RPC. body holds the parameters
+// given as a JSON document, if any; args then set or override single
+// values:
+//
+//	--id 42                 parameter id
+//	--text.title foo        field title of the model parameter text
+//	--meta.color red        key color of the map parameter meta
+//	--tags a --tags b       elements of a list of scalars
+//	--enabled               true, for bool parameters
+//
+// Names may be given as written in the schema or as JSON keys. Values of
+// json, raw, list, map and model types may be JSON documents, and optional
+// values other than strings may be null. The result is validated against
+// the parameters.
+func Params(rpc parser.RPC, models map[string]parser.Model, body map[string]any, args []string) (map[string]any, error) {
+	if body == nil {
+		body = map[string]any{}
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, ok := strings.CutPrefix(arg, "--")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("unexpected argument %q (expected --parameter value)", arg)
+		}
+		name, value, hasValue := strings.Cut(name, "=")
+		if !hasValue && i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+			i++
+			value, hasValue = args[i], true
+		}
+		s := setter{models: models, flag: "--" + name, value: value, hasValue: hasValue}
+		if err := s.set(body, rpc.Parameters, strings.Split(name, ".")); err != nil {
+			return nil, err
+		}
+	}
+	if err := protocol.ValidateParams(body, rpc.Parameters, models); err != nil {
+		return nil, fmt.Errorf("invalid params: %w", err)
+	}
+	return body, nil
+}
+
+type setter struct {
+	models   map[string]parser.Model
+	flag     string
+	value    string
+	hasValue bool
+}
+
+// set assigns the value at path, whose first element names one of fields.
+func (s setter) set(obj map[string]any, fields []parser.Field, path []string) error {
+	for _, field := range fields {
+		key := protocol.JSONName(field.Name)
+		if field.Name == path[0] || key == path[0] {
+			return s.setKey(obj, key, field.Type, path[1:])
+		}
+	}
+	return fmt.Errorf("%s: unknown parameter or field %q", s.flag, path[0])
+}
+
+func (s setter) setKey(obj map[string]any, key string, t parser.TypeRef, rest []string) error {
+	if len(rest) == 0 {
+		value, err := s.parse(t, obj[key])
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.flag, err)
+		}
+		obj[key] = value
+		return nil
+	}
+	child, ok := obj[key].(map[string]any)
+	if !ok {
+		child = map[string]any{}
+		obj[key] = child
+	}
+	switch {
+	case t.Kind == parser.TypeMap:
+		return s.setKey(child, rest[0], *t.Value, rest[1:])
+	case t.Kind == parser.TypeIdent && isModel(t, s.models):
+		return s.set(child, s.models[t.Name].Fields, rest)
+	default:
+		return fmt.Errorf("%s: %s has no fields", s.flag, parser.FormatType(t))
+	}
+}
+
+// parse converts the value of a flag to type t. current is the value set
+// so far, which repeated flags of scalar lists append to.
+func (s setter) parse(t parser.TypeRef, current any) (any, error) {
+	if !s.hasValue {
+		if t.Kind == parser.TypeIdent && t.Name == "bool" {
+			return true, nil
+		}
+		return nil, fmt.Errorf("missing value")
+	}
+	if s.value == "null" && t.Optional && !(t.Kind == parser.TypeIdent && t.Name == "string") {
+		return nil, nil
+	}
+	switch t.Kind {
+	case parser.TypeList:
+		if strings.HasPrefix(strings.TrimSpace(s.value), "[") {
+			return decodeJSON(s.value)
+		}
+		if t.Elem.Kind != parser.TypeIdent || isModel(*t.Elem, s.models) {
+			return nil, fmt.Errorf("expected JSON list")
+		}
+		item, err := s.parse(*t.Elem, nil)
+		if err != nil {
+			return nil, err
+		}
+		items, _ := current.([]any)
+		return append(items, item), nil
+	case parser.TypeMap:
+		return decodeJSON(s.value)
+	}
+	switch t.Name {
+	case "string":
+		return s.value, nil
+	case "int":
+		if _, err := strconv.ParseInt(s.value, 10, 64); err != nil {
+			return nil, fmt.Errorf("expected int, got %q", s.value)
+		}
+		return json.Number(s.value), nil
+	case "bool":
+		value, err := strconv.ParseBool(s.value)
+		if err != nil {
+			return nil, fmt.Errorf("expected bool, got %q", s.value)
+		}
+		return value, nil
+	default:
+		return decodeJSON(s.value)
+	}
+}
+
+func decodeJSON(value string) (any, error) {
+	decoded, err := protocol.Decode([]byte(value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return decoded, nil
+}
+
+func isModel(t parser.TypeRef, models map[string]parser.Model) bool {
+	_, ok := models[t.Name]
+	return ok
+}
+
+// Send posts params to the route of rpc. Error envelopes are returned in
+// the response; replies that are neither a result nor an envelope fail.
+func Send(ctx context.Context, rpc parser.RPC, params map[string]any, opts Options) (Response, error) {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return Response{}, fmt.Errorf("encode params: %w", err)
+	}
+	url := strings.TrimRight(opts.BaseURL, "/") + protocol.RPCPath(opts.Prefix, rpc.Name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return Response{}, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range opts.Headers {
+		req.Header.Set(key, value)
+	}
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Response{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Response{}, fmt.Errorf("read response: %w", err)
+	}
+	out := Response{Status: resp.StatusCode}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, raw, "", "  "); err != nil {
+		return Response{}, fmt.Errorf("unexpected response (status %d): %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	out.Body = append(indented.Bytes(), '\n')
+	if resp.StatusCode == http.StatusOK {
+		return out, nil
+	}
+	decoded, err := protocol.Decode(raw)
+	if err != nil {
+		return Response{}, fmt.Errorf("decode response: %w", err)
+	}
+	envelope, err := protocol.ValidateError(decoded, resp.StatusCode)
+	if err != nil {
+		return Response{}, fmt.Errorf("unexpected error response (status %d): %w", resp.StatusCode, err)
+	}
+	out.Error = &envelope
+	return out, nil
+}
+
+// List writes one line per RPC: its signature and the first line of its
+// doc comment.
+func List(w io.Writer, schema *parser.Schema) error {
+	converted := ir.FromSchema(schema, "")
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	for i, rpc := range schema.RPCs {
+		summary, _, _ := strings.Cut(converted.RPCs[i].Doc, "\n")
+		fmt.Fprintf(tw, "%s\t%s\n", signature(rpc), summary)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, trimLines(b.String()))
+	return err
+}
+
+// Describe writes the doc comment, signature and route of an RPC, and the
+// flags that set its parameters.
+func Describe(w io.Writer, schema *parser.Schema, name, prefix string) error {
+	rpc, err := FindRPC(schema, name)
+	if err != nil {
+		return err
+	}
+	var doc string
+	for _, converted := range ir.FromSchema(schema, prefix).RPCs {
+		if converted.Name == rpc.Name {
+			doc = converted.Doc
+		}
+	}
+	var b strings.Builder
+	if doc != "" {
+		for _, line := range strings.Split(doc, "\n") {
+			b.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+		}
+	}
+	b.WriteString("rpc " + signature(rpc) + "\n\n")
+	b.WriteString("POST " + protocol.RPCPath(prefix, rpc.Name) + "\n")
+	if len(rpc.Parameters) > 0 {
+		b.WriteString("\nFlags:\n")
+		tw := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+		models := protocol.ModelIndex(schema)
+		for _, param := range rpc.Parameters {
+			writeFlags(tw, models, "--"+protocol.JSONName(param.Name), param.Type, map[string]bool{})
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(w, trimLines(b.String()))
+	return err
+}
+
+// writeFlags writes the flags setting a value of type t. Models are
+// expanded into their fields, except when they contain themselves.
+func writeFlags(w io.Writer, models map[string]parser.Model, flag string, t parser.TypeRef, seen map[string]bool) {
+	switch {
+	case t.Kind == parser.TypeMap:
+		fmt.Fprintf(w, "  %s\t%s\t(JSON object)\n", flag, parser.FormatType(t))
+		writeFlags(w, models, flag+".<key>", *t.Value, seen)
+	case t.Kind == parser.TypeIdent && isModel(t, models) && !seen[t.Name]:
+		seen[t.Name] = true
+		for _, field := range models[t.Name].Fields {
+			writeFlags(w, models, flag+"."+protocol.JSONName(field.Name), field.Type, seen)
+		}
+		delete(seen, t.Name)
+	default:
+		note := ""
+		switch {
+		case t.Kind == parser.TypeList && t.Elem.Kind == parser.TypeIdent && !isModel(*t.Elem, models):
+			note = "(repeatable, or JSON list)"
+		case t.Kind == parser.TypeList || isModel(t, models) || t.Name == "json" || t.Name == "raw":
+			note = "(JSON)"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", flag, parser.FormatType(t), note)
+	}
+}
+
+// trimLines removes the padding tabwriter leaves after empty last cells.
+func trimLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func signature(rpc parser.RPC) string {
+	params := make([]string, 0, len(rpc.Parameters))
+	for _, param := range rpc.Parameters {
+		params = append(params, param.Name+": "+parser.FormatType(param.Type))
+	}
+	out := rpc.Name + "(" + strings.Join(params, ", ") + ")"
+	if rpc.HasReturn {
+		out += " " + parser.FormatType(rpc.Returns)
+	}
+	return out
+}
@@ -0,0 +1,279 @@
+package call_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Rapid-Vision/rRPC/internal/call"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/protocol"
+)
+
+func TestParams(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string?
+    body: string
+    tags: list[string]
+    meta: map[int]
+}
+
+rpc CreateText(
+    text: Text,
+    count: int?,
+    draft: bool,
+    data: json?,
+)
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := call.Params(schema.RPCs[0], protocol.ModelIndex(schema), nil, []string{
+		"--text.title", "Hello",
+		"--text.body=--body--",
+		"--text.tags", "a", "--text.tags", "b",
+		"--text.meta.x", "-1",
+		"--count", "null",
+		"--draft",
+		"--data", `{"k": [1]}`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]any{
+		"text": map[string]any{
+			"title": "Hello",
+			"body":  "--body--",
+			"tags":  []any{"a", "b"},
+			"meta":  map[string]any{"x": json.Number("-1")},
+		},
+		"count": nil,
+		"draft": true,
+		"data":  map[string]any{"k": []any{json.Number("1")}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected params:\n%#v", got)
+	}
+}
+
+func TestParamsOverrideBody(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    body: string
+    tags: list[string]
+}
+
+rpc CreateText(
+    text: Text,
+    draft: bool,
+)
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body := map[string]any{"text": map[string]any{"body": "old", "tags": []any{}}, "draft": false}
+	got, err := call.Params(schema.RPCs[0], protocol.ModelIndex(schema), body, []string{"--text.body", "new", "--text.tags", `["x"]`, "--draft", "true"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := got["text"].(map[string]any)
+	if text["body"] != "new" || !reflect.DeepEqual(text["tags"], []any{"x"}) || got["draft"] != true {
+		t.Fatalf("unexpected params: %v", got)
+	}
+}
+
+func TestParamsErrors(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    body: string
+    meta: map[int]
+}
+
+rpc CreateText(
+    text: Text,
+    count: int?,
+    draft: bool,
+    data: json?,
+)
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--nope", "1"}, `--nope: unknown parameter or field "nope"`},
+		{[]string{"--count", "x"}, `--count: expected int, got "x"`},
+		{[]string{"--count"}, "--count: missing value"},
+		{[]string{"--draft", "maybe"}, `--draft: expected bool, got "maybe"`},
+		{[]string{"--text.body.x", "1"}, "--text.body.x: string has no fields"},
+		{[]string{"--data", "{"}, "--data: invalid JSON: unexpected EOF"},
+		{[]string{"text"}, `unexpected argument "text" (expected --parameter value)`},
+		{[]string{"--draft"}, "invalid params: text: missing required field"},
+		{[]string{"--text.body", "b", "--text.meta.a", "x", "--draft"}, `--text.meta.a: expected int, got "x"`},
+	} {
+		if _, err := call.Params(schema.RPCs[0], protocol.ModelIndex(schema), nil, tc.args); err == nil || err.Error() != tc.want {
+			t.Errorf("%v: expected error %q, got %v", tc.args, tc.want, err)
+		}
+	}
+}
+
+func TestFindRPC(t *testing.T) {
+	schema, err := parser.Parse("rpc GetText()\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"GetText", "get_text"} {
+		if rpc, err := call.FindRPC(schema, name); err != nil || rpc.Name != "GetText" {
+			t.Errorf("FindRPC(%q) = %v, %v", name, rpc.Name, err)
+		}
+	}
+	if _, err := call.FindRPC(schema, "Missing"); err == nil || err.Error() != `unknown rpc "Missing"` {
+		t.Fatalf("expected unknown rpc error, got %v", err)
+	}
+}
+
+func TestSend(t *testing.T) {
+	schema, err := parser.Parse(`rpc Ping()
+
+rpc GetSecret()
+
+rpc Crash()
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var gotBody, gotAuth string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/ping", func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		gotBody, gotAuth = string(raw), r.Header.Get("Authorization")
+		w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("POST /api/get_secret", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"type":"forbidden","message":"no access"}`))
+	})
+	mux.HandleFunc("POST /api/crash", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("boom"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	opts := call.Options{BaseURL: server.URL + "/", Prefix: "api", Headers: map[string]string{"Authorization": "Bearer t"}}
+
+	resp, err := call.Send(context.Background(), schema.RPCs[0], map[string]any{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Status != 200 || resp.Error != nil || string(resp.Body) != "{}\n" || gotBody != "{}" || gotAuth != "Bearer t" {
+		t.Fatalf("unexpected response %+v (request %q, %q)", resp, gotBody, gotAuth)
+	}
+
+	resp, err = call.Send(context.Background(), schema.RPCs[1], map[string]any{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &protocol.Error{Type: protocol.ErrorTypeForbidden, Message: "no access"}
+	if !reflect.DeepEqual(resp.Error, want) || !strings.Contains(string(resp.Body), "\"message\": \"no access\"") {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+
+	if _, err := call.Send(context.Background(), schema.RPCs[2], map[string]any{}, opts); err == nil || err.Error() != "unexpected response (status 500): boom" {
+		t.Fatalf("expected unexpected response error, got %v", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string
+}
+
+# Creates a text.
+# Returns the stored text.
+rpc CreateText(text: Text, count: int?) Text
+
+rpc Ping()
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var b bytes.Buffer
+	if err := call.List(&b, schema); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `CreateText(text: Text, count: int?) Text  Creates a text.
+Ping()
+`
+	if b.String() != want {
+		t.Fatalf("unexpected list:\n%s", b.String())
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string?
+    tags: list[string]
+    meta: map[int]
+}
+
+# Creates a text.
+# Returns the stored text.
+rpc CreateText(
+    text: Text,
+    draft: bool,
+    data: json?,
+) Text
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var b bytes.Buffer
+	if err := call.Describe(&b, schema, "CreateText", "rpc"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `# Creates a text.
+# Returns the stored text.
+rpc CreateText(text: Text, draft: bool, data: json?) Text
+
+POST /rpc/create_text
+
+Flags:
+  --text.title       string?
+  --text.tags        list[string]  (repeatable, or JSON list)
+  --text.meta        map[int]      (JSON object)
+  --text.meta.<key>  int
+  --draft            bool
+  --data             json?         (JSON)
+`
+	if b.String() != want {
+		t.Fatalf("unexpected description:\n%s", b.String())
+	}
+}
+
+func TestDescribeRecursiveModel(t *testing.T) {
+	schema, err := parser.Parse(`model Node {
+    children: list[Node]
+    parent: Node?
+}
+
+rpc AddNode(node: Node)
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var b bytes.Buffer
+	if err := call.Describe(&b, schema, "AddNode", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"  --node.children  list[Node]  (JSON)\n", "  --node.parent    Node?       (JSON)\n"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("description is missing %q:\n%s", want, b.String())
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/protocol"
+)
+
+const unknownFieldName = "rrpc_conformance_unknown"
+
+type Options struct {
+	BaseURL string
+	Prefix  string
+	Headers map[string]string
+	Client  *http.Client
+}
+
+type Check struct {
+	RPC    parser.RPC
+	Name   string
+	Body   []byte
+	Expect Expectation
+}
+
+type Expectation int
+
+const (
+	// ExpectAccepted requires a success envelope or an error raised by the
+	// handler itself; an input error means the request was rejected.
+	ExpectAccepted Expectation = iota
+	// ExpectInputError requires a 400 response with an input error envelope.
+	ExpectInputError
+)
+
+type Result struct {
+	RPC    string
+	Name   string
+	Passed bool
+	Detail string
+}
+
+type response struct {
+	status      int
+	contentType string
+	body        []byte
+}
+
+func Checks(schema *parser.Schema) ([]Check, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("schema is nil")
+	}
+	models := protocol.ModelIndex(schema)
+	var checks []Check
+	for _, rpc := range schema.RPCs {
+		full, err := json.Marshal(protocol.SampleParams(rpc.Parameters, models, true))
+		if err != nil {
+			return nil, fmt.Errorf("rpc %q: encode params: %w", rpc.Name, err)
+		}
+		checks = append(checks, Check{RPC: rpc, Name: "valid", Body: full, Expect: ExpectAccepted})
+		if hasOptional(rpc.Parameters) {
+			required, err := json.Marshal(protocol.SampleParams(rpc.Parameters, models, false))
+			if err != nil {
+				return nil, fmt.Errorf("rpc %q: encode params: %w", rpc.Name, err)
+			}
+			checks = append(checks, Check{RPC: rpc, Name: "missing_optional", Body: required, Expect: ExpectAccepted})
+		}
+		if !hasRequired(rpc.Parameters) {
+			checks = append(checks, Check{RPC: rpc, Name: "empty_body", Body: nil, Expect: ExpectAccepted})
+		}
+		if len(rpc.Parameters) == 0 {
+			continue
+		}
+
+		withUnknown := protocol.SampleParams(rpc.Parameters, models, true)
+		withUnknown[unknownFieldName] = 1
+		unknown, err := json.Marshal(withUnknown)
+		if err != nil {
+			return nil, fmt.Errorf("rpc %q: encode params: %w", rpc.Name, err)
+		}
+		checks = append(checks,
+			Check{RPC: rpc, Name: "unknown_field", Body: unknown, Expect: ExpectInputError},
+			Check{RPC: rpc, Name: "malformed_json", Body: []byte(`{"`), Expect: ExpectInputError},
+			Check{RPC: rpc, Name: "non_object_body", Body: []byte(`[]`), Expect: ExpectInputError},
+		)
+		for _, param := range rpc.Parameters {
+			wrong, ok := protocol.WrongValue(param.Type)
+			if !ok {
+				continue
+			}
+			params := protocol.SampleParams(rpc.Parameters, models, true)
+			params[protocol.JSONName(param.Name)] = wrong
+			body, err := json.Marshal(params)
+			if err != nil {
+				return nil, fmt.Errorf("rpc %q: encode params: %w", rpc.Name, err)
+			}
+			checks = append(checks, Check{
+				RPC:    rpc,
+				Name:   "wrong_type:" + protocol.JSONName(param.Name),
+				Body:   body,
+				Expect: ExpectInputError,
+			})
+		}
+	}
+	return checks, nil
+}
+
+func Run(ctx context.Context, schema *parser.Schema, opts Options) ([]Result, error) {
+	checks, err := Checks(schema)
+	if err != nil {
+		return nil, err
+	}
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	models := protocol.ModelIndex(schema)
+	baseURL := strings.TrimRight(opts.BaseURL, "/")
+	results := make([]Result, 0, len(checks))
+	for _, check := range checks {
+		result := Result{RPC: check.RPC.Name, Name: check.Name}
+		resp, err := send(ctx, client, baseURL+protocol.RPCPath(opts.Prefix, check.RPC.Name), opts.Headers, check.Body)
+		if err != nil {
+			result.Detail = err.Error()
+		} else if detail, err := verify(check, resp, models); err != nil {
+			result.Detail = err.Error()
+		} else {
+			result.Passed = true
+			result.Detail = detail
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func WriteReport(w io.Writer, results []Result) (int, error) {
+	failed := 0
+	for _, result := range results {
+		status := "PASS"
+		if !result.Passed {
+			status = "FAIL"
+			failed++
+		}
+		if _, err := fmt.Fprintf(w, "%s %s %s: %s\n", status, result.RPC, result.Name, result.Detail); err != nil {
+			return failed, err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "\n%d passed, %d failed\n", len(results)-failed, failed); err != nil {
+		return failed, err
+	}
+	return failed, nil
+}
+
+func send(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte) (response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, reader)
+	if err != nil {
+		return response{}, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return response{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return response{}, fmt.Errorf("read response: %w", err)
+	}
+	return response{status: resp.StatusCode, contentType: resp.Header.Get("Content-Type"), body: raw}, nil
+}
+
+func verify(check Check, resp response, models map[string]parser.Model) (string, error) {
+	if !strings.HasPrefix(resp.contentType, "application/json") {
+		return "", fmt.Errorf("status %d: expected Content-Type application/json, got %q", resp.status, resp.contentType)
+	}
+	value, err := protocol.Decode(resp.body)
+	if err != nil {
+		return "", fmt.Errorf("status %d: response is not valid JSON: %v", resp.status, err)
+	}
+	if resp.status == http.StatusOK {
+		if check.Expect == ExpectInputError {
+			return "", fmt.Errorf("status 200: expected input error")
+		}
+		if err := protocol.ValidateResult(value, check.RPC, models); err != nil {
+			return "", fmt.Errorf("status 200: %v", err)
+		}
+		return "200", nil
+	}
+	rpcErr, err := protocol.ValidateError(value, resp.status)
+	if err != nil {
+		return "", fmt.Errorf("status %d: %v", resp.status, err)
+	}
+	detail := fmt.Sprintf("%d %s", resp.status, rpcErr.Type)
+	switch check.Expect {
+	case ExpectInputError:
+		if rpcErr.Type != protocol.ErrorTypeInput {
+			return "", fmt.Errorf("%s: expected input error", detail)
+		}
+	default:
+		if rpcErr.Type == protocol.ErrorTypeInput {
+			return "", fmt.Errorf("%s: valid request rejected: %s", detail, rpcErr.Message)
+		}
+	}
+	return detail, nil
+}
+
+func hasOptional(fields []parser.Field) bool {
+	for _, field := range fields {
+		if field.Type.Optional {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRequired(fields []parser.Field) bool {
+	for _, field := range fields {
+		if !field.Type.Optional {
+			return true
+		}
+	}
+	return false
+}
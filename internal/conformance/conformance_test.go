@@ -0,0 +1,118 @@
+package conformance_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Rapid-Vision/rRPC/internal/conformance"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/protocol"
+)
+
+// newServer returns a server that follows the protocol. When lax is set it
+// accepts unknown fields like a server without DisallowUnknownFields.
+func newServer(t *testing.T, schema *parser.Schema, lax bool) *httptest.Server {
+	t.Helper()
+	models := protocol.ModelIndex(schema)
+	mux := http.NewServeMux()
+	for _, rpc := range schema.RPCs {
+		mux.HandleFunc("POST "+protocol.RPCPath("rpc", rpc.Name), func(w http.ResponseWriter, r *http.Request) {
+			raw, _ := io.ReadAll(r.Body)
+			var value any = map[string]any{}
+			if len(bytes.TrimSpace(raw)) > 0 {
+				decoded, err := protocol.Decode(raw)
+				if err != nil {
+					writeJSON(w, 400, protocol.Error{Type: protocol.ErrorTypeInput, Message: err.Error()})
+					return
+				}
+				value = decoded
+			}
+			if obj, ok := value.(map[string]any); ok && lax {
+				delete(obj, "rrpc_conformance_unknown")
+			}
+			if err := protocol.ValidateParams(value, rpc.Parameters, models); err != nil {
+				writeJSON(w, 400, protocol.Error{Type: protocol.ErrorTypeInput, Message: err.Error()})
+				return
+			}
+			if !rpc.HasReturn {
+				writeJSON(w, 200, map[string]any{})
+				return
+			}
+			writeJSON(w, 200, map[string]any{"text": value.(map[string]any)["text"]})
+		})
+	}
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func TestRunConformingServer(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string?
+    body: string
+}
+
+rpc Echo(
+    text: Text,
+    note: string?,
+) Text
+
+rpc Ping()
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := newServer(t, schema, false)
+	results, err := conformance.Run(context.Background(), schema, conformance.Options{BaseURL: server.URL, Prefix: "rpc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) == 0 {
+		t.Fatalf("expected results")
+	}
+	for _, result := range results {
+		if !result.Passed {
+			t.Fatalf("%s %s failed: %s", result.RPC, result.Name, result.Detail)
+		}
+	}
+}
+
+func TestRunReportsUnknownFieldsAccepted(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    body: string
+}
+
+rpc Echo(text: Text) Text
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := newServer(t, schema, true)
+	results, err := conformance.Run(context.Background(), schema, conformance.Options{BaseURL: server.URL, Prefix: "rpc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out strings.Builder
+	failed, err := conformance.WriteReport(&out, results)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if failed != 1 {
+		t.Fatalf("expected 1 failure, got %d:\n%s", failed, out.String())
+	}
+	if !strings.Contains(out.String(), "FAIL Echo unknown_field: status 200: expected input error") {
+		t.Fatalf("unexpected report:\n%s", out.String())
+	}
+}
@@ -0,0 +1,332 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/protocol"
+)
+
+const (
+	RPCRemoved       = "rpc_removed"
+	RPCAdded         = "rpc_added"
+	ParamRemoved     = "param_removed"
+	ParamAdded       = "param_added"
+	ParamRenamed     = "param_renamed"
+	ParamRequired    = "param_required"
+	ParamOptional    = "param_optional"
+	ParamTypeChanged = "param_type_changed"
+	ReturnRemoved    = "return_removed"
+	ReturnAdded      = "return_added"
+	ReturnRequired   = "return_required"
+	ReturnOptional   = "return_optional"
+	ReturnChanged    = "return_type_changed"
+	ModelRemoved     = "model_removed"
+	ModelAdded       = "model_added"
+	FieldRemoved     = "field_removed"
+	FieldAdded       = "field_added"
+	FieldRenamed     = "field_renamed"
+	FieldRequired    = "field_required"
+	FieldOptional    = "field_optional"
+	FieldTypeChanged = "field_type_changed"
+)
+
+// Change is a single difference between two schema versions. Breaking
+// changes are ones that can make a client generated from the old schema
+// fail against a server built from the new one.
+type Change struct {
+	Code     string `json:"code"`
+	Breaking bool   `json:"breaking"`
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+}
+
+type direction struct {
+	input  bool
+	output bool
+}
+
+// Compare lists the changes from before to after: RPCs first, then models,
+// each in declaration order with additions last.
+func Compare(before, after *parser.Schema) []Change {
+	c := comparer{
+		usage: modelUsage(before, after),
+	}
+	c.compareRPCs(before.RPCs, after.RPCs)
+	c.compareModels(before.Models, after.Models)
+	if c.changes == nil {
+		return []Change{}
+	}
+	return c.changes
+}
+
+// Breaking counts the breaking changes.
+func Breaking(changes []Change) int {
+	count := 0
+	for _, change := range changes {
+		if change.Breaking {
+			count++
+		}
+	}
+	return count
+}
+
+type comparer struct {
+	usage   map[string]direction
+	changes []Change
+}
+
+func (c *comparer) add(code string, breaking bool, path, format string, args ...any) {
+	c.changes = append(c.changes, Change{
+		Code:     code,
+		Breaking: breaking,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *comparer) compareRPCs(before, after []parser.RPC) {
+	afterByName := make(map[string]parser.RPC, len(after))
+	for _, rpc := range after {
+		afterByName[rpc.Name] = rpc
+	}
+	beforeNames := make(map[string]struct{}, len(before))
+	for _, oldRPC := range before {
+		beforeNames[oldRPC.Name] = struct{}{}
+		path := "rpc " + oldRPC.Name
+		newRPC, ok := afterByName[oldRPC.Name]
+		if !ok {
+			c.add(RPCRemoved, true, path, "removed")
+			continue
+		}
+		c.compareFields(path, oldRPC.Parameters, newRPC.Parameters, direction{input: true}, fieldCodes{
+			noun:        "parameter",
+			removed:     ParamRemoved,
+			added:       ParamAdded,
+			renamed:     ParamRenamed,
+			required:    ParamRequired,
+			optional:    ParamOptional,
+			typeChanged: ParamTypeChanged,
+		})
+		c.compareReturn(path, oldRPC, newRPC)
+	}
+	for _, rpc := range after {
+		if _, ok := beforeNames[rpc.Name]; !ok {
+			c.add(RPCAdded, false, "rpc "+rpc.Name, "added")
+		}
+	}
+}
+
+func (c *comparer) compareReturn(path string, before, after parser.RPC) {
+	path += " result"
+	switch {
+	case !before.HasReturn && !after.HasReturn:
+	case !after.HasReturn:
+		c.add(ReturnRemoved, true, path, "removed (was %s)", parser.FormatType(before.Returns))
+	case !before.HasReturn:
+		c.add(ReturnAdded, false, path, "added: %s", parser.FormatType(after.Returns))
+	case protocol.ResultKey(before.Returns) != protocol.ResultKey(after.Returns) || !sameType(before.Returns, after.Returns):
+		c.add(ReturnChanged, true, path, "type changed from %s to %s", parser.FormatType(before.Returns), parser.FormatType(after.Returns))
+	case before.Returns.Optional && !after.Returns.Optional:
+		c.add(ReturnRequired, false, path, "changed from optional to required")
+	case !before.Returns.Optional && after.Returns.Optional:
+		c.add(ReturnOptional, true, path, "changed from required to optional")
+	}
+}
+
+func (c *comparer) compareModels(before, after []parser.Model) {
+	afterByName := make(map[string]parser.Model, len(after))
+	for _, model := range after {
+		afterByName[model.Name] = model
+	}
+	beforeNames := make(map[string]struct{}, len(before))
+	for _, oldModel := range before {
+		beforeNames[oldModel.Name] = struct{}{}
+		path := "model " + oldModel.Name
+		newModel, ok := afterByName[oldModel.Name]
+		if !ok {
+			c.add(ModelRemoved, true, path, "removed")
+			continue
+		}
+		c.compareFields(path, oldModel.Fields, newModel.Fields, c.usage[oldModel.Name], fieldCodes{
+			noun:        "field",
+			removed:     FieldRemoved,
+			added:       FieldAdded,
+			renamed:     FieldRenamed,
+			required:    FieldRequired,
+			optional:    FieldOptional,
+			typeChanged: FieldTypeChanged,
+		})
+	}
+	for _, model := range after {
+		if _, ok := beforeNames[model.Name]; !ok {
+			c.add(ModelAdded, false, "model "+model.Name, "added")
+		}
+	}
+}
+
+type fieldCodes struct {
+	noun        string
+	removed     string
+	added       string
+	renamed     string
+	required    string
+	optional    string
+	typeChanged string
+}
+
+// compareFields matches fields by wire name. A field removed at the same
+// position where a field of the same type was added is reported as a rename.
+func (c *comparer) compareFields(path string, before, after []parser.Field, dir direction, codes fieldCodes) {
+	newIndex := make(map[string]int, len(after))
+	for i, field := range after {
+		newIndex[protocol.JSONName(field.Name)] = i
+	}
+	oldIndex := make(map[string]int, len(before))
+	for i, field := range before {
+		oldIndex[protocol.JSONName(field.Name)] = i
+	}
+	renamedTo := make(map[int]struct{})
+
+	for i, oldField := range before {
+		fieldPath := path + "." + oldField.Name
+		j, ok := newIndex[protocol.JSONName(oldField.Name)]
+		if !ok {
+			if i < len(after) {
+				candidate := after[i]
+				if _, exists := oldIndex[protocol.JSONName(candidate.Name)]; !exists && parser.FormatType(candidate.Type) == parser.FormatType(oldField.Type) {
+					renamedTo[i] = struct{}{}
+					c.add(codes.renamed, true, fieldPath, "%s renamed to %q", codes.noun, candidate.Name)
+					continue
+				}
+			}
+			c.add(codes.removed, true, fieldPath, "%s removed", codes.noun)
+			continue
+		}
+		newField := after[j]
+		switch {
+		case !sameType(oldField.Type, newField.Type):
+			c.add(codes.typeChanged, true, fieldPath, "type changed from %s to %s", parser.FormatType(oldField.Type), parser.FormatType(newField.Type))
+		case oldField.Type.Optional && !newField.Type.Optional:
+			c.add(codes.required, dir.input, fieldPath, "changed from optional to required")
+		case !oldField.Type.Optional && newField.Type.Optional:
+			c.add(codes.optional, dir.output, fieldPath, "changed from required to optional")
+		}
+	}
+	for j, newField := range after {
+		if _, ok := oldIndex[protocol.JSONName(newField.Name)]; ok {
+			continue
+		}
+		if _, ok := renamedTo[j]; ok {
+			continue
+		}
+		fieldPath := path + "." + newField.Name
+		if newField.Type.Optional {
+			c.add(codes.added, false, fieldPath, "optional %s added", codes.noun)
+		} else {
+			c.add(codes.added, dir.input, fieldPath, "required %s added", codes.noun)
+		}
+	}
+}
+
+// sameType compares two types ignoring top-level optionality, which is
+// reported separately.
+func sameType(a, b parser.TypeRef) bool {
+	a.Optional = false
+	b.Optional = false
+	return parser.FormatType(a) == parser.FormatType(b)
+}
+
+// modelUsage records whether each model is reachable from RPC parameters
+// (input) or results (output) in either schema. Models not reachable from
+// any RPC are treated as both, since clients may use them directly.
+func modelUsage(schemas ...*parser.Schema) map[string]direction {
+	usage := make(map[string]direction)
+	for _, schema := range schemas {
+		models := protocol.ModelIndex(schema)
+		for _, rpc := range schema.RPCs {
+			for _, param := range rpc.Parameters {
+				markModels(param.Type, models, usage, direction{input: true})
+			}
+			if rpc.HasReturn {
+				markModels(rpc.Returns, models, usage, direction{output: true})
+			}
+		}
+	}
+	for _, schema := range schemas {
+		for _, model := range schema.Models {
+			if _, ok := usage[model.Name]; !ok {
+				usage[model.Name] = direction{input: true, output: true}
+			}
+		}
+	}
+	return usage
+}
+
+func markModels(t parser.TypeRef, models map[string]parser.Model, usage map[string]direction, dir direction) {
+	switch t.Kind {
+	case parser.TypeList:
+		if t.Elem != nil {
+			markModels(*t.Elem, models, usage, dir)
+		}
+		return
+	case parser.TypeMap:
+		if t.Value != nil {
+			markModels(*t.Value, models, usage, dir)
+		}
+		return
+	}
+	model, ok := models[t.Name]
+	if !ok {
+		return
+	}
+	current := usage[t.Name]
+	merged := direction{input: current.input || dir.input, output: current.output || dir.output}
+	if merged == current {
+		return
+	}
+	usage[t.Name] = merged
+	for _, field := range model.Fields {
+		markModels(field.Type, models, usage, dir)
+	}
+}
+
+func WriteText(w io.Writer, changes []Change) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "no changes")
+		return err
+	}
+	for _, change := range changes {
+		label := "non-breaking"
+		if change.Breaking {
+			label = "breaking"
+		}
+		if _, err := fmt.Fprintf(w, "%-12s  %s: %s\n", label, change.Path, change.Message); err != nil {
+			return err
+		}
+	}
+	breaking := Breaking(changes)
+	_, err := fmt.Fprintf(w, "\n%s, %s\n", plural(breaking, "breaking change"), plural(len(changes)-breaking, "non-breaking change"))
+	return err
+}
+
+func WriteJSON(w io.Writer, changes []Change) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Breaking bool     `json:"breaking"`
+		Changes  []Change `json:"changes"`
+	}{
+		Breaking: Breaking(changes) > 0,
+		Changes:  changes,
+	})
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,201 @@
+package diff_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Rapid-Vision/rRPC/internal/diff"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+)
+
+const baseSchema = `model Text {
+    title: string?
+    body: string
+}
+
+model Stats {
+    words: int
+}
+
+rpc GetText(
+    text_id: int,
+    lang: string?,
+) Text
+
+rpc CreateText(
+    text: Text,
+)
+
+rpc GetStats() Stats
+`
+
+func mustParse(t *testing.T, text string) *parser.Schema {
+	t.Helper()
+	schema, err := parser.Parse(text)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return schema
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     []string
+	}{
+		{
+			name: "unchanged",
+			from: "",
+			to:   "",
+			want: nil,
+		},
+		{
+			name: "rpc removed",
+			from: "\nrpc GetStats() Stats\n",
+			to:   "",
+			want: []string{"breaking rpc_removed rpc GetStats"},
+		},
+		{
+			name: "rpc and model added",
+			from: "rpc GetStats() Stats",
+			to:   "rpc GetStats() Stats\n\nmodel Extra {\n    id: int\n}\n\nrpc Ping()",
+			want: []string{"non-breaking rpc_added rpc Ping", "non-breaking model_added model Extra"},
+		},
+		{
+			name: "parameter optional to required",
+			from: "lang: string?,",
+			to:   "lang: string,",
+			want: []string{"breaking param_required rpc GetText.lang"},
+		},
+		{
+			name: "parameter required to optional",
+			from: "text_id: int,",
+			to:   "text_id: int?,",
+			want: []string{"non-breaking param_optional rpc GetText.text_id"},
+		},
+		{
+			name: "parameter type changed",
+			from: "text_id: int,",
+			to:   "text_id: string,",
+			want: []string{"breaking param_type_changed rpc GetText.text_id"},
+		},
+		{
+			name: "parameter renamed",
+			from: "lang: string?,",
+			to:   "locale: string?,",
+			want: []string{"breaking param_renamed rpc GetText.lang"},
+		},
+		{
+			name: "required parameter added",
+			from: "lang: string?,\n)",
+			to:   "lang: string?,\n    version: int,\n)",
+			want: []string{"breaking param_added rpc GetText.version"},
+		},
+		{
+			name: "optional parameter added",
+			from: "lang: string?,\n)",
+			to:   "lang: string?,\n    version: int?,\n)",
+			want: []string{"non-breaking param_added rpc GetText.version"},
+		},
+		{
+			name: "return type changed",
+			from: "rpc GetStats() Stats",
+			to:   "rpc GetStats() list[Stats]",
+			want: []string{"breaking return_type_changed rpc GetStats result"},
+		},
+		{
+			name: "return removed",
+			from: "rpc GetStats() Stats",
+			to:   "rpc GetStats()",
+			want: []string{"breaking return_removed rpc GetStats result"},
+		},
+		{
+			name: "output-only field becomes optional",
+			from: "words: int",
+			to:   "words: int?",
+			want: []string{"breaking field_optional model Stats.words"},
+		},
+		{
+			name: "input field becomes required",
+			from: "    title: string?",
+			to:   "    title: string",
+			want: []string{"breaking field_required model Text.title"},
+		},
+		{
+			name: "output-only field added",
+			from: "words: int",
+			to:   "words: int\n    lines: int",
+			want: []string{"non-breaking field_added model Stats.lines"},
+		},
+		{
+			name: "input field added",
+			from: "body: string",
+			to:   "body: string\n    author: string",
+			want: []string{"breaking field_added model Text.author"},
+		},
+		{
+			name: "field removed",
+			from: "words: int",
+			to:   "chars: string",
+			want: []string{"breaking field_removed model Stats.words", "non-breaking field_added model Stats.chars"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := mustParse(t, baseSchema)
+			after := mustParse(t, strings.Replace(baseSchema, tt.from, tt.to, 1))
+			var got []string
+			for _, change := range diff.Compare(before, after) {
+				label := "non-breaking"
+				if change.Breaking {
+					label = "breaking"
+				}
+				got = append(got, label+" "+change.Code+" "+change.Path)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Fatalf("unexpected changes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestCompareModelRemoved(t *testing.T) {
+	before := mustParse(t, baseSchema+"\nmodel Unused {\n    id: int\n}\n")
+	after := mustParse(t, baseSchema)
+	changes := diff.Compare(before, after)
+	if len(changes) != 1 || changes[0].Code != diff.ModelRemoved || !changes[0].Breaking {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	before := mustParse(t, baseSchema)
+	after := mustParse(t, strings.Replace(baseSchema, "lang: string?", "lang: string", 1))
+	var buf bytes.Buffer
+	if err := diff.WriteJSON(&buf, diff.Compare(before, after)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	var report struct {
+		Breaking bool          `json:"breaking"`
+		Changes  []diff.Change `json:"changes"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !report.Breaking || len(report.Changes) != 1 || report.Changes[0].Message != "changed from optional to required" {
+		t.Fatalf("unexpected report: %s", buf.String())
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := diff.WriteText(&buf, []diff.Change{}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if buf.String() != "no changes\n" {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
@@ -0,0 +1,329 @@
+// Package collection renders a schema as a collection of HTTP requests for
+// manual testing: a Postman or Insomnia export, or a .http file for the
+// REST clients of VS Code and JetBrains IDEs.
+package collection
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/ir"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/protocol"
+	"github.com/Rapid-Vision/rRPC/internal/utils"
+)
+
+const (
+	FormatPostman  = "postman"
+	FormatInsomnia = "insomnia"
+	FormatHTTP     = "http"
+)
+
+// FolderAnnotation puts an RPC in a named folder: "# rrpc:folder Admin".
+// Other RPCs are grouped by the model they return or, failing that, take.
+const FolderAnnotation = "folder"
+
+// defaultFolder holds RPCs that neither return nor take a model.
+const defaultFolder = "General"
+
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// Options configure the collection. Prefix is the URL path prefix of the
+// RPCs and BaseURL the default value of the base URL variable.
+type Options struct {
+	Format  string
+	Name    string
+	Prefix  string
+	BaseURL string
+}
+
+type request struct {
+	name string
+	doc  string
+	path string
+	body string
+}
+
+type folder struct {
+	name     string
+	requests []request
+}
+
+func Generate(schema *parser.Schema, opts Options) (string, error) {
+	if schema == nil {
+		return "", fmt.Errorf("schema is nil")
+	}
+	if opts.Name == "" {
+		opts.Name = "rRPC API"
+	}
+	folders, err := group(schema, ir.FromSchema(schema, opts.Prefix))
+	if err != nil {
+		return "", err
+	}
+	switch opts.Format {
+	case FormatPostman, "":
+		return postman(folders, opts)
+	case FormatInsomnia:
+		return insomnia(folders, opts)
+	case FormatHTTP:
+		return httpFile(folders, opts), nil
+	default:
+		return "", fmt.Errorf("unknown format %q (expected postman, insomnia or http)", opts.Format)
+	}
+}
+
+// group puts the RPCs into folders, in the order folders are first used.
+// Request bodies are sampled from source, of which schema is the
+// conversion.
+func group(source *parser.Schema, schema *ir.Schema) ([]folder, error) {
+	models := protocol.ModelIndex(source)
+	var folders []folder
+	index := make(map[string]int)
+	for i, rpc := range schema.RPCs {
+		body, err := json.MarshalIndent(protocol.SampleParams(source.RPCs[i].Parameters, models, true), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encode request body: %w", err)
+		}
+		name := folderOf(rpc)
+		fi, ok := index[name]
+		if !ok {
+			fi = len(folders)
+			index[name] = fi
+			folders = append(folders, folder{name: name})
+		}
+		folders[fi].requests = append(folders[fi].requests, request{
+			name: rpc.Name,
+			doc:  rpc.Doc,
+			path: rpc.Path,
+			body: string(body),
+		})
+	}
+	return folders, nil
+}
+
+// folderOf returns the folder of an RPC.
+func folderOf(rpc ir.RPC) string {
+	if name, ok := rpc.Annotations.Lookup(FolderAnnotation); ok && name != "" {
+		return name
+	}
+	if rpc.Returns != nil {
+		if model, ok := modelOf(*rpc.Returns); ok {
+			return model
+		}
+	}
+	for _, param := range rpc.Params {
+		if model, ok := modelOf(param.Type); ok {
+			return model
+		}
+	}
+	return defaultFolder
+}
+
+func modelOf(t ir.Type) (string, bool) {
+	for t.Kind == ir.KindList || t.Kind == ir.KindMap {
+		t = *t.Elem
+	}
+	return t.Name, t.Kind == ir.KindModel
+}
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanFolder   `json:"item"`
+	Auth     postmanAuth       `json:"auth"`
+	Variable []postmanVariable `json:"variable"`
+}
+
+type postmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+type postmanFolder struct {
+	Name string        `json:"name"`
+	Item []postmanItem `json:"item"`
+}
+
+type postmanItem struct {
+	Name    string         `json:"name"`
+	Request postmanRequest `json:"request"`
+}
+
+type postmanRequest struct {
+	Method      string            `json:"method"`
+	Header      []postmanVariable `json:"header"`
+	Body        postmanBody       `json:"body"`
+	URL         postmanURL        `json:"url"`
+	Description string            `json:"description,omitempty"`
+}
+
+type postmanBody struct {
+	Mode    string         `json:"mode"`
+	Raw     string         `json:"raw"`
+	Options map[string]any `json:"options"`
+}
+
+type postmanURL struct {
+	Raw  string   `json:"raw"`
+	Host []string `json:"host"`
+	Path []string `json:"path"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Bearer []postmanVariable `json:"bearer"`
+}
+
+type postmanVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Type  string `json:"type,omitempty"`
+}
+
+// postman renders a Postman v2.1 collection. The bearer token is set on
+// the collection, so every request inherits it.
+func postman(folders []folder, opts Options) (string, error) {
+	out := postmanCollection{
+		Info: postmanInfo{Name: opts.Name, Schema: postmanSchema},
+		Auth: postmanAuth{Type: "bearer", Bearer: []postmanVariable{{Key: "token", Value: "{{token}}", Type: "string"}}},
+		Variable: []postmanVariable{
+			{Key: "baseUrl", Value: opts.BaseURL, Type: "string"},
+			{Key: "token", Value: "", Type: "string"},
+		},
+	}
+	for _, f := range folders {
+		converted := postmanFolder{Name: f.name}
+		for _, r := range f.requests {
+			converted.Item = append(converted.Item, postmanItem{
+				Name: r.name,
+				Request: postmanRequest{
+					Method: "POST",
+					Header: []postmanVariable{{Key: "Content-Type", Value: "application/json"}},
+					Body: postmanBody{
+						Mode:    "raw",
+						Raw:     r.body,
+						Options: map[string]any{"raw": map[string]string{"language": "json"}},
+					},
+					URL: postmanURL{
+						Raw:  "{{baseUrl}}" + r.path,
+						Host: []string{"{{baseUrl}}"},
+						Path: strings.Split(strings.TrimPrefix(r.path, "/"), "/"),
+					},
+					Description: r.doc,
+				},
+			})
+		}
+		out.Item = append(out.Item, converted)
+	}
+	return encode(out)
+}
+
+type insomniaExport struct {
+	Type      string `json:"_type"`
+	Format    int    `json:"__export_format"`
+	Source    string `json:"__export_source"`
+	Resources []any  `json:"resources"`
+}
+
+type insomniaResource struct {
+	ID   string `json:"_id"`
+	Type string `json:"_type"`
+	// ParentID is nil only for the workspace.
+	ParentID *string `json:"parentId"`
+	Name     string  `json:"name"`
+}
+
+type insomniaEnvironment struct {
+	insomniaResource
+	Data map[string]string `json:"data"`
+}
+
+type insomniaRequest struct {
+	insomniaResource
+	Method         string            `json:"method"`
+	URL            string            `json:"url"`
+	Body           insomniaBody      `json:"body"`
+	Headers        []insomniaHeader  `json:"headers"`
+	Authentication map[string]string `json:"authentication"`
+	Description    string            `json:"description,omitempty"`
+}
+
+type insomniaBody struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type insomniaHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// insomnia renders an Insomnia v4 export with a workspace, a base
+// environment holding the variables, and one request group per folder.
+// Resource IDs are derived from names, so re-imports update requests in
+// place.
+func insomnia(folders []folder, opts Options) (string, error) {
+	workspace := "wrk_rrpc"
+	out := insomniaExport{Type: "export", Format: 4, Source: "rrpc"}
+	out.Resources = append(out.Resources,
+		insomniaResource{ID: workspace, Type: "workspace", Name: opts.Name},
+		insomniaEnvironment{
+			insomniaResource: insomniaResource{ID: "env_rrpc", Type: "environment", ParentID: &workspace, Name: "Base Environment"},
+			Data:             map[string]string{"base_url": opts.BaseURL, "token": ""},
+		},
+	)
+	for _, f := range folders {
+		group := "fld_" + utils.NewIdentifierName(f.name).SnakeCase()
+		out.Resources = append(out.Resources, insomniaResource{ID: group, Type: "request_group", ParentID: &workspace, Name: f.name})
+		for _, r := range f.requests {
+			out.Resources = append(out.Resources, insomniaRequest{
+				insomniaResource: insomniaResource{
+					ID:       "req_" + utils.NewIdentifierName(r.name).SnakeCase(),
+					Type:     "request",
+					ParentID: &group,
+					Name:     r.name,
+				},
+				Method:         "POST",
+				URL:            "{{ _.base_url }}" + r.path,
+				Body:           insomniaBody{MimeType: "application/json", Text: r.body},
+				Headers:        []insomniaHeader{{Name: "Content-Type", Value: "application/json"}},
+				Authentication: map[string]string{"type": "bearer", "token": "{{ _.token }}"},
+				Description:    r.doc,
+			})
+		}
+	}
+	return encode(out)
+}
+
+// httpFile renders a .http file. The format has no folders, so request
+// names are prefixed with theirs.
+func httpFile(folders []folder, opts Options) string {
+	var b strings.Builder
+	b.WriteString("# THIS CODE IS GENERATED\n")
+	fmt.Fprintf(&b, "# %s\n\n", opts.Name)
+	fmt.Fprintf(&b, "@baseUrl = %s\n", opts.BaseURL)
+	b.WriteString("@token = \n")
+	for _, f := range folders {
+		for _, r := range f.requests {
+			fmt.Fprintf(&b, "\n### %s / %s\n", f.name, r.name)
+			if r.doc != "" {
+				for _, line := range strings.Split(r.doc, "\n") {
+					b.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+				}
+			}
+			fmt.Fprintf(&b, "POST {{baseUrl}}%s\n", r.path)
+			b.WriteString("Content-Type: application/json\n")
+			b.WriteString("Authorization: Bearer {{token}}\n\n")
+			b.WriteString(r.body + "\n")
+		}
+	}
+	return b.String()
+}
+
+func encode(v any) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode collection: %w", err)
+	}
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,232 @@
+package collection_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/Rapid-Vision/rRPC/internal/gen/collection"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+)
+
+func TestHTTP(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string?
+    count: int
+}
+
+# Creates a text.
+rpc CreateText(
+    text: Text,
+)
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := collection.Generate(schema, collection.Options{
+		Format:  collection.FormatHTTP,
+		Name:    "Texts",
+		Prefix:  "api",
+		BaseURL: "http://localhost:9000",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `# THIS CODE IS GENERATED
+# Texts
+
+@baseUrl = http://localhost:9000
+@token = 
+
+### Text / CreateText
+# Creates a text.
+POST {{baseUrl}}/api/create_text
+Content-Type: application/json
+Authorization: Bearer {{token}}
+
+{
+  "text": {
+    "count": 1,
+    "title": "string"
+  }
+}
+`
+	if got != want {
+		t.Fatalf("unexpected http file:\n%s", got)
+	}
+}
+
+func TestFolders(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string
+}
+
+rpc ListTexts() list[Text]
+
+rpc CreateText(
+    id: int,
+    text: Text,
+)
+
+# rrpc:folder Admin
+rpc DeleteText(text: Text)
+
+rpc Ping()
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := collection.Generate(schema, collection.Options{Format: collection.FormatHTTP})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := regexp.MustCompile(`(?m)^### (.*)$`).FindAllStringSubmatch(out, -1)
+	var titles []string
+	for _, match := range got {
+		titles = append(titles, match[1])
+	}
+	want := []string{"Text / ListTexts", "Text / CreateText", "Admin / DeleteText", "General / Ping"}
+	if !reflect.DeepEqual(titles, want) {
+		t.Fatalf("unexpected requests %v", titles)
+	}
+	if regexp.MustCompile(`rrpc:`).MatchString(out) {
+		t.Fatalf("folder annotation kept in the output:\n%s", out)
+	}
+}
+
+func TestPostman(t *testing.T) {
+	schema, err := parser.Parse(`# Gets a text.
+rpc GetText(
+    id: int,
+)
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	raw, err := collection.Generate(schema, collection.Options{
+		Format:  collection.FormatPostman,
+		Name:    "Texts",
+		Prefix:  "api",
+		BaseURL: "http://localhost:9000",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out struct {
+		Info struct {
+			Name string `json:"name"`
+		} `json:"info"`
+		Item []struct {
+			Name string `json:"name"`
+			Item []struct {
+				Name    string `json:"name"`
+				Request struct {
+					Method string `json:"method"`
+					Body   struct {
+						Raw string `json:"raw"`
+					} `json:"body"`
+					URL struct {
+						Raw  string   `json:"raw"`
+						Path []string `json:"path"`
+					} `json:"url"`
+					Description string `json:"description"`
+				} `json:"request"`
+			} `json:"item"`
+		} `json:"item"`
+		Auth struct {
+			Type   string              `json:"type"`
+			Bearer []map[string]string `json:"bearer"`
+		} `json:"auth"`
+		Variable []map[string]string `json:"variable"`
+	}
+	if err := json.Unmarshal([]byte(raw), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Info.Name != "Texts" || len(out.Item) != 1 || out.Item[0].Name != "General" || len(out.Item[0].Item) != 1 {
+		t.Fatalf("unexpected collection: %+v", out)
+	}
+	get := out.Item[0].Item[0]
+	if get.Name != "GetText" || get.Request.Method != "POST" || get.Request.URL.Raw != "{{baseUrl}}/api/get_text" ||
+		!reflect.DeepEqual(get.Request.URL.Path, []string{"api", "get_text"}) || get.Request.Description != "Gets a text." {
+		t.Fatalf("unexpected request: %+v", get)
+	}
+	if get.Request.Body.Raw != "{\n  \"id\": 1\n}" {
+		t.Fatalf("unexpected body %q", get.Request.Body.Raw)
+	}
+	if out.Auth.Type != "bearer" || out.Auth.Bearer[0]["value"] != "{{token}}" {
+		t.Fatalf("unexpected auth: %+v", out.Auth)
+	}
+	if out.Variable[0]["key"] != "baseUrl" || out.Variable[0]["value"] != "http://localhost:9000" || out.Variable[1]["key"] != "token" {
+		t.Fatalf("unexpected variables: %v", out.Variable)
+	}
+}
+
+func TestInsomnia(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string
+}
+
+rpc GetText() Text
+
+rpc Ping()
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	raw, err := collection.Generate(schema, collection.Options{
+		Format:  collection.FormatInsomnia,
+		Prefix:  "api",
+		BaseURL: "http://localhost:9000",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out struct {
+		Type      string           `json:"_type"`
+		Format    int              `json:"__export_format"`
+		Resources []map[string]any `json:"resources"`
+	}
+	if err := json.Unmarshal([]byte(raw), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Type != "export" || out.Format != 4 {
+		t.Fatalf("unexpected export header: %+v", out)
+	}
+	var got [][3]any
+	for _, r := range out.Resources {
+		got = append(got, [3]any{r["_type"], r["_id"], r["parentId"]})
+	}
+	want := [][3]any{
+		{"workspace", "wrk_rrpc", nil},
+		{"environment", "env_rrpc", "wrk_rrpc"},
+		{"request_group", "fld_text", "wrk_rrpc"},
+		{"request", "req_get_text", "fld_text"},
+		{"request_group", "fld_general", "wrk_rrpc"},
+		{"request", "req_ping", "fld_general"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected resources %v", got)
+	}
+	get := out.Resources[3]
+	if get["url"] != "{{ _.base_url }}/api/get_text" || get["method"] != "POST" {
+		t.Fatalf("unexpected request: %v", get)
+	}
+	if auth := get["authentication"].(map[string]any); auth["type"] != "bearer" || auth["token"] != "{{ _.token }}" {
+		t.Fatalf("unexpected authentication: %v", auth)
+	}
+	if env := out.Resources[1]["data"].(map[string]any); env["base_url"] != "http://localhost:9000" {
+		t.Fatalf("unexpected environment: %v", env)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	schema, err := parser.Parse("rpc Ping()\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `unknown format "har" (expected postman, insomnia or http)`
+	if _, err := collection.Generate(schema, collection.Options{Format: "har"}); err == nil || err.Error() != want {
+		t.Fatalf("expected error %q, got %v", want, err)
+	}
+}
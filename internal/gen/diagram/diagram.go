@@ -0,0 +1,252 @@
+// Package diagram renders the models and RPCs of a schema as a Mermaid
+// class diagram or a Graphviz graph. Models are boxes listing their fields,
+// RPCs are boxes listing their parameters, and edges follow model
+// references.
+package diagram
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/ir"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+)
+
+const (
+	FormatMermaid = "mermaid"
+	FormatDot     = "dot"
+)
+
+type Options struct {
+	Format string
+	// Focus, when set, limits the diagram to one RPC and the models
+	// reachable from its parameters and result.
+	Focus string
+}
+
+type edge struct {
+	from, to string
+	label    string
+	// param edges go from an RPC to the model of a parameter.
+	param bool
+}
+
+type graph struct {
+	models []ir.Model
+	rpcs   []ir.RPC
+	edges  []edge
+}
+
+func Generate(schema *parser.Schema, opts Options) (string, error) {
+	if schema == nil {
+		return "", fmt.Errorf("schema is nil")
+	}
+	g, err := newGraph(ir.FromSchema(schema, ""), opts.Focus)
+	if err != nil {
+		return "", err
+	}
+	switch opts.Format {
+	case FormatMermaid, "":
+		return g.mermaid(), nil
+	case FormatDot:
+		return g.dot(), nil
+	default:
+		return "", fmt.Errorf("unknown format %q", opts.Format)
+	}
+}
+
+func newGraph(schema *ir.Schema, focus string) (*graph, error) {
+	models := make(map[string]ir.Model, len(schema.Models))
+	for _, model := range schema.Models {
+		models[model.Name] = model
+	}
+	g := &graph{models: schema.Models, rpcs: schema.RPCs}
+	if focus != "" {
+		g.rpcs = nil
+		for _, rpc := range schema.RPCs {
+			if rpc.Name == focus || rpc.CodeName == focus {
+				g.rpcs = []ir.RPC{rpc}
+			}
+		}
+		if g.rpcs == nil {
+			return nil, fmt.Errorf("unknown rpc %q", focus)
+		}
+		g.models = reachable(g.rpcs[0], models, schema.Models)
+	}
+
+	for _, model := range g.models {
+		for _, field := range model.Fields {
+			if target, ok := modelOf(field.Type); ok {
+				g.edges = append(g.edges, edge{from: modelID(model.Name), to: modelID(target), label: edgeLabel(field.Name, field.Type)})
+			}
+		}
+	}
+	for _, rpc := range g.rpcs {
+		for _, param := range rpc.Params {
+			if target, ok := modelOf(param.Type); ok {
+				g.edges = append(g.edges, edge{from: rpcID(rpc.Name), to: modelID(target), label: edgeLabel(param.Name, param.Type), param: true})
+			}
+		}
+		if rpc.Returns != nil {
+			if target, ok := modelOf(*rpc.Returns); ok {
+				g.edges = append(g.edges, edge{from: rpcID(rpc.Name), to: modelID(target), label: edgeLabel("returns", *rpc.Returns)})
+			}
+		}
+	}
+	return g, nil
+}
+
+// reachable returns the models referenced by rpc, directly or through
+// other models, in schema order.
+func reachable(rpc ir.RPC, models map[string]ir.Model, order []ir.Model) []ir.Model {
+	seen := map[string]bool{}
+	var visit func(t ir.Type)
+	visit = func(t ir.Type) {
+		name, ok := modelOf(t)
+		if !ok || seen[name] {
+			return
+		}
+		seen[name] = true
+		for _, field := range models[name].Fields {
+			visit(field.Type)
+		}
+	}
+	for _, param := range rpc.Params {
+		visit(param.Type)
+	}
+	if rpc.Returns != nil {
+		visit(*rpc.Returns)
+	}
+	var out []ir.Model
+	for _, model := range order {
+		if seen[model.Name] {
+			out = append(out, model)
+		}
+	}
+	return out
+}
+
+// modelOf returns the model a type refers to, looking through lists and
+// maps.
+func modelOf(t ir.Type) (string, bool) {
+	for t.Kind == ir.KindList || t.Kind == ir.KindMap {
+		t = *t.Elem
+	}
+	return t.Name, t.Kind == ir.KindModel
+}
+
+// edgeLabel is the name of a field followed by the wrappers around the
+// referenced model, outermost first: "tags (list)", "parent (optional)".
+func edgeLabel(name string, t ir.Type) string {
+	var wrappers []string
+	for {
+		if t.Optional {
+			wrappers = append(wrappers, "optional")
+		}
+		if t.Kind != ir.KindList && t.Kind != ir.KindMap {
+			break
+		}
+		wrappers = append(wrappers, t.Kind)
+		t = *t.Elem
+	}
+	if len(wrappers) == 0 {
+		return name
+	}
+	return name + " (" + strings.Join(wrappers, ", ") + ")"
+}
+
+// typeText renders a type like the schema language, with open and close
+// around list and map elements.
+func typeText(t ir.Type, open, close string) string {
+	var out string
+	switch t.Kind {
+	case ir.KindList, ir.KindMap:
+		out = t.Kind + open + typeText(*t.Elem, open, close) + close
+	default:
+		out = t.Name
+	}
+	if t.Optional {
+		out += "?"
+	}
+	return out
+}
+
+func modelID(name string) string { return "model_" + name }
+func rpcID(name string) string   { return "rpc_" + name }
+
+// mermaid renders a class diagram. Mermaid reads square brackets in
+// members as syntax, so list and map elements use its generic notation.
+func (g *graph) mermaid() string {
+	var b strings.Builder
+	b.WriteString("%% THIS CODE IS GENERATED\n")
+	b.WriteString("classDiagram\n")
+	member := func(name string, t ir.Type) {
+		fmt.Fprintf(&b, "        %s: %s\n", name, typeText(t, "~", "~"))
+	}
+	for _, model := range g.models {
+		fmt.Fprintf(&b, "    class %s[\"%s\"] {\n", modelID(model.Name), model.Name)
+		for _, field := range model.Fields {
+			member(field.Name, field.Type)
+		}
+		b.WriteString("    }\n")
+	}
+	for _, rpc := range g.rpcs {
+		fmt.Fprintf(&b, "    class %s[\"%s\"] {\n", rpcID(rpc.Name), rpc.Name)
+		b.WriteString("        <<rpc>>\n")
+		for _, param := range rpc.Params {
+			member(param.Name, param.Type)
+		}
+		if rpc.Returns != nil {
+			member("returns", *rpc.Returns)
+		}
+		b.WriteString("    }\n")
+	}
+	for _, e := range g.edges {
+		arrow := "-->"
+		if e.param {
+			arrow = "..>"
+		}
+		fmt.Fprintf(&b, "    %s %s %s : %s\n", e.from, arrow, e.to, e.label)
+	}
+	return b.String()
+}
+
+// dot renders a Graphviz digraph with HTML-like table labels.
+func (g *graph) dot() string {
+	var b strings.Builder
+	b.WriteString("// THIS CODE IS GENERATED\n")
+	b.WriteString("digraph schema {\n")
+	b.WriteString("    rankdir=LR;\n")
+	b.WriteString("    node [shape=plain, fontname=\"Helvetica\"];\n")
+	b.WriteString("    edge [fontname=\"Helvetica\", fontsize=10];\n")
+	node := func(id, title, color string, fields []ir.Field, returns *ir.Type) {
+		fmt.Fprintf(&b, "    %s [label=<<table border=\"0\" cellborder=\"1\" cellspacing=\"0\" cellpadding=\"4\">\n", id)
+		fmt.Fprintf(&b, "        <tr><td bgcolor=\"%s\"><b>%s</b></td></tr>\n", color, html.EscapeString(title))
+		row := func(name string, t ir.Type) {
+			fmt.Fprintf(&b, "        <tr><td align=\"left\">%s: %s</td></tr>\n", html.EscapeString(name), html.EscapeString(typeText(t, "[", "]")))
+		}
+		for _, field := range fields {
+			row(field.Name, field.Type)
+		}
+		if returns != nil {
+			row("returns", *returns)
+		}
+		b.WriteString("    </table>>];\n")
+	}
+	for _, model := range g.models {
+		node(modelID(model.Name), model.Name, "lightgrey", model.Fields, nil)
+	}
+	for _, rpc := range g.rpcs {
+		node(rpcID(rpc.Name), rpc.Name+"()", "lightblue", rpc.Params, rpc.Returns)
+	}
+	for _, e := range g.edges {
+		style := ""
+		if e.param {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "    %s -> %s [label=%q%s];\n", e.from, e.to, e.label, style)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
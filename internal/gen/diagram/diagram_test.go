@@ -0,0 +1,156 @@
+package diagram_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Rapid-Vision/rRPC/internal/gen/diagram"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+)
+
+func TestMermaid(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string?
+    tag: Tag
+}
+
+model Tag {
+    name: string
+}
+
+rpc GetText(
+    id: int,
+) Text
+
+rpc CreateText(
+    text: Text,
+)
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := diagram.Generate(schema, diagram.Options{Format: diagram.FormatMermaid})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `%% THIS CODE IS GENERATED
+classDiagram
+    class model_Text["Text"] {
+        title: string?
+        tag: Tag
+    }
+    class model_Tag["Tag"] {
+        name: string
+    }
+    class rpc_GetText["GetText"] {
+        <<rpc>>
+        id: int
+        returns: Text
+    }
+    class rpc_CreateText["CreateText"] {
+        <<rpc>>
+        text: Text
+    }
+    model_Text --> model_Tag : tag
+    rpc_GetText --> model_Text : returns
+    rpc_CreateText ..> model_Text : text
+`
+	if got != want {
+		t.Fatalf("unexpected diagram:\n%s", got)
+	}
+}
+
+func TestEdgeLabels(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    tags: list[map[Tag?]]
+    parent: Text?
+}
+
+model Tag {
+    name: string
+}
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := diagram.Generate(schema, diagram.Options{Format: diagram.FormatMermaid})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"        tags: list~map~Tag?~~\n",
+		"    model_Text --> model_Tag : tags (list, map, optional)\n",
+		"    model_Text --> model_Text : parent (optional)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("diagram is missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestDotFocus(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    tag: Tag
+}
+
+model Tag {
+    name: string
+}
+
+model Other {
+    name: string
+}
+
+rpc CreateText(
+    text: Text,
+)
+
+rpc GetOther() Other
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := diagram.Generate(schema, diagram.Options{Format: diagram.FormatDot, Focus: "CreateText"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `// THIS CODE IS GENERATED
+digraph schema {
+    rankdir=LR;
+    node [shape=plain, fontname="Helvetica"];
+    edge [fontname="Helvetica", fontsize=10];
+    model_Text [label=<<table border="0" cellborder="1" cellspacing="0" cellpadding="4">
+        <tr><td bgcolor="lightgrey"><b>Text</b></td></tr>
+        <tr><td align="left">tag: Tag</td></tr>
+    </table>>];
+    model_Tag [label=<<table border="0" cellborder="1" cellspacing="0" cellpadding="4">
+        <tr><td bgcolor="lightgrey"><b>Tag</b></td></tr>
+        <tr><td align="left">name: string</td></tr>
+    </table>>];
+    rpc_CreateText [label=<<table border="0" cellborder="1" cellspacing="0" cellpadding="4">
+        <tr><td bgcolor="lightblue"><b>CreateText()</b></td></tr>
+        <tr><td align="left">text: Text</td></tr>
+    </table>>];
+    model_Text -> model_Tag [label="tag"];
+    rpc_CreateText -> model_Text [label="text", style=dashed];
+}
+`
+	if got != want {
+		t.Fatalf("unexpected diagram:\n%s", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	schema, err := parser.Parse("rpc Ping()\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for opts, want := range map[diagram.Options]string{
+		{Format: "svg"}:    `unknown format "svg"`,
+		{Focus: "Missing"}: `unknown rpc "Missing"`,
+	} {
+		if _, err := diagram.Generate(schema, opts); err == nil || err.Error() != want {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	}
+}
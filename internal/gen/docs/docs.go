@@ -0,0 +1,451 @@
+// Package docsgen renders a schema as a static API reference site, either
+// as HTML pages that work offline or as Markdown.
+package docsgen
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Rapid-Vision/rRPC/internal/ir"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/protocol"
+	"github.com/Rapid-Vision/rRPC/internal/utils"
+)
+
+//go:embed *.tmpl style.css search.js
+var templateFS embed.FS
+
+const (
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+)
+
+// Options configure the site. Prefix is the URL path prefix of the RPCs.
+type Options struct {
+	Title  string
+	Prefix string
+	Format string
+}
+
+// site is the data passed to the templates.
+type site struct {
+	Title  string
+	RPCs   []rpcPage
+	Models []modelPage
+	// Ext is the extension of page files, used in links.
+	Ext string
+}
+
+type rpcPage struct {
+	Name     string
+	File     string
+	Doc      string
+	Route    string
+	Params   []fieldRow
+	Returns  []typePart
+	Request  string
+	Response string
+	Errors   []errorRow
+	Snippets []snippet
+}
+
+type modelPage struct {
+	Name    string
+	File    string
+	Doc     string
+	Fields  []fieldRow
+	Example string
+	UsedBy  []link
+}
+
+type fieldRow struct {
+	Name     string
+	Type     []typePart
+	Optional bool
+	Doc      string
+}
+
+// typePart is a piece of a rendered type; model names link to their page.
+type typePart struct {
+	Text string
+	Link string
+}
+
+type link struct {
+	Text string
+	Link string
+}
+
+type errorRow struct {
+	Type   string
+	Status int
+	Cause  string
+}
+
+type snippet struct {
+	Lang string
+	Name string
+	Code string
+}
+
+// errorCauses explains when each error type is returned, in the order
+// errors are listed on RPC pages.
+var errorCauses = []struct {
+	typ   string
+	cause string
+}{
+	{protocol.ErrorTypeInput, "The request body is not valid JSON or does not match the parameters."},
+	{protocol.ErrorTypeValidation, "The handler rejected the parameters."},
+	{protocol.ErrorTypeUnauthorized, "Authentication is missing or invalid."},
+	{protocol.ErrorTypeForbidden, "The caller is not allowed to make the call."},
+	{protocol.ErrorTypeNotImplemented, "The server does not implement the RPC."},
+	{protocol.ErrorTypeCustom, "Any other error returned by the handler."},
+}
+
+// Generate returns the pages of the site keyed by file name.
+func Generate(schema *parser.Schema, opts Options) (map[string]string, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("schema is nil")
+	}
+	if opts.Title == "" {
+		opts.Title = "API reference"
+	}
+	ext := ".html"
+	switch opts.Format {
+	case "", FormatHTML:
+	case FormatMarkdown:
+		ext = ".md"
+	default:
+		return nil, fmt.Errorf("unknown format %q (expected html or markdown)", opts.Format)
+	}
+	data := newSite(schema, ir.FromSchema(schema, opts.Prefix), opts.Title, ext)
+	if ext == ".md" {
+		return renderMarkdown(data)
+	}
+	return renderHTML(data)
+}
+
+// newSite builds the pages of schema, the conversion of source. Examples
+// are sampled from source.
+func newSite(source *parser.Schema, schema *ir.Schema, title, ext string) site {
+	models := make(map[string]ir.Model, len(schema.Models))
+	for _, model := range schema.Models {
+		models[model.Name] = model
+	}
+	ex := &exampler{models: models}
+	samples := protocol.ModelIndex(source)
+	data := site{Title: title, Ext: ext}
+	usedBy := make(map[string][]link)
+	use := func(t ir.Type, by link) {
+		for _, name := range modelRefs(t) {
+			links := usedBy[name]
+			if len(links) == 0 || links[len(links)-1] != by {
+				usedBy[name] = append(links, by)
+			}
+		}
+	}
+
+	for i, rpc := range schema.RPCs {
+		page := rpcPage{
+			Name:   rpc.Name,
+			File:   rpcFile(rpc.Name, ext),
+			Doc:    rpc.Doc,
+			Route:  "POST " + rpc.Path,
+			Params: fieldRows(rpc.Params, ext),
+		}
+		self := link{Text: rpc.Name, Link: page.File}
+		for _, param := range rpc.Params {
+			use(param.Type, self)
+		}
+		params := ex.fields(rpc.Params, protocol.SampleParams(source.RPCs[i].Parameters, samples, true))
+		page.Request = jsonText(params)
+		var response []exampleEntry
+		if rpc.Returns != nil {
+			use(*rpc.Returns, self)
+			page.Returns = typeParts(*rpc.Returns, ext)
+			value := protocol.SampleValue(source.RPCs[i].Returns, samples)
+			response = []exampleEntry{{key: rpc.ResultKey, value: ex.value(*rpc.Returns, value)}}
+		}
+		page.Response = jsonText(response)
+		for _, e := range errorCauses {
+			if e.typ == protocol.ErrorTypeInput && len(rpc.Params) == 0 {
+				continue
+			}
+			page.Errors = append(page.Errors, errorRow{Type: e.typ, Status: protocol.ErrorStatus[e.typ], Cause: e.cause})
+		}
+		page.Snippets = snippets(rpc, params)
+		data.RPCs = append(data.RPCs, page)
+	}
+	for _, model := range schema.Models {
+		self := link{Text: model.Name, Link: modelFile(model.Name, ext)}
+		for _, field := range model.Fields {
+			use(field.Type, self)
+		}
+	}
+	for i, model := range schema.Models {
+		value, _ := protocol.SampleValue(parser.TypeRef{Kind: parser.TypeIdent, Name: source.Models[i].Name}, samples).(map[string]any)
+		data.Models = append(data.Models, modelPage{
+			Name:    model.Name,
+			File:    modelFile(model.Name, ext),
+			Doc:     model.Doc,
+			Fields:  fieldRows(model.Fields, ext),
+			Example: jsonText(ex.fields(model.Fields, value)),
+			UsedBy:  usedBy[model.Name],
+		})
+	}
+	return data
+}
+
+func rpcFile(name, ext string) string {
+	return "rpc-" + utils.NewIdentifierName(name).SnakeCase() + ext
+}
+
+func modelFile(name, ext string) string {
+	return "model-" + name + ext
+}
+
+func modelRefs(t ir.Type) []string {
+	switch t.Kind {
+	case ir.KindList, ir.KindMap:
+		return modelRefs(*t.Elem)
+	case ir.KindModel:
+		return []string{t.Name}
+	}
+	return nil
+}
+
+func fieldRows(fields []ir.Field, ext string) []fieldRow {
+	rows := make([]fieldRow, 0, len(fields))
+	for _, f := range fields {
+		rows = append(rows, fieldRow{Name: f.WireName, Type: typeParts(f.Type, ext), Optional: f.Type.Optional, Doc: f.Doc})
+	}
+	return rows
+}
+
+// typeParts renders t in schema syntax.
+func typeParts(t ir.Type, ext string) []typePart {
+	var parts []typePart
+	switch t.Kind {
+	case ir.KindList, ir.KindMap:
+		parts = append(parts, typePart{Text: t.Kind + "["})
+		parts = append(parts, typeParts(*t.Elem, ext)...)
+		parts = append(parts, typePart{Text: "]"})
+	case ir.KindModel:
+		parts = append(parts, typePart{Text: t.Name, Link: modelFile(t.Name, ext)})
+	default:
+		parts = append(parts, typePart{Text: t.Name})
+	}
+	if t.Optional {
+		parts = append(parts, typePart{Text: "?"})
+	}
+	return parts
+}
+
+// snippets show how the clients call rpc with the example params.
+func snippets(rpc ir.RPC, params []exampleEntry) []snippet {
+	assign := "res, err := "
+	if rpc.Returns == nil {
+		assign = "err := "
+	}
+	var goCode strings.Builder
+	goCode.WriteString("client := rpcclient.NewRPCClient(\"http://localhost:8080\")\n")
+	fmt.Fprintf(&goCode, "%sclient.%s(ctx", assign, rpc.CodeName)
+	if len(params) > 0 {
+		fmt.Fprintf(&goCode, ", rpcclient.%sParams", rpc.CodeName)
+		writeGoFields(&goCode, params, "")
+	}
+	goCode.WriteString(")\n")
+
+	var call strings.Builder
+	writePythonArgs(&call, params, "")
+	imports := []string{"RPCClient"}
+	for _, name := range exampleModels(params) {
+		imports = append(imports, utils.NewIdentifierName(name).PascalCase()+"Model")
+	}
+	assign = "res = "
+	if rpc.Returns == nil {
+		assign = ""
+	}
+	python := fmt.Sprintf("from rpcclient import %s\n\nclient = RPCClient(\"http://localhost:8080\")\n%sclient.%s(%s)\n",
+		strings.Join(imports, ", "), assign, utils.NewIdentifierName(rpc.Name).SnakeCase(), call.String())
+
+	var ts strings.Builder
+	ts.WriteString("import { RPCClient } from \"./rpcclient\";\n\nconst client = new RPCClient(\"http://localhost:8080\");\n")
+	if rpc.Returns != nil {
+		ts.WriteString("const res = ")
+	}
+	fmt.Fprintf(&ts, "await client.%s(", tsMethodName(rpc.CodeName))
+	if len(params) > 0 {
+		writeTSObject(&ts, params, "")
+	}
+	ts.WriteString(");\n")
+
+	return []snippet{
+		{Lang: "go", Name: "Go", Code: formatGo(goCode.String())},
+		{Lang: "python", Name: "Python", Code: python},
+		{Lang: "typescript", Name: "TypeScript", Code: ts.String()},
+	}
+}
+
+// exampleModels lists the models constructed in an example, sorted.
+func exampleModels(entries []exampleEntry) []string {
+	seen := utils.NewSet[string]()
+	var names []string
+	var walk func(e example)
+	walk = func(e example) {
+		if e.typ.Kind == ir.KindModel && !e.null && !seen.Has(e.typ.Name) {
+			seen.Add(e.typ.Name)
+			names = append(names, e.typ.Name)
+		}
+		for _, item := range e.items {
+			walk(item)
+		}
+		for _, entry := range e.entries {
+			walk(entry.value)
+		}
+	}
+	for _, entry := range entries {
+		walk(entry.value)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func tsMethodName(codeName string) string {
+	if codeName == "" {
+		return ""
+	}
+	return strings.ToLower(codeName[:1]) + codeName[1:]
+}
+
+// searchEntry is an entry of the client-side search index.
+type searchEntry struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	Doc  string `json:"doc"`
+	URL  string `json:"url"`
+}
+
+func renderHTML(data site) (map[string]string, error) {
+	tmpl, err := htmltemplate.New("site").Funcs(htmltemplate.FuncMap{
+		"lines": func(doc string) []string { return strings.Split(doc, "\n") },
+		"dict": func(pairs ...any) map[string]any {
+			out := make(map[string]any, len(pairs)/2)
+			for i := 0; i+1 < len(pairs); i += 2 {
+				out[pairs[i].(string)] = pairs[i+1]
+			}
+			return out
+		},
+	}).ParseFS(templateFS, "site.html.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("parse templates: %w", err)
+	}
+	files := make(map[string]string)
+	render := func(file, name string, page any) error {
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, name, map[string]any{"Site": data, "Page": page}); err != nil {
+			return fmt.Errorf("render %s: %w", file, err)
+		}
+		files[file] = buf.String()
+		return nil
+	}
+	if err := render("index.html", "index", nil); err != nil {
+		return nil, err
+	}
+	var index []searchEntry
+	for _, page := range data.RPCs {
+		if err := render(page.File, "rpc", page); err != nil {
+			return nil, err
+		}
+		index = append(index, searchEntry{Name: page.Name, Kind: "rpc", Doc: page.Doc, URL: page.File})
+	}
+	for _, page := range data.Models {
+		if err := render(page.File, "model", page); err != nil {
+			return nil, err
+		}
+		index = append(index, searchEntry{Name: page.Name, Kind: "model", Doc: page.Doc, URL: page.File})
+	}
+
+	// The index is a script rather than JSON so that search also works
+	// when the pages are opened from the file system.
+	encoded, err := json.Marshal(index)
+	if err != nil {
+		return nil, fmt.Errorf("encode search index: %w", err)
+	}
+	files["search-index.js"] = "window.RRPC_SEARCH_INDEX = " + string(encoded) + ";\n"
+	for _, name := range []string{"style.css", "search.js"} {
+		content, err := templateFS.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = string(content)
+	}
+	return files, nil
+}
+
+func renderMarkdown(data site) (map[string]string, error) {
+	tmpl, err := template.New("site").Funcs(template.FuncMap{
+		"cell":   markdownCell,
+		"escape": markdownEscape,
+	}).ParseFS(templateFS, "site.md.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("parse templates: %w", err)
+	}
+	files := make(map[string]string)
+	render := func(file, name string, page any) error {
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, name, map[string]any{"Site": data, "Page": page}); err != nil {
+			return fmt.Errorf("render %s: %w", file, err)
+		}
+		files[file] = buf.String()
+		return nil
+	}
+	if err := render("README.md", "index", nil); err != nil {
+		return nil, err
+	}
+	for _, page := range data.RPCs {
+		if err := render(page.File, "rpc", page); err != nil {
+			return nil, err
+		}
+	}
+	for _, page := range data.Models {
+		if err := render(page.File, "model", page); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// markdownCell makes text safe for a table cell.
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
+
+// markdownEscape escapes the brackets of list and map types.
+func markdownEscape(text string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`).Replace(text)
+}
+
+// formatGo aligns a Go snippet like gofmt does, returning it unchanged if
+// it does not parse.
+func formatGo(code string) string {
+	const prefix = "package p\n\nfunc f() {\n"
+	formatted, err := format.Source([]byte(prefix + code + "}\n"))
+	if err != nil {
+		return code
+	}
+	body := strings.TrimSuffix(strings.TrimPrefix(string(formatted), prefix), "}\n")
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, "\t")
+	}
+	return strings.Join(lines, "\n")
+}
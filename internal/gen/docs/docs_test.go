@@ -0,0 +1,245 @@
+package docsgen_test
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	docsgen "github.com/Rapid-Vision/rRPC/internal/gen/docs"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+)
+
+func names(files map[string]string) []string {
+	var out []string
+	for name := range files {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestHTMLPages(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string
+}
+
+rpc GetText() Text
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := docsgen.Generate(schema, docsgen.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"index.html", "model-Text.html", "rpc-get_text.html", "search-index.js", "search.js", "style.css"}
+	if got := names(files); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected files: %v", got)
+	}
+	for name, page := range files {
+		if strings.Contains(page, `src="http`) || strings.Contains(page, `href="http`) {
+			t.Errorf("%s loads remote resources, the site must work offline", name)
+		}
+	}
+}
+
+func TestRPCPage(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string?
+}
+
+# Creates a text.
+rpc CreateText(
+    text: Text,
+    count: int,
+) Text
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := docsgen.Generate(schema, docsgen.Options{Prefix: "api"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	page := files["rpc-create_text.html"]
+	for _, want := range []string{
+		"<title>CreateText</title>",
+		"<code>POST /api/create_text</code>",
+		"<p>Creates a text.</p>",
+		`<code><a href="model-Text.html">Text</a></code>`,
+		"&#34;count&#34;: 1",
+		"&#34;title&#34;: &#34;string&#34;",
+		"<td><code>input</code></td><td>400</td>",
+		"<td><code>not_implemented</code></td><td>501</td>",
+		`<code class="language-go">`,
+		`<code class="language-python">`,
+		`<code class="language-typescript">`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("rpc page is missing %q", want)
+		}
+	}
+}
+
+func TestRPCWithoutParamsHasNoInputError(t *testing.T) {
+	schema, err := parser.Parse("rpc Ping()\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := docsgen.Generate(schema, docsgen.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(files["rpc-ping.html"], "<code>input</code>") {
+		t.Errorf("RPC without parameters lists input errors")
+	}
+}
+
+func TestModelPage(t *testing.T) {
+	schema, err := parser.Parse(`# A text <b>with markup</b>.
+model Text {
+    title: string
+}
+
+model Node {
+    text: Text?
+}
+
+rpc GetText() Text
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := docsgen.Generate(schema, docsgen.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	page := files["model-Text.html"]
+	if !strings.Contains(page, "A text &lt;b&gt;with markup&lt;/b&gt;.") {
+		t.Errorf("model doc is not escaped:\n%s", page)
+	}
+	for _, used := range []string{`<a href="rpc-get_text.html">GetText</a>`, `<a href="model-Node.html">Node</a>`} {
+		if !strings.Contains(page, used) {
+			t.Errorf("model page is missing used by link %q", used)
+		}
+	}
+}
+
+func TestDirectivesAreNotDocs(t *testing.T) {
+	schema, err := parser.Parse(`# rrpc:query
+# rrpc:folder Texts
+# Gets a text.
+rpc GetText()
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := docsgen.Generate(schema, docsgen.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, page := range files {
+		if strings.Contains(page, "rrpc:") {
+			t.Errorf("%s shows a directive comment", name)
+		}
+	}
+	if !strings.Contains(files["rpc-get_text.html"], "<p>Gets a text.</p>") {
+		t.Errorf("rpc page is missing its doc")
+	}
+}
+
+func TestSearchIndex(t *testing.T) {
+	schema, err := parser.Parse("# Creates a text.\nrpc CreateText()\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := docsgen.Generate(schema, docsgen.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(files["search-index.js"], `{"name":"CreateText","kind":"rpc","doc":"Creates a text.","url":"rpc-create_text.html"}`) {
+		t.Errorf("unexpected search index: %s", files["search-index.js"])
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	schema, err := parser.Parse(`model Node {
+    children: list[Node]
+}
+
+# Pings the server.
+rpc Ping()
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := docsgen.Generate(schema, docsgen.Options{Prefix: "api", Format: docsgen.FormatMarkdown})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"README.md", "model-Node.md", "rpc-ping.md"}
+	if got := names(files); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected files: %v", got)
+	}
+	if !strings.Contains(files["model-Node.md"], "| `children` | list\\[[Node](model-Node.md)\\] | yes |  |") {
+		t.Errorf("unexpected model page:\n%s", files["model-Node.md"])
+	}
+	ping := files["rpc-ping.md"]
+	for _, want := range []string{"## Parameters\nNone.", "```json\n{}\n```", "err := client.Ping(ctx)\n", "client.ping()\n", "await client.ping();\n"} {
+		if !strings.Contains(ping, want) {
+			t.Errorf("rpc page is missing %q:\n%s", want, ping)
+		}
+	}
+	if !strings.Contains(files["README.md"], "| [Ping](rpc-ping.md) | `POST /api/ping` | Pings the server. |") {
+		t.Errorf("unexpected index:\n%s", files["README.md"])
+	}
+}
+
+func TestSnippets(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string?
+    body: string
+}
+
+rpc CreateText(
+    text: Text,
+    count: int,
+) Text
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := docsgen.Generate(schema, docsgen.Options{Format: docsgen.FormatMarkdown})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	page := files["rpc-create_text.md"]
+	for _, want := range []string{
+		"```go\n" + `client := rpcclient.NewRPCClient("http://localhost:8080")
+res, err := client.CreateText(ctx, rpcclient.CreateTextParams{
+	Text: rpcclient.TextModel{
+		Body: "string",
+	},
+	Count: 1,
+})
+` + "```",
+		"from rpcclient import RPCClient, TextModel\n",
+		"res = client.create_text(\n    text=TextModel(\n",
+		"const res = await client.createText({\n\ttext: {\n",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("rpc page is missing %q:\n%s", want, page)
+		}
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	schema, err := parser.Parse("rpc Ping()\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := docsgen.Generate(schema, docsgen.Options{Format: "pdf"}); err == nil || !strings.Contains(err.Error(), `unknown format "pdf"`) {
+		t.Fatalf("expected unknown format error, got %v", err)
+	}
+}
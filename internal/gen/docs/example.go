@@ -0,0 +1,342 @@
+package docsgen
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/ir"
+	"github.com/Rapid-Vision/rRPC/internal/utils"
+)
+
+// example is a sample value built by protocol.SampleValue, together with
+// its type, so it can be rendered in every client language.
+type example struct {
+	typ  ir.Type
+	null bool
+	// scalar is a string, int or bool value.
+	scalar any
+	items  []example
+	// entries are the fields of models, in schema order, and map entries.
+	entries []exampleEntry
+}
+
+type exampleEntry struct {
+	// key is the JSON key; name the field name of models, empty for maps.
+	key   string
+	name  string
+	value example
+}
+
+type exampler struct {
+	models map[string]ir.Model
+}
+
+// value types a sample value of t.
+func (e *exampler) value(t ir.Type, value any) example {
+	out := example{typ: t}
+	switch v := value.(type) {
+	case nil:
+		out.null = true
+	case []any:
+		for _, item := range v {
+			out.items = append(out.items, e.value(*t.Elem, item))
+		}
+	case map[string]any:
+		switch t.Kind {
+		case ir.KindMap:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				out.entries = append(out.entries, exampleEntry{key: key, value: e.value(*t.Elem, v[key])})
+			}
+		case ir.KindModel:
+			out.entries = e.fields(e.models[t.Name].Fields, v)
+		}
+	default:
+		out.scalar = v
+	}
+	return out
+}
+
+// fields types the sample values of fields, keyed by JSON key.
+func (e *exampler) fields(fields []ir.Field, values map[string]any) []exampleEntry {
+	out := make([]exampleEntry, 0, len(fields))
+	for _, f := range fields {
+		if value, ok := values[f.WireName]; ok {
+			out = append(out, exampleEntry{key: f.WireName, name: f.Name, value: e.value(f.Type, value)})
+		}
+	}
+	return out
+}
+
+// isJSON reports whether t is json or raw, whose examples are a small
+// object in every language.
+func isJSON(t ir.Type) bool {
+	return t.Kind == ir.KindBuiltin && (t.Name == "json" || t.Name == "raw")
+}
+
+func isObject(t ir.Type) bool {
+	return t.Kind == ir.KindMap || t.Kind == ir.KindModel
+}
+
+// jsonText renders an example as indented JSON.
+func jsonText(entries []exampleEntry) string {
+	var b strings.Builder
+	writeJSONObject(&b, entries, "")
+	return b.String()
+}
+
+func writeJSONObject(b *strings.Builder, entries []exampleEntry, indent string) {
+	if len(entries) == 0 {
+		b.WriteString("{}")
+		return
+	}
+	b.WriteString("{\n")
+	for i, entry := range entries {
+		fmt.Fprintf(b, "%s  %s: ", indent, strconv.Quote(entry.key))
+		writeJSON(b, entry.value, indent+"  ")
+		if i < len(entries)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(indent + "}")
+}
+
+func writeJSON(b *strings.Builder, e example, indent string) {
+	switch {
+	case e.null:
+		b.WriteString("null")
+	case isJSON(e.typ):
+		b.WriteString(`{"key": "value"}`)
+	case e.typ.Kind == ir.KindList:
+		if len(e.items) == 0 {
+			b.WriteString("[]")
+			return
+		}
+		b.WriteString("[\n")
+		for i, item := range e.items {
+			b.WriteString(indent + "  ")
+			writeJSON(b, item, indent+"  ")
+			if i < len(e.items)-1 {
+				b.WriteString(",")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString(indent + "]")
+	case isObject(e.typ):
+		writeJSONObject(b, e.entries, indent)
+	default:
+		writeScalar(b, e.scalar, "true", "false")
+	}
+}
+
+func writeScalar(b *strings.Builder, value any, trueText, falseText string) {
+	switch v := value.(type) {
+	case string:
+		b.WriteString(strconv.Quote(v))
+	case bool:
+		if v {
+			b.WriteString(trueText)
+		} else {
+			b.WriteString(falseText)
+		}
+	default:
+		fmt.Fprint(b, v)
+	}
+}
+
+// goType returns the Go client type of t, with models in package pkg.
+func goType(t ir.Type, pkg string) string {
+	var base string
+	switch t.Kind {
+	case ir.KindList:
+		base = "[]" + goType(*t.Elem, pkg)
+	case ir.KindMap:
+		base = "map[string]" + goType(*t.Elem, pkg)
+	case ir.KindModel:
+		base = pkg + "." + utils.NewIdentifierName(t.Name).PascalCase() + "Model"
+	default:
+		switch t.Name {
+		case "json":
+			base = "any"
+		case "raw":
+			base = "json.RawMessage"
+		default:
+			base = t.Name
+		}
+	}
+	if t.Optional {
+		return "*" + base
+	}
+	return base
+}
+
+// writeGo renders an example as a Go composite literal. Optional fields are
+// left out, as the client uses pointers for them; optional list elements
+// and map values are only filled for models.
+func writeGo(b *strings.Builder, e example, indent string) {
+	switch {
+	case e.null || e.typ.Optional && e.typ.Kind != ir.KindModel:
+		b.WriteString("nil")
+	case isJSON(e.typ):
+		if e.typ.Name == "raw" {
+			b.WriteString("json.RawMessage(`{\"key\": \"value\"}`)")
+		} else {
+			b.WriteString(`map[string]any{"key": "value"}`)
+		}
+	case e.typ.Kind == ir.KindList:
+		b.WriteString(goType(e.typ, "rpcclient") + "{")
+		for i, item := range e.items {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeGo(b, item, indent)
+		}
+		b.WriteString("}")
+	case e.typ.Kind == ir.KindMap:
+		b.WriteString(goType(e.typ, "rpcclient") + "{")
+		for i, entry := range e.entries {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(strconv.Quote(entry.key) + ": ")
+			writeGo(b, entry.value, indent)
+		}
+		b.WriteString("}")
+	case e.typ.Kind == ir.KindModel:
+		t := e.typ
+		if t.Optional {
+			b.WriteString("&")
+			t.Optional = false
+		}
+		b.WriteString(goType(t, "rpcclient"))
+		writeGoFields(b, e.entries, indent)
+	default:
+		writeScalar(b, e.scalar, "true", "false")
+	}
+}
+
+func writeGoFields(b *strings.Builder, entries []exampleEntry, indent string) {
+	var set []exampleEntry
+	for _, entry := range entries {
+		if !entry.value.typ.Optional {
+			set = append(set, entry)
+		}
+	}
+	if len(set) == 0 {
+		b.WriteString("{}")
+		return
+	}
+	b.WriteString("{\n")
+	for _, entry := range set {
+		fmt.Fprintf(b, "%s\t%s: ", indent, utils.NewIdentifierName(entry.name).PascalCase())
+		writeGo(b, entry.value, indent+"\t")
+		b.WriteString(",\n")
+	}
+	b.WriteString(indent + "}")
+}
+
+// writePython renders an example as a Python expression, with models
+// constructed from their generated classes.
+func writePython(b *strings.Builder, e example, indent string) {
+	switch {
+	case e.null:
+		b.WriteString("None")
+	case isJSON(e.typ):
+		b.WriteString(`{"key": "value"}`)
+	case e.typ.Kind == ir.KindList:
+		b.WriteString("[")
+		for i, item := range e.items {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writePython(b, item, indent)
+		}
+		b.WriteString("]")
+	case e.typ.Kind == ir.KindMap:
+		b.WriteString("{")
+		for i, entry := range e.entries {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(strconv.Quote(entry.key) + ": ")
+			writePython(b, entry.value, indent)
+		}
+		b.WriteString("}")
+	case e.typ.Kind == ir.KindModel:
+		b.WriteString(utils.NewIdentifierName(e.typ.Name).PascalCase() + "Model(")
+		writePythonArgs(b, e.entries, indent)
+		b.WriteString(")")
+	default:
+		writeScalar(b, e.scalar, "True", "False")
+	}
+}
+
+func writePythonArgs(b *strings.Builder, entries []exampleEntry, indent string) {
+	if len(entries) == 0 {
+		return
+	}
+	b.WriteString("\n")
+	for _, entry := range entries {
+		fmt.Fprintf(b, "%s    %s=", indent, utils.NewIdentifierName(entry.name).SnakeCase())
+		writePython(b, entry.value, indent+"    ")
+		b.WriteString(",\n")
+	}
+	b.WriteString(indent)
+}
+
+// writeTS renders an example as a TypeScript object literal.
+func writeTS(b *strings.Builder, e example, indent string) {
+	switch {
+	case e.null:
+		b.WriteString("null")
+	case isJSON(e.typ):
+		b.WriteString(`{ key: "value" }`)
+	case e.typ.Kind == ir.KindList:
+		b.WriteString("[")
+		for i, item := range e.items {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeTS(b, item, indent)
+		}
+		b.WriteString("]")
+	case isObject(e.typ):
+		writeTSObject(b, e.entries, indent)
+	default:
+		writeScalar(b, e.scalar, "true", "false")
+	}
+}
+
+func writeTSObject(b *strings.Builder, entries []exampleEntry, indent string) {
+	if len(entries) == 0 {
+		b.WriteString("{}")
+		return
+	}
+	b.WriteString("{\n")
+	for _, entry := range entries {
+		key := entry.key
+		if !isTSIdentifier(key) {
+			key = strconv.Quote(key)
+		}
+		fmt.Fprintf(b, "%s\t%s: ", indent, key)
+		writeTS(b, entry.value, indent+"\t")
+		b.WriteString(",\n")
+	}
+	b.WriteString(indent + "}")
+}
+
+func isTSIdentifier(s string) bool {
+	for i, r := range s {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
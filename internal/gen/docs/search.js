@@ -0,0 +1,47 @@
+// THIS CODE IS GENERATED
+(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("search-results");
+  var index = window.RRPC_SEARCH_INDEX || [];
+  if (!input || !results) {
+    return;
+  }
+  input.addEventListener("input", function () {
+    var query = input.value.trim().toLowerCase();
+    results.innerHTML = "";
+    if (!query) {
+      return;
+    }
+    var matches = index.filter(function (entry) {
+      return entry.name.toLowerCase().indexOf(query) >= 0 || entry.doc.toLowerCase().indexOf(query) >= 0;
+    });
+    matches.sort(function (a, b) {
+      var an = a.name.toLowerCase().indexOf(query) >= 0 ? 0 : 1;
+      var bn = b.name.toLowerCase().indexOf(query) >= 0 ? 0 : 1;
+      return an - bn || a.name.localeCompare(b.name);
+    });
+    matches.slice(0, 20).forEach(function (entry) {
+      var item = document.createElement("li");
+      var link = document.createElement("a");
+      link.href = entry.url;
+      link.textContent = entry.name;
+      var kind = document.createElement("span");
+      kind.className = "kind";
+      kind.textContent = entry.kind;
+      item.appendChild(link);
+      item.appendChild(kind);
+      results.appendChild(item);
+    });
+    if (matches.length === 0) {
+      var empty = document.createElement("li");
+      empty.textContent = "No results";
+      results.appendChild(empty);
+    }
+  });
+  input.addEventListener("keydown", function (event) {
+    var first = results.querySelector("a");
+    if (event.key === "Enter" && first) {
+      window.location.href = first.getAttribute("href");
+    }
+  });
+})();
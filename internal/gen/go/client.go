@@ -1,32 +1,35 @@
 package gogen
 
 import (
-	"bytes"
 	"fmt"
-	"sort"
-	"strings"
+	"go/format"
 	"text/template"
 
+	"github.com/Rapid-Vision/rRPC/internal/gen/override"
 	"github.com/Rapid-Vision/rRPC/internal/parser"
-
-	_ "embed"
-	"go/format"
 )
 
-//go:embed client_models.go.tmpl
-var clientModelsTemplate string
-
-//go:embed client_errors.go.tmpl
-var clientErrorsTemplate string
-
-//go:embed client_client.go.tmpl
-var clientClientTemplate string
-
-//go:embed client_transport.go.tmpl
-var clientTransportTemplate string
+// clientTemplates renders the files of a generated client package.
+var clientTemplates = override.Set{
+	FS:     templateFS,
+	Header: "header.go.tmpl",
+	Files: map[string]string{
+		"models.go":    "client_models.go.tmpl",
+		"errors.go":    "client_errors.go.tmpl",
+		"client.go":    "client_client.go.tmpl",
+		"transport.go": "client_transport.go.tmpl",
+		"rpcs.go":      "client_rpcs.go.tmpl",
+		"fake.go":      "client_fake.go.tmpl",
+		"memory.go":    "client_memory.go.tmpl",
+	},
+	Format: format.Source,
+}
 
-//go:embed client_rpcs.go.tmpl
-var clientRPCsTemplate string
+// ClientTemplates returns the names of the templates
+// GenerateClientWithTemplates renders, for use with template overrides.
+func ClientTemplates() []string {
+	return clientTemplates.Names()
+}
 
 func GenerateClient(schema *parser.Schema, pkg string) (map[string]string, error) {
 	if schema == nil {
@@ -36,6 +39,12 @@ func GenerateClient(schema *parser.Schema, pkg string) (map[string]string, error
 }
 
 func GenerateClientWithPrefix(schema *parser.Schema, pkg, prefix string) (map[string]string, error) {
+	return GenerateClientWithTemplates(schema, pkg, prefix, nil)
+}
+
+// GenerateClientWithTemplates generates a client package, rendering
+// templates from overrides where given instead of the embedded ones.
+func GenerateClientWithTemplates(schema *parser.Schema, pkg, prefix string, overrides override.Templates) (map[string]string, error) {
 	if schema == nil {
 		return nil, fmt.Errorf("schema is nil")
 	}
@@ -45,13 +54,14 @@ func GenerateClientWithPrefix(schema *parser.Schema, pkg, prefix string) (map[st
 		RPCs:    schema.RPCs,
 	}
 	funcMap := template.FuncMap{
-		"modelTypeName": modelTypeName,
-		"fieldName":     fieldName,
-		"jsonName":      jsonName,
-		"goType":        goType,
-		"rpcParamsName": rpcParamsName,
-		"rpcResultName": rpcResultName,
-		"rpcMethodName": rpcMethodName,
+		"modelTypeName":  modelTypeName,
+		"fieldName":      fieldName,
+		"jsonName":       jsonName,
+		"goType":         goType,
+		"rpcParamsName":  rpcParamsName,
+		"rpcResultName":  rpcResultName,
+		"rpcMethodName":  rpcMethodName,
+		"fakeCallsField": fakeCallsField,
 		"rpcPath": func(name string) string {
 			return rpcPath(prefix, name)
 		},
@@ -63,49 +73,18 @@ func GenerateClientWithPrefix(schema *parser.Schema, pkg, prefix string) (map[st
 		"usesRawInRPCs": func(data templateData) bool {
 			return parser.UsesRawInRPCs(*schema)
 		},
+		"usesRawInReturns": func(data templateData) bool {
+			for _, rpc := range data.RPCs {
+				if rpc.HasReturn && parser.HasRawType(rpc.Returns) {
+					return true
+				}
+			}
+			return false
+		},
 		"hasRPCs": func(data templateData) bool {
 			return len(data.RPCs) > 0
 		},
 	}
 
-	templates := map[string]string{
-		"models.go":    clientModelsTemplate,
-		"errors.go":    clientErrorsTemplate,
-		"client.go":    clientClientTemplate,
-		"transport.go": clientTransportTemplate,
-		"rpcs.go":      clientRPCsTemplate,
-	}
-
-	files := make(map[string]string, len(templates))
-	for name, tmplText := range templates {
-		tmpl, err := template.New(name).Funcs(funcMap).Parse(tmplText)
-		if err != nil {
-			return nil, fmt.Errorf("parse template %s: %w", name, err)
-		}
-		var buf bytes.Buffer
-		buf.WriteString("// THIS CODE IS GENERATED\n\n")
-		buf.WriteString("package ")
-		buf.WriteString(pkg)
-		buf.WriteString("\n\n")
-		if err := tmpl.Execute(&buf, data); err != nil {
-			return nil, fmt.Errorf("execute template %s: %w", name, err)
-		}
-		formatted, err := format.Source(buf.Bytes())
-		if err != nil {
-			return nil, fmt.Errorf("formatting error %s: %w", name, err)
-		}
-		files[name] = string(formatted)
-	}
-
-	ordered := make([]string, 0, len(files))
-	for name := range files {
-		ordered = append(ordered, name)
-	}
-	sort.Strings(ordered)
-	for _, name := range ordered {
-		if strings.TrimSpace(files[name]) == "" {
-			delete(files, name)
-		}
-	}
-	return files, nil
+	return overrides.Render(clientTemplates, data, funcMap)
 }
@@ -0,0 +1,200 @@
+package gogen_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gogen "github.com/Rapid-Vision/rRPC/internal/gen/go"
+	"github.com/Rapid-Vision/rRPC/internal/gen/override"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+)
+
+const inMemoryTest = `package gentest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gentest/rpcclient"
+	"gentest/rpcserver"
+)
+
+func TestInMemoryRoundTrip(t *testing.T) {
+	handler := &rpcserver.MockRPCHandler{
+		EchoFunc: func(_ context.Context, params rpcserver.EchoParams) (rpcserver.EchoResult, error) {
+			return rpcserver.EchoResult{Text: params.Text}, nil
+		},
+	}
+	var client rpcclient.RPCClientInterface = rpcclient.NewInMemoryRPCClient(rpcserver.CreateHTTPHandler(handler))
+
+	res, err := client.Echo(context.Background(), rpcclient.EchoParams{Text: rpcclient.TextModel{Body: "hi"}})
+	if err != nil {
+		t.Fatalf("Echo failed: %v", err)
+	}
+	if res.Body != "hi" || res.Title != nil {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	if calls := handler.EchoCalls(); len(calls) != 1 || calls[0].Text.Body != "hi" {
+		t.Fatalf("unexpected calls %+v", calls)
+	}
+
+	var notImpl rpcclient.NotImplementedRPCError
+	if err := client.Ping(context.Background()); !errors.As(err, &notImpl) {
+		t.Fatalf("expected not implemented error, got %v", err)
+	}
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestInMemoryTransportClosesBody(t *testing.T) {
+	transport := rpcclient.InMemoryTransport{Handler: rpcserver.CreateHTTPHandler(&rpcserver.MockRPCHandler{})}
+	body := &closeRecorder{Reader: strings.NewReader("{}")}
+	req, err := http.NewRequest(http.MethodPost, "http://memory/rpc/ping", body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if !body.closed {
+		t.Fatalf("request body was not closed")
+	}
+}
+
+func TestFakeClient(t *testing.T) {
+	fake := &rpcclient.FakeRPCClient{
+		PingFunc: func(context.Context, rpcclient.PingParams) error { return nil },
+	}
+	var client rpcclient.RPCClientInterface = fake
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if len(fake.PingCalls()) != 1 {
+		t.Fatalf("expected one recorded call")
+	}
+	var notImpl rpcclient.NotImplementedRPCError
+	if _, err := client.Echo(context.Background(), rpcclient.EchoParams{}); !errors.As(err, &notImpl) {
+		t.Fatalf("expected not implemented error, got %v", err)
+	}
+}
+`
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestGeneratedTestDoubles(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not found")
+	}
+	schema, err := parser.Parse(`model Text {
+    title: string?
+    body: string
+}
+
+rpc Echo(
+    text: Text,
+) Text
+
+rpc Ping()
+
+rpc Raw(
+    payload: raw,
+) raw
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	serverFiles, err := gogen.GenerateWithPrefix(schema, "rpcserver", "rpc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	clientFiles, err := gogen.GenerateClientWithPrefix(schema, "rpcclient", "rpc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(clientFiles["memory.go"], "net/http/httptest") {
+		t.Fatalf("client package imports net/http/httptest")
+	}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":           "module gentest\n\ngo 1.22\n",
+		"inmemory_test.go": inMemoryTest,
+	})
+	writeFiles(t, filepath.Join(dir, "rpcserver"), serverFiles)
+	writeFiles(t, filepath.Join(dir, "rpcclient"), clientFiles)
+
+	cmd := exec.Command(goBin, "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go test failed: %v\n%s", err, out)
+	}
+}
+
+func TestTemplateOverrides(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    body: string
+}
+
+rpc GetText() Text
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := gogen.GenerateWithTemplates(schema, "rpcserver", "rpc", override.Templates{
+		"header.go.tmpl": "// Copyright Example Corp.\n\n// THIS CODE IS GENERATED\n\npackage {{.Package}}\n\n",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, contents := range files {
+		if !strings.HasPrefix(contents, "// Copyright Example Corp.\n") {
+			t.Fatalf("%s: missing license header:\n%s", name, contents)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		overrides override.Templates
+		want      string
+	}{
+		{"unknown", override.Templates{"client_models.go.tmpl": ""}, "unknown template client_models.go.tmpl"},
+		{"syntax", override.Templates{"server_models.go.tmpl": "{{range .Models}"}, "parse template override server_models.go.tmpl"},
+		{"execute", override.Templates{"server_models.go.tmpl": "{{.Missing}}"}, "execute template override server_models.go.tmpl"},
+		{"format", override.Templates{"server_models.go.tmpl": "func {"}, "formatting error models.go (from template override server_models.go.tmpl)"},
+	}
+	for _, tt := range tests {
+		_, err := gogen.GenerateWithTemplates(schema, "rpcserver", "rpc", tt.overrides)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Fatalf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
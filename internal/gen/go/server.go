@@ -1,35 +1,53 @@
 package gogen
 
 import (
-	"bytes"
 	"fmt"
-	"sort"
 	"strings"
 	"text/template"
+	"unicode"
 
+	"github.com/Rapid-Vision/rRPC/internal/gen/override"
 	"github.com/Rapid-Vision/rRPC/internal/parser"
 	"github.com/Rapid-Vision/rRPC/internal/utils"
 
-	_ "embed"
+	"embed"
 	"go/format"
 )
 
-//go:embed server_models.go.tmpl
-var serverModelsTemplate string
-
-//go:embed server_errors.go.tmpl
-var serverErrorsTemplate string
-
-//go:embed server_utils.go.tmpl
-var serverUtilsTemplate string
-
-//go:embed server_rpcs.go.tmpl
-var serverRPCsTemplate string
-
+//go:embed *.tmpl
+var templateFS embed.FS
+
+// serverTemplates renders the files of a generated server package. The
+// header renders the start of every file, up to and including the package
+// clause.
+var serverTemplates = override.Set{
+	FS:     templateFS,
+	Header: "header.go.tmpl",
+	Files: map[string]string{
+		"models.go": "server_models.go.tmpl",
+		"errors.go": "server_errors.go.tmpl",
+		"utils.go":  "server_utils.go.tmpl",
+		"rpcs.go":   "server_rpcs.go.tmpl",
+		"mock.go":   "server_mock.go.tmpl",
+	},
+	Format: format.Source,
+}
+
+// templateData is the data passed to every template. Its fields, and the
+// functions of the template func maps, are documented in docs/templates.md
+// and kept stable for user template overrides.
 type templateData struct {
+	// Package is the name of the generated Go package.
 	Package string
-	Models  []parser.Model
-	RPCs    []parser.RPC
+	// Models and RPCs are the declarations of the schema, in source order.
+	Models []parser.Model
+	RPCs   []parser.RPC
+}
+
+// ServerTemplates returns the names of the templates GenerateWithTemplates
+// renders, for use with template overrides.
+func ServerTemplates() []string {
+	return serverTemplates.Names()
 }
 
 func Generate(schema *parser.Schema, pkg string) (map[string]string, error) {
@@ -40,6 +58,12 @@ func Generate(schema *parser.Schema, pkg string) (map[string]string, error) {
 }
 
 func GenerateWithPrefix(schema *parser.Schema, pkg, prefix string) (map[string]string, error) {
+	return GenerateWithTemplates(schema, pkg, prefix, nil)
+}
+
+// GenerateWithTemplates generates a server package, rendering templates
+// from overrides where given instead of the embedded ones.
+func GenerateWithTemplates(schema *parser.Schema, pkg, prefix string, overrides override.Templates) (map[string]string, error) {
 	if schema == nil {
 		return nil, fmt.Errorf("schema is nil")
 	}
@@ -57,6 +81,7 @@ func GenerateWithPrefix(schema *parser.Schema, pkg, prefix string) (map[string]s
 		"rpcResultName":  rpcResultName,
 		"rpcHandlerName": rpcHandlerName,
 		"rpcMethodName":  rpcMethodName,
+		"fakeCallsField": fakeCallsField,
 		"rpcRoute": func(name string) string {
 			return rpcRoute(prefix, name)
 		},
@@ -75,46 +100,7 @@ func GenerateWithPrefix(schema *parser.Schema, pkg, prefix string) (map[string]s
 			return usesJSONDecoder(data.RPCs)
 		},
 	}
-
-	templates := map[string]string{
-		"models.go": serverModelsTemplate,
-		"errors.go": serverErrorsTemplate,
-		"utils.go":  serverUtilsTemplate,
-		"rpcs.go":   serverRPCsTemplate,
-	}
-
-	files := make(map[string]string, len(templates))
-	for name, tmplText := range templates {
-		tmpl, err := template.New(name).Funcs(funcMap).Parse(tmplText)
-		if err != nil {
-			return nil, fmt.Errorf("parse template %s: %w", name, err)
-		}
-		var buf bytes.Buffer
-		buf.WriteString("// THIS CODE IS GENERATED\n\n")
-		buf.WriteString("package ")
-		buf.WriteString(pkg)
-		buf.WriteString("\n\n")
-		if err := tmpl.Execute(&buf, data); err != nil {
-			return nil, fmt.Errorf("execute template %s: %w", name, err)
-		}
-		formatted, err := format.Source(buf.Bytes())
-		if err != nil {
-			return nil, fmt.Errorf("formatting error %s: %w", name, err)
-		}
-		files[name] = string(formatted)
-	}
-
-	ordered := make([]string, 0, len(files))
-	for name := range files {
-		ordered = append(ordered, name)
-	}
-	sort.Strings(ordered)
-	for _, name := range ordered {
-		if strings.TrimSpace(files[name]) == "" {
-			delete(files, name)
-		}
-	}
-	return files, nil
+	return overrides.Render(serverTemplates, data, funcMap)
 }
 
 func usesJSONDecoder(rpcs []parser.RPC) bool {
@@ -154,6 +140,15 @@ func rpcMethodName(name string) string {
 	return utils.NewIdentifierName(name).PascalCase()
 }
 
+func fakeCallsField(name string) string {
+	runes := []rune(rpcMethodName(name))
+	if len(runes) == 0 {
+		return "calls"
+	}
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes) + "Calls"
+}
+
 func rpcRoute(prefix, name string) string {
 	return "POST " + rpcPath(prefix, name)
 }
@@ -0,0 +1,304 @@
+// Package graphqlgen renders a schema as GraphQL SDL, together with a Go
+// adapter that resolves the root fields through a generated Go server's
+// RPCHandler.
+package graphqlgen
+
+import (
+	"embed"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+
+	"github.com/Rapid-Vision/rRPC/internal/gen/override"
+	"github.com/Rapid-Vision/rRPC/internal/ir"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/utils"
+)
+
+// QueryAnnotation marks an RPC without side effects, which becomes a Query
+// field instead of a Mutation field: "# rrpc:query".
+const QueryAnnotation = "query"
+
+// SchemaFile and ResolversFile name the generated files.
+const (
+	SchemaFile    = "schema.graphql"
+	ResolversFile = "resolvers.go"
+)
+
+// placeholder is the field of object types that would otherwise be empty,
+// which GraphQL does not allow.
+const placeholder = "_empty: Boolean"
+
+//go:embed *.tmpl
+var templateFS embed.FS
+
+// resolversTemplates renders the Go adapter.
+var resolversTemplates = override.Set{
+	FS:     templateFS,
+	Header: "header.go.tmpl",
+	Files:  map[string]string{ResolversFile: "resolvers.go.tmpl"},
+	Format: format.Source,
+}
+
+// Options configure the generated files. The Go adapter is only generated
+// when ServerImport, the import path of the generated Go server package,
+// is set; Package is the name of the adapter package. Templates override
+// the templates of the adapter.
+type Options struct {
+	Package      string
+	ServerImport string
+	Templates    override.Templates
+}
+
+// resolversData is the data of the adapter templates. Roots holds Query
+// and Mutation, each when it has fields.
+type resolversData struct {
+	Package      string
+	ServerImport string
+	Roots        []rootData
+}
+
+type rootData struct {
+	Name string
+	RPCs []ir.RPC
+}
+
+type generator struct {
+	schema *ir.Schema
+	// inputs are the models used as arguments, which also need an input
+	// type.
+	inputs utils.Set[string]
+}
+
+// Generate returns the SDL and, when configured, the Go adapter, keyed by
+// file name.
+func Generate(schema *parser.Schema, opts Options) (map[string]string, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("schema is nil")
+	}
+	g := &generator{schema: ir.FromSchema(schema, ""), inputs: utils.NewSet[string]()}
+	for _, rpc := range g.schema.RPCs {
+		for _, param := range rpc.Params {
+			g.markInputs(param.Type)
+		}
+	}
+	if err := g.checkNames(); err != nil {
+		return nil, err
+	}
+	files := map[string]string{SchemaFile: g.sdl()}
+	if opts.ServerImport != "" {
+		resolvers, err := g.resolvers(opts)
+		if err != nil {
+			return nil, err
+		}
+		files[ResolversFile] = resolvers[ResolversFile]
+	}
+	return files, nil
+}
+
+// IsQuery reports whether rpc carries the query annotation.
+func IsQuery(rpc ir.RPC) bool {
+	_, ok := rpc.Annotations.Lookup(QueryAnnotation)
+	return ok
+}
+
+func (g *generator) markInputs(t ir.Type) {
+	switch t.Kind {
+	case ir.KindList:
+		g.markInputs(*t.Elem)
+	case ir.KindModel:
+		if g.inputs.Has(t.Name) {
+			return
+		}
+		g.inputs.Add(t.Name)
+		for _, model := range g.schema.Models {
+			if model.Name == t.Name {
+				for _, field := range model.Fields {
+					g.markInputs(field.Type)
+				}
+			}
+		}
+	}
+}
+
+func (g *generator) checkNames() error {
+	names := map[string]string{
+		"Query":    "the Query type",
+		"Mutation": "the Mutation type",
+		"JSON":     "the JSON scalar",
+	}
+	declare := func(name, owner string) error {
+		if previous, ok := names[name]; ok {
+			return fmt.Errorf("type %s of %s conflicts with %s", name, owner, previous)
+		}
+		names[name] = owner
+		return nil
+	}
+	for _, model := range g.schema.Models {
+		if err := declare(model.CodeName, "model "+model.Name); err != nil {
+			return err
+		}
+	}
+	for _, model := range g.schema.Models {
+		if g.inputs.Has(model.Name) {
+			if err := declare(model.CodeName+"Input", "model "+model.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (g *generator) sdl() string {
+	var b strings.Builder
+	b.WriteString("# THIS CODE IS GENERATED\n\n")
+	b.WriteString("\"\"\"Any JSON value.\"\"\"\nscalar JSON\n")
+
+	var queries, mutations []ir.RPC
+	for _, rpc := range g.schema.RPCs {
+		if IsQuery(rpc) {
+			queries = append(queries, rpc)
+		} else {
+			mutations = append(mutations, rpc)
+		}
+	}
+	b.WriteString("\ntype Query {\n")
+	if len(queries) == 0 {
+		b.WriteString("  " + placeholder + "\n")
+	}
+	g.writeRootFields(&b, queries)
+	b.WriteString("}\n")
+	if len(mutations) > 0 {
+		b.WriteString("\ntype Mutation {\n")
+		g.writeRootFields(&b, mutations)
+		b.WriteString("}\n")
+	}
+
+	for _, model := range g.schema.Models {
+		g.writeObject(&b, "type", model.CodeName, model, false)
+	}
+	for _, model := range g.schema.Models {
+		if g.inputs.Has(model.Name) {
+			g.writeObject(&b, "input", model.CodeName+"Input", model, true)
+		}
+	}
+	return b.String()
+}
+
+func (g *generator) writeRootFields(b *strings.Builder, rpcs []ir.RPC) {
+	for i, rpc := range rpcs {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		writeDescription(b, "  ", rpc.Doc)
+		b.WriteString("  " + protocolName(rpc.Name))
+		if len(rpc.Params) > 0 {
+			b.WriteString("(\n")
+			for _, param := range rpc.Params {
+				writeDescription(b, "    ", param.Doc)
+				fmt.Fprintf(b, "    %s: %s\n", param.WireName, typeName(param.Type, true))
+			}
+			b.WriteString("  )")
+		}
+		returns := "Boolean!"
+		if rpc.Returns != nil {
+			returns = typeName(*rpc.Returns, false)
+		}
+		b.WriteString(": " + returns + "\n")
+	}
+}
+
+func (g *generator) writeObject(b *strings.Builder, keyword, name string, model ir.Model, input bool) {
+	b.WriteString("\n")
+	writeDescription(b, "", model.Doc)
+	fmt.Fprintf(b, "%s %s {\n", keyword, name)
+	if len(model.Fields) == 0 {
+		b.WriteString("  " + placeholder + "\n")
+	}
+	for _, field := range model.Fields {
+		writeDescription(b, "  ", field.Doc)
+		fmt.Fprintf(b, "  %s: %s\n", field.WireName, typeName(field.Type, input))
+	}
+	b.WriteString("}\n")
+}
+
+// typeName returns the GraphQL type of t. GraphQL has no maps, so maps are
+// JSON values.
+func typeName(t ir.Type, input bool) string {
+	var name string
+	switch t.Kind {
+	case ir.KindList:
+		name = "[" + typeName(*t.Elem, input) + "]"
+	case ir.KindMap:
+		name = "JSON"
+	case ir.KindModel:
+		name = utils.NewIdentifierName(t.Name).PascalCase()
+		if input {
+			name += "Input"
+		}
+	default:
+		switch t.Name {
+		case "string":
+			name = "String"
+		case "int":
+			name = "Int"
+		case "bool":
+			name = "Boolean"
+		default:
+			name = "JSON"
+		}
+	}
+	if t.Optional {
+		return name
+	}
+	return name + "!"
+}
+
+// writeDescription writes doc as a block string.
+func writeDescription(b *strings.Builder, indent, doc string) {
+	if doc == "" {
+		return
+	}
+	doc = strings.ReplaceAll(doc, `"""`, `\"""`)
+	b.WriteString(indent + `"""` + "\n")
+	for _, line := range strings.Split(doc, "\n") {
+		if line == "" {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(indent + line + "\n")
+	}
+	b.WriteString(indent + `"""` + "\n")
+}
+
+func protocolName(name string) string {
+	return utils.NewIdentifierName(name).SnakeCase()
+}
+
+func (g *generator) resolvers(opts Options) (map[string]string, error) {
+	data := resolversData{Package: opts.Package, ServerImport: opts.ServerImport}
+	for _, name := range []string{"Query", "Mutation"} {
+		root := rootData{Name: name}
+		for _, rpc := range g.schema.RPCs {
+			if IsQuery(rpc) == (name == "Query") {
+				root.RPCs = append(root.RPCs, rpc)
+			}
+		}
+		if len(root.RPCs) > 0 {
+			data.Roots = append(data.Roots, root)
+		}
+	}
+	return opts.Templates.Render(resolversTemplates, data, template.FuncMap{
+		"protocolName": protocolName,
+		"resultField":  resultField,
+	})
+}
+
+// resultField is the field of the Go result struct holding the result.
+func resultField(t *ir.Type) string {
+	if t.Kind == ir.KindBuiltin || t.Kind == ir.KindModel {
+		return utils.NewIdentifierName(t.Name).PascalCase()
+	}
+	return "Result"
+}
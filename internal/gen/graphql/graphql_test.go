@@ -0,0 +1,250 @@
+package graphqlgen_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gogen "github.com/Rapid-Vision/rRPC/internal/gen/go"
+	graphqlgen "github.com/Rapid-Vision/rRPC/internal/gen/graphql"
+	"github.com/Rapid-Vision/rRPC/internal/gen/override"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+)
+
+func TestGenerateQueryAnnotation(t *testing.T) {
+	schema, err := parser.Parse(`# rrpc:query
+# Gets a count.
+rpc GetCount() int
+
+rpc Reset()
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := graphqlgen.Generate(schema, graphqlgen.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `# THIS CODE IS GENERATED
+
+"""Any JSON value."""
+scalar JSON
+
+type Query {
+  """
+  Gets a count.
+  """
+  get_count: Int!
+}
+
+type Mutation {
+  reset: Boolean!
+}
+`
+	if got := files[graphqlgen.SchemaFile]; got != want {
+		t.Fatalf("unexpected schema:\n%s", got)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected only the schema without a server import, got %d files", len(files))
+	}
+}
+
+func TestGenerateObjectAndInputTypes(t *testing.T) {
+	schema, err := parser.Parse(`# A text.
+model Text {
+    title: string?
+    tags: list[Tag]
+    meta: map[string]
+}
+
+model Tag {
+    name: string
+}
+
+model Empty {
+}
+
+rpc CreateText(
+    text: Text,
+    data: json?,
+) Text?
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := graphqlgen.Generate(schema, graphqlgen.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sdl := files[graphqlgen.SchemaFile]
+	for _, want := range []string{
+		"type Mutation {\n  create_text(\n    text: TextInput!\n    data: JSON\n  ): Text\n}\n",
+		"\"\"\"\nA text.\n\"\"\"\ntype Text {\n  title: String\n  tags: [Tag!]!\n  meta: JSON!\n}\n",
+		"input TextInput {\n  title: String\n  tags: [TagInput!]!\n  meta: JSON!\n}\n",
+		"input TagInput {\n  name: String!\n}\n",
+		"type Empty {\n  _empty: Boolean\n}\n",
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("schema is missing %q:\n%s", want, sdl)
+		}
+	}
+	if strings.Contains(sdl, "input EmptyInput") {
+		t.Errorf("input type generated for a model that is not a parameter:\n%s", sdl)
+	}
+}
+
+func TestGenerateConflicts(t *testing.T) {
+	for src, want := range map[string]string{
+		"model Query {\n}\n": "type Query of model Query conflicts with the Query type",
+		"model Text {\n}\n\nmodel TextInput {\n}\n\nrpc A(\n    text: Text,\n)\n": "type TextInput of model Text conflicts with model TextInput",
+	} {
+		schema, err := parser.Parse(src)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := graphqlgen.Generate(schema, graphqlgen.Options{}); err == nil || err.Error() != want {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	}
+}
+
+func TestResolversTemplateOverride(t *testing.T) {
+	schema, err := parser.Parse("rpc Ping()\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := graphqlgen.Generate(schema, graphqlgen.Options{
+		Package:      "graphqlapi",
+		ServerImport: "gentest/rpcserver",
+		Templates: override.Templates{
+			"header.go.tmpl": "// Copyright Example Corp.\n\npackage {{.Package}}\n\n",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(files[graphqlgen.ResolversFile], "// Copyright Example Corp.\n\npackage graphqlapi\n") {
+		t.Fatalf("header override not applied:\n%s", files[graphqlgen.ResolversFile])
+	}
+
+	_, err = graphqlgen.Generate(schema, graphqlgen.Options{
+		Package:      "graphqlapi",
+		ServerImport: "gentest/rpcserver",
+		Templates:    override.Templates{"server_rpcs.go.tmpl": ""},
+	})
+	if err == nil || !strings.Contains(err.Error(), "unknown template server_rpcs.go.tmpl") {
+		t.Fatalf("expected unknown template error, got %v", err)
+	}
+}
+
+const resolversTest = `package graphqlapi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gentest/rpcserver"
+)
+
+func TestResolvers(t *testing.T) {
+	handler := &rpcserver.MockRPCHandler{
+		GetTextFunc: func(_ context.Context, params rpcserver.GetTextParams) (rpcserver.GetTextResult, error) {
+			title := "title"
+			return rpcserver.GetTextResult{Text: rpcserver.TextModel{Title: &title, Body: strings.Repeat("x", params.Id)}}, nil
+		},
+		PingFunc: func(context.Context, rpcserver.PingParams) error { return nil },
+	}
+	resolvers := Resolvers(handler)
+
+	res, err := resolvers["Query"]["get_text"](context.Background(), map[string]any{"id": 2})
+	if err != nil {
+		t.Fatalf("get_text failed: %v", err)
+	}
+	want := map[string]any{"title": "title", "body": "xx", "tags": nil, "meta": nil}
+	if !reflect.DeepEqual(res, want) {
+		t.Fatalf("unexpected result %#v", res)
+	}
+
+	if res, err := resolvers["Mutation"]["ping"](context.Background(), nil); err != nil || res != true {
+		t.Fatalf("unexpected ping result %v, %v", res, err)
+	}
+
+	var input rpcserver.InputError
+	if _, err := resolvers["Query"]["get_text"](context.Background(), map[string]any{"other": 1}); !errors.As(err, &input) {
+		t.Fatalf("expected input error, got %v", err)
+	}
+	var notImpl rpcserver.NotImplementedError
+	if _, err := resolvers["Mutation"]["list_texts"](context.Background(), nil); !errors.As(err, &notImpl) {
+		t.Fatalf("expected not implemented error, got %v", err)
+	}
+	if !strings.Contains(Schema, "type Query") {
+		t.Fatalf("schema not embedded")
+	}
+}
+`
+
+func TestResolvers(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not found")
+	}
+	schema, err := parser.Parse(`model Text {
+    title: string?
+    body: string
+    tags: list[Tag]
+    meta: map[string]
+}
+
+model Tag {
+    name: string
+}
+
+# rrpc:query
+rpc GetText(id: int) Text
+
+rpc ListTexts() list[Text]
+
+rpc Ping()
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	serverFiles, err := gogen.GenerateWithPrefix(schema, "rpcserver", "rpc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := graphqlgen.Generate(schema, graphqlgen.Options{Package: "graphqlapi", ServerImport: "gentest/rpcserver"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files["resolvers_test.go"] = resolversTest
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"go.mod": "module gentest\n\ngo 1.22\n"})
+	writeFiles(t, filepath.Join(dir, "rpcserver"), serverFiles)
+	writeFiles(t, filepath.Join(dir, "graphqlapi"), files)
+
+	cmd := exec.Command(goBin, "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go test failed: %v\n%s", err, out)
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
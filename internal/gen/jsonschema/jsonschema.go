@@ -0,0 +1,136 @@
+// Package jsonschema renders a schema as JSON Schema (draft 2020-12)
+// documents: one per model, and one for the params and the result of every
+// RPC. Documents refer to each other by relative file name.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/ir"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/utils"
+)
+
+// Dialect is the $schema of every generated document.
+const Dialect = "https://json-schema.org/draft/2020-12/schema"
+
+// ModelFile, ParamsFile and ResultFile name the documents of a model and of
+// the params and result of an RPC, given their PascalCase names.
+func ModelFile(name string) string  { return name + ".schema.json" }
+func ParamsFile(name string) string { return name + ".params.schema.json" }
+func ResultFile(name string) string { return name + ".result.schema.json" }
+
+// Generate returns the documents keyed by file name. baseURL, when set, is
+// the URL the files are served under; documents then get an absolute $id.
+//
+// Objects do not allow properties that are not declared, as the Go server
+// rejects unknown fields.
+func Generate(schema *parser.Schema, baseURL string) (map[string]string, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("schema is nil")
+	}
+	if baseURL != "" && !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	converted := ir.FromSchema(schema, "")
+	files := make(map[string]string, len(converted.Models)+2*len(converted.RPCs))
+	add := func(name, title, doc string, schema map[string]any) error {
+		schema["$schema"] = Dialect
+		if baseURL != "" {
+			schema["$id"] = baseURL + name
+		}
+		schema["title"] = title
+		if doc != "" {
+			schema["description"] = doc
+		}
+		data, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode %s: %w", name, err)
+		}
+		files[name] = string(data) + "\n"
+		return nil
+	}
+	for _, model := range converted.Models {
+		if err := add(ModelFile(model.CodeName), model.CodeName, model.Doc, object(model.Fields)); err != nil {
+			return nil, err
+		}
+	}
+	for _, rpc := range converted.RPCs {
+		if err := add(ParamsFile(rpc.CodeName), rpc.CodeName+" params", rpc.Doc, object(rpc.Params)); err != nil {
+			return nil, err
+		}
+		result := map[string]any{
+			"type":                 "object",
+			"properties":           map[string]any{},
+			"additionalProperties": false,
+		}
+		if rpc.Returns != nil {
+			result["properties"] = map[string]any{rpc.ResultKey: typeSchema(*rpc.Returns)}
+			result["required"] = []string{rpc.ResultKey}
+		}
+		if err := add(ResultFile(rpc.CodeName), rpc.CodeName+" result", "", result); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// object is the schema of a model or of RPC params. Optional fields may be
+// omitted as well as null.
+func object(fields []ir.Field) map[string]any {
+	properties := make(map[string]any, len(fields))
+	required := make([]string, 0, len(fields))
+	for _, f := range fields {
+		property := typeSchema(f.Type)
+		if f.Doc != "" {
+			property["description"] = f.Doc
+		}
+		properties[f.WireName] = property
+		if !f.Type.Optional {
+			required = append(required, f.WireName)
+		}
+	}
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func typeSchema(t ir.Type) map[string]any {
+	var schema map[string]any
+	switch t.Kind {
+	case ir.KindList:
+		schema = map[string]any{"type": "array", "items": typeSchema(*t.Elem)}
+	case ir.KindMap:
+		schema = map[string]any{"type": "object", "additionalProperties": typeSchema(*t.Elem)}
+	case ir.KindModel:
+		schema = map[string]any{"$ref": ModelFile(utils.NewIdentifierName(t.Name).PascalCase())}
+	default:
+		switch t.Name {
+		case "string":
+			schema = map[string]any{"type": "string"}
+		case "int":
+			schema = map[string]any{"type": "integer"}
+		case "bool":
+			schema = map[string]any{"type": "boolean"}
+		default:
+			// json and raw accept any value, including null.
+			return map[string]any{}
+		}
+	}
+	if !t.Optional {
+		return schema
+	}
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []string{typ, "null"}
+		return schema
+	}
+	return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+}
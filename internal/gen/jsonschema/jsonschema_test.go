@@ -0,0 +1,185 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Rapid-Vision/rRPC/internal/gen/jsonschema"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+)
+
+func TestGenerateFilePerDeclaration(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string
+}
+
+rpc CreateText(text: Text) Text
+
+rpc Ping()
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := jsonschema.Generate(schema, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	want := []string{
+		"CreateText.params.schema.json",
+		"CreateText.result.schema.json",
+		"Ping.params.schema.json",
+		"Ping.result.schema.json",
+		"Text.schema.json",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("unexpected files: %v", names)
+	}
+}
+
+func TestGenerateModel(t *testing.T) {
+	schema, err := parser.Parse(`# A text.
+model Text {
+    # The title.
+    title: string?
+    tags: list[string]
+    meta: map[json]
+}
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := jsonschema.Generate(schema, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "additionalProperties": false,
+  "description": "A text.",
+  "properties": {
+    "meta": {
+      "additionalProperties": {},
+      "type": "object"
+    },
+    "tags": {
+      "items": {
+        "type": "string"
+      },
+      "type": "array"
+    },
+    "title": {
+      "description": "The title.",
+      "type": [
+        "string",
+        "null"
+      ]
+    }
+  },
+  "required": [
+    "tags",
+    "meta"
+  ],
+  "title": "Text",
+  "type": "object"
+}
+`
+	if files["Text.schema.json"] != want {
+		t.Fatalf("unexpected model schema:\n%s", files["Text.schema.json"])
+	}
+}
+
+func TestGenerateParamsReferenceModels(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string
+}
+
+rpc CreateText(
+    text: Text,
+    parent: Text?,
+    count: int,
+)
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := jsonschema.Generate(schema, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var params map[string]any
+	if err := json.Unmarshal([]byte(files["CreateText.params.schema.json"]), &params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	properties := params["properties"].(map[string]any)
+	if !reflect.DeepEqual(properties["text"], map[string]any{"$ref": "Text.schema.json"}) {
+		t.Errorf("unexpected text schema: %v", properties["text"])
+	}
+	wantParent := map[string]any{"anyOf": []any{
+		map[string]any{"$ref": "Text.schema.json"},
+		map[string]any{"type": "null"},
+	}}
+	if !reflect.DeepEqual(properties["parent"], wantParent) {
+		t.Errorf("unexpected parent schema: %v", properties["parent"])
+	}
+	if !reflect.DeepEqual(params["required"], []any{"text", "count"}) || params["additionalProperties"] != false {
+		t.Errorf("unexpected params schema: %s", files["CreateText.params.schema.json"])
+	}
+}
+
+func TestGenerateResultWrappedInResultKey(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string
+}
+
+rpc GetText() Text
+
+rpc Ping()
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := jsonschema.Generate(schema, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result, empty map[string]any
+	if err := json.Unmarshal([]byte(files["GetText.result.schema.json"]), &result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal([]byte(files["Ping.result.schema.json"]), &empty); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result["required"], []any{"text"}) || result["additionalProperties"] != false {
+		t.Errorf("unexpected result schema: %s", files["GetText.result.schema.json"])
+	}
+	if _, ok := empty["required"]; ok || len(empty["properties"].(map[string]any)) != 0 {
+		t.Errorf("unexpected empty result schema: %s", files["Ping.result.schema.json"])
+	}
+}
+
+func TestGenerateBaseURL(t *testing.T) {
+	schema, err := parser.Parse("model Text {\n    title: string\n}\n\nrpc GetText() Text\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err := jsonschema.Generate(schema, "https://example.com/schemas")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, contents := range files {
+		var doc map[string]any
+		if err := json.Unmarshal([]byte(contents), &doc); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id := doc["$id"]; id != "https://example.com/schemas/"+name {
+			t.Errorf("%s: unexpected $id %v", name, id)
+		}
+	}
+}
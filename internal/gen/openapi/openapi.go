@@ -8,6 +8,7 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/Rapid-Vision/rRPC/internal/gen/override"
 	"github.com/Rapid-Vision/rRPC/internal/parser"
 	"github.com/Rapid-Vision/rRPC/internal/utils"
 )
@@ -15,12 +16,24 @@ import (
 //go:embed openapi.json.tmpl
 var openApiTemplate string
 
+const templateName = "openapi.json.tmpl"
+
+// templateData is the data of openapi.json.tmpl, documented for overrides
+// in docs/templates.md.
 type templateData struct {
+	// Title and Version fill the info object of the spec.
 	Title   string
 	Version string
 	Models  []parser.Model
 	RPCs    []parser.RPC
-	Prefix  string
+	// Prefix is the URL path prefix as given, without normalization.
+	Prefix string
+}
+
+// Templates returns the names of the templates the generator renders, for
+// use with template overrides.
+func Templates() []string {
+	return []string{templateName}
 }
 
 func Generate(schema *parser.Schema, title, version string) (string, error) {
@@ -31,16 +44,26 @@ func Generate(schema *parser.Schema, title, version string) (string, error) {
 }
 
 func GenerateWithPrefix(schema *parser.Schema, title, version, prefix string) (string, error) {
+	return GenerateWithTemplates(schema, title, version, prefix, nil)
+}
+
+// GenerateWithTemplates generates the spec, rendering the template from
+// overrides when given instead of the embedded one. The result must be
+// valid JSON.
+func GenerateWithTemplates(schema *parser.Schema, title, version, prefix string, overrides override.Templates) (string, error) {
 	if schema == nil {
 		return "", fmt.Errorf("schema is nil")
 	}
+	if err := overrides.Check(Templates()); err != nil {
+		return "", err
+	}
 	if title == "" {
 		title = "rRPC API"
 	}
 	if version == "" {
 		version = "0.1.0"
 	}
-	tmpl, err := template.New("openapi.json.tmpl").Funcs(template.FuncMap{
+	tmpl, err := overrides.Parse(templateName, openApiTemplate, template.FuncMap{
 		"modelSchemaName":  modelSchemaName,
 		"paramsSchemaName": paramsSchemaName,
 		"resultSchemaName": resultSchemaName,
@@ -57,9 +80,9 @@ func GenerateWithPrefix(schema *parser.Schema, title, version, prefix string) (s
 		"resultField":   resultField,
 		"hasReturn":     hasReturn,
 		"add":           add,
-	}).Parse(openApiTemplate)
+	})
 	if err != nil {
-		return "", fmt.Errorf("parse template: %w", err)
+		return "", err
 	}
 
 	data := templateData{
@@ -71,7 +94,10 @@ func GenerateWithPrefix(schema *parser.Schema, title, version, prefix string) (s
 	}
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("execute template: %w", err)
+		return "", fmt.Errorf("execute %s: %w", overrides.Describe(templateName), err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return "", fmt.Errorf("%s did not produce valid JSON", overrides.Describe(templateName))
 	}
 	return buf.String(), nil
 }
@@ -0,0 +1,151 @@
+// Package override lets users replace the templates embedded in the
+// built-in generators with their own, matched by template file name.
+package override
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Templates maps template file names, such as server_rpcs.go.tmpl, to
+// replacement template text. A nil Templates overrides nothing.
+type Templates map[string]string
+
+// Load reads every *.tmpl file in dir. Other files are ignored, so the
+// directory can hold a README or shared notes.
+func Load(dir string) (Templates, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read templates: %w", err)
+	}
+	templates := make(Templates)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".tmpl" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read templates: %w", err)
+		}
+		templates[entry.Name()] = string(data)
+	}
+	return templates, nil
+}
+
+// Set describes the embedded templates of a generator that renders one
+// file per template.
+type Set struct {
+	// FS holds the embedded templates.
+	FS fs.FS
+	// Header renders the start of every file.
+	Header string
+	// Files maps generated file names to the templates that render them.
+	Files map[string]string
+	// Format, when set, post-processes every rendered file.
+	Format func([]byte) ([]byte, error)
+}
+
+// Names returns the sorted names of the templates in s.
+func (s Set) Names() []string {
+	names := []string{s.Header}
+	for _, name := range s.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render checks t against s and renders every file of s: the header
+// followed by the file template, both executed with data. Files that render
+// to nothing are dropped.
+func (t Templates) Render(s Set, data any, funcs template.FuncMap) (map[string]string, error) {
+	if err := t.Check(s.Names()); err != nil {
+		return nil, err
+	}
+	header, err := t.parseFS(s.FS, s.Header, funcs)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]string, len(s.Files))
+	for name, templateName := range s.Files {
+		tmpl, err := t.parseFS(s.FS, templateName, funcs)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := header.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("execute %s: %w", t.Describe(s.Header), err)
+		}
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("execute %s: %w", t.Describe(templateName), err)
+		}
+		out := buf.Bytes()
+		if s.Format != nil {
+			if out, err = s.Format(out); err != nil {
+				return nil, fmt.Errorf("formatting error %s (from %s): %w", name, t.Describe(templateName), err)
+			}
+		}
+		if strings.TrimSpace(string(out)) != "" {
+			files[name] = string(out)
+		}
+	}
+	return files, nil
+}
+
+func (t Templates) parseFS(fsys fs.FS, name string, funcs template.FuncMap) (*template.Template, error) {
+	embedded, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("read template %s: %w", name, err)
+	}
+	return t.Parse(name, string(embedded), funcs)
+}
+
+// Check reports templates that the generator does not use, listing the
+// names it does. Templates of other generators are reported too, so every
+// target needs a directory of its own.
+func (t Templates) Check(known []string) error {
+	var unknown []string
+	for name := range t {
+		if !slices.Contains(known, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	sorted := append([]string(nil), known...)
+	sort.Strings(sorted)
+	return fmt.Errorf("unknown template %s (this generator uses %s; each target needs its own templates directory)", strings.Join(unknown, ", "), strings.Join(sorted, ", "))
+}
+
+// Parse parses the template called name from its override, or from
+// embedded when there is none. Errors name the override so that mistakes
+// in user templates are easy to tell apart from generator bugs.
+func (t Templates) Parse(name, embedded string, funcs template.FuncMap) (*template.Template, error) {
+	text, overridden := t[name]
+	if !overridden {
+		text = embedded
+	}
+	tmpl, err := template.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", t.Describe(name), err)
+	}
+	return tmpl, nil
+}
+
+// Describe returns "template NAME" or "template override NAME" for use in
+// error messages.
+func (t Templates) Describe(name string) string {
+	if _, ok := t[name]; ok {
+		return "template override " + name
+	}
+	return "template " + name
+}
@@ -0,0 +1,98 @@
+package override_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Rapid-Vision/rRPC/internal/gen/override"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	for name, contents := range map[string]string{
+		"header.go.tmpl": "// Copyright Example\n",
+		"README.md":      "notes",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	templates, err := override.Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(templates) != 1 || templates["header.go.tmpl"] != "// Copyright Example\n" {
+		t.Fatalf("unexpected templates: %v", templates)
+	}
+	if _, err := override.Load(filepath.Join(dir, "missing")); err == nil {
+		t.Fatalf("expected error for missing directory")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	templates := override.Templates{"modles.go.tmpl": ""}
+	err := templates.Check([]string{"models.go.tmpl", "header.go.tmpl"})
+	want := "unknown template modles.go.tmpl (this generator uses header.go.tmpl, models.go.tmpl; each target needs its own templates directory)"
+	if err == nil || err.Error() != want {
+		t.Fatalf("expected %q, got %v", want, err)
+	}
+	if err := override.Templates(nil).Check([]string{"models.go.tmpl"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	templates := override.Templates{"a.tmpl": "{{.Name"}
+	if _, err := templates.Parse("a.tmpl", "{{.Name}}", nil); err == nil || !strings.Contains(err.Error(), "parse template override a.tmpl") {
+		t.Fatalf("expected override parse error, got %v", err)
+	}
+	if _, err := templates.Parse("b.tmpl", "{{.Name", nil); err == nil || !strings.Contains(err.Error(), "parse template b.tmpl") {
+		t.Fatalf("expected embedded parse error, got %v", err)
+	}
+	tmpl, err := templates.Parse("b.tmpl", "{{.Name}}", nil)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, struct{ Name string }{"embedded"}); err != nil || b.String() != "embedded" {
+		t.Fatalf("unexpected output %q: %v", b.String(), err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	set := override.Set{
+		FS: fstest.MapFS{
+			"header.tmpl": {Data: []byte("{{if .Name}}// {{.Name}}\n{{end}}")},
+			"a.tmpl":      {Data: []byte("a")},
+			"b.tmpl":      {Data: []byte("{{if false}}b{{end}}")},
+		},
+		Header: "header.tmpl",
+		Files:  map[string]string{"a.txt": "a.tmpl", "b.txt": "b.tmpl"},
+		Format: func(data []byte) ([]byte, error) { return []byte(strings.ToUpper(string(data))), nil },
+	}
+	if got := set.Names(); !slices.Equal(got, []string{"a.tmpl", "b.tmpl", "header.tmpl"}) {
+		t.Fatalf("unexpected names: %v", got)
+	}
+	files, err := override.Templates{"a.tmpl": "override"}.Render(set, struct{ Name string }{"x"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 2 || files["a.txt"] != "// X\nOVERRIDE" {
+		t.Fatalf("unexpected files: %v", files)
+	}
+	// Files that render to nothing are dropped.
+	files, err = override.Templates(nil).Render(set, struct{ Name string }{""}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 1 || files["a.txt"] != "A" {
+		t.Fatalf("unexpected files: %v", files)
+	}
+	if _, err := (override.Templates{"c.tmpl": ""}).Render(set, nil, nil); err == nil || !strings.Contains(err.Error(), "unknown template c.tmpl") {
+		t.Fatalf("expected unknown template error, got %v", err)
+	}
+}
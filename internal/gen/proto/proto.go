@@ -0,0 +1,393 @@
+// Package protogen renders a schema as a proto3 file. Field numbers are
+// assigned through a Lock, so regenerating after a schema change never
+// renumbers existing fields.
+package protogen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/ir"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/utils"
+)
+
+const lockVersion = 1
+
+// Lock records the field numbers of every message ever generated. Fields
+// that are removed, or whose type changes, keep their number reserved so
+// it is never reused for different data.
+type Lock struct {
+	Version  int                       `json:"version"`
+	Messages map[string]*LockedMessage `json:"messages"`
+}
+
+type LockedMessage struct {
+	Fields map[string]LockedField `json:"fields"`
+	// Reserved lists numbers of fields whose type changed.
+	Reserved []int `json:"reserved,omitempty"`
+}
+
+// LockedField is the number of a field and the type it was generated with.
+type LockedField struct {
+	Number int    `json:"number"`
+	Type   string `json:"type"`
+}
+
+// ReadLock reads a lock file. A missing file yields an empty lock.
+func ReadLock(path string) (*Lock, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Lock{Version: lockVersion, Messages: map[string]*LockedMessage{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read lock: %w", err)
+	}
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("read lock %s: %w", path, err)
+	}
+	if lock.Version != lockVersion {
+		return nil, fmt.Errorf("read lock %s: unsupported version %d", path, lock.Version)
+	}
+	if lock.Messages == nil {
+		lock.Messages = map[string]*LockedMessage{}
+	}
+	for _, message := range lock.Messages {
+		if message.Fields == nil {
+			message.Fields = map[string]LockedField{}
+		}
+	}
+	return &lock, nil
+}
+
+// Write writes the lock file.
+func (l *Lock) Write(path string) error {
+	// Types such as map<string, Text> stay readable without HTML escaping.
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(l); err != nil {
+		return fmt.Errorf("encode lock: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write lock: %w", err)
+	}
+	return nil
+}
+
+// Options configure the generated file. Package is the proto package and
+// Service the name of the service holding the RPCs.
+type Options struct {
+	Package   string
+	Service   string
+	GoPackage string
+}
+
+// firstReserved and lastReserved bound the field numbers reserved for the
+// protobuf implementation.
+const (
+	firstReserved = 19000
+	lastReserved  = 19999
+)
+
+type message struct {
+	name   string
+	doc    string
+	fields []protoField
+}
+
+type protoField struct {
+	name string
+	doc  string
+	// typ is the field type including its label, such as "repeated string".
+	typ string
+}
+
+type generator struct {
+	lock     *Lock
+	messages []message
+	names    map[string]string
+	// wrappers are the messages holding nested lists and maps, which
+	// proto3 cannot express directly.
+	wrappers []message
+	imports  utils.Set[string]
+}
+
+// Generate renders schema as a proto3 file, assigning numbers to new fields
+// in lock. The lock is updated in place and has to be written back.
+func Generate(schema *parser.Schema, lock *Lock, opts Options) (string, error) {
+	if schema == nil {
+		return "", fmt.Errorf("schema is nil")
+	}
+	if lock.Messages == nil {
+		lock.Messages = map[string]*LockedMessage{}
+	}
+	g := &generator{lock: lock, names: map[string]string{}, imports: utils.NewSet[string]()}
+	converted := ir.FromSchema(schema, "")
+	for _, model := range converted.Models {
+		if err := g.declare(model.CodeName, "model "+model.Name); err != nil {
+			return "", err
+		}
+	}
+	for _, rpc := range converted.RPCs {
+		for _, name := range []string{rpc.CodeName + "Request", rpc.CodeName + "Response"} {
+			if err := g.declare(name, "rpc "+rpc.Name); err != nil {
+				return "", err
+			}
+		}
+	}
+	for _, model := range converted.Models {
+		g.messages = append(g.messages, message{
+			name:   model.CodeName,
+			doc:    model.Doc,
+			fields: g.fields(model.Fields),
+		})
+	}
+	for _, rpc := range converted.RPCs {
+		g.messages = append(g.messages, message{
+			name:   rpc.CodeName + "Request",
+			fields: g.fields(rpc.Params),
+		})
+		response := message{name: rpc.CodeName + "Response"}
+		if rpc.Returns != nil {
+			response.fields = []protoField{{name: rpc.ResultKey, typ: g.fieldType(*rpc.Returns)}}
+		}
+		g.messages = append(g.messages, response)
+	}
+	for _, wrapper := range g.wrappers {
+		if owner, ok := g.names[wrapper.name]; ok {
+			return "", fmt.Errorf("message %s for nested lists and maps conflicts with %s", wrapper.name, owner)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("// THIS CODE IS GENERATED\n\n")
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %s;\n", opts.Package)
+	if g.imports.Has("struct") {
+		b.WriteString("\nimport \"google/protobuf/struct.proto\";\n")
+	}
+	if opts.GoPackage != "" {
+		fmt.Fprintf(&b, "\noption go_package = %q;\n", opts.GoPackage)
+	}
+	if len(converted.RPCs) > 0 {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "service %s {\n", opts.Service)
+		for i, rpc := range converted.RPCs {
+			if i > 0 && rpc.Doc != "" {
+				b.WriteString("\n")
+			}
+			writeDoc(&b, "  ", rpc.Doc)
+			fmt.Fprintf(&b, "  rpc %s(%sRequest) returns (%sResponse);\n", rpc.CodeName, rpc.CodeName, rpc.CodeName)
+		}
+		b.WriteString("}\n")
+	}
+	for _, m := range append(g.messages, g.wrappers...) {
+		b.WriteString("\n")
+		g.writeMessage(&b, m)
+	}
+	return b.String(), nil
+}
+
+func (g *generator) declare(name, owner string) error {
+	if previous, ok := g.names[name]; ok {
+		return fmt.Errorf("message %s of %s conflicts with %s", name, owner, previous)
+	}
+	g.names[name] = owner
+	return nil
+}
+
+func (g *generator) fields(fields []ir.Field) []protoField {
+	out := make([]protoField, 0, len(fields))
+	for _, f := range fields {
+		out = append(out, protoField{name: f.WireName, doc: f.Doc, typ: g.fieldType(f.Type)})
+	}
+	return out
+}
+
+// fieldType returns the type of a field with its label. Lists and maps
+// cannot be optional in proto3, and their elements cannot be null, so
+// those optional markers are dropped.
+func (g *generator) fieldType(t ir.Type) string {
+	switch t.Kind {
+	case ir.KindList:
+		return "repeated " + g.elemType(*t.Elem)
+	case ir.KindMap:
+		if isJSON(*t.Elem) {
+			g.imports.Add("struct")
+			return optionalLabel("google.protobuf.Struct", t.Optional)
+		}
+		return "map<string, " + g.elemType(*t.Elem) + ">"
+	}
+	return optionalLabel(g.scalarType(t), t.Optional)
+}
+
+// elemType returns the type of a list element or map value, wrapping
+// nested lists and maps in a message.
+func (g *generator) elemType(t ir.Type) string {
+	if t.Kind != ir.KindList && t.Kind != ir.KindMap {
+		return g.scalarType(t)
+	}
+	name := wrapperName(t)
+	if !slices.ContainsFunc(g.wrappers, func(m message) bool { return m.name == name }) {
+		t.Optional = false
+		g.wrappers = append(g.wrappers, message{
+			name:   name,
+			fields: []protoField{{name: "values", typ: g.fieldType(t)}},
+		})
+	}
+	return name
+}
+
+func (g *generator) scalarType(t ir.Type) string {
+	if t.Kind == ir.KindModel {
+		return utils.NewIdentifierName(t.Name).PascalCase()
+	}
+	switch t.Name {
+	case "string":
+		return "string"
+	case "int":
+		return "int64"
+	case "bool":
+		return "bool"
+	default:
+		g.imports.Add("struct")
+		return "google.protobuf.Value"
+	}
+}
+
+func optionalLabel(typ string, optional bool) string {
+	if optional {
+		return "optional " + typ
+	}
+	return typ
+}
+
+func isJSON(t ir.Type) bool {
+	return t.Kind == ir.KindBuiltin && (t.Name == "json" || t.Name == "raw")
+}
+
+// wrapperName names the message wrapping a nested list or map, such as
+// StringList for list[string] or TextMapList for list[map[Text]].
+func wrapperName(t ir.Type) string {
+	switch t.Kind {
+	case ir.KindList:
+		return wrapperName(*t.Elem) + "List"
+	case ir.KindMap:
+		return wrapperName(*t.Elem) + "Map"
+	}
+	return utils.NewIdentifierName(t.Name).PascalCase()
+}
+
+// writeMessage writes a message, numbering its fields from the lock.
+// Wrapper messages are not locked: they always hold a single field.
+func (g *generator) writeMessage(b *strings.Builder, m message) {
+	writeDoc(b, "", m.doc)
+	if _, declared := g.names[m.name]; !declared {
+		fmt.Fprintf(b, "message %s {\n  %s %s = 1;\n}\n", m.name, m.fields[0].typ, m.fields[0].name)
+		return
+	}
+	numbers := g.number(m)
+	locked := g.lock.Messages[m.name]
+	fmt.Fprintf(b, "message %s {\n", m.name)
+	var reserved []int
+	var reservedNames []string
+	reserved = append(reserved, locked.Reserved...)
+	current := utils.NewSet[string]()
+	for _, f := range m.fields {
+		current.Add(f.name)
+	}
+	for name, f := range locked.Fields {
+		if !current.Has(name) {
+			reserved = append(reserved, f.Number)
+			reservedNames = append(reservedNames, name)
+		}
+	}
+	sort.Ints(reserved)
+	sort.Strings(reservedNames)
+	if len(reserved) > 0 {
+		fmt.Fprintf(b, "  reserved %s;\n", joinInts(reserved))
+	}
+	if len(reservedNames) > 0 {
+		quoted := make([]string, len(reservedNames))
+		for i, name := range reservedNames {
+			quoted[i] = fmt.Sprintf("%q", name)
+		}
+		fmt.Fprintf(b, "  reserved %s;\n", strings.Join(quoted, ", "))
+	}
+	if len(reserved) > 0 && len(m.fields) > 0 {
+		b.WriteString("\n")
+	}
+	for _, f := range m.fields {
+		writeDoc(b, "  ", f.doc)
+		fmt.Fprintf(b, "  %s %s = %d;\n", f.typ, f.name, numbers[f.name])
+	}
+	b.WriteString("}\n")
+}
+
+// number returns the field numbers of m, assigning new numbers above all
+// numbers ever used in the message.
+func (g *generator) number(m message) map[string]int {
+	locked := g.lock.Messages[m.name]
+	if locked == nil {
+		locked = &LockedMessage{Fields: map[string]LockedField{}}
+		g.lock.Messages[m.name] = locked
+	}
+	next := 1
+	for _, f := range locked.Fields {
+		next = max(next, f.Number+1)
+	}
+	for _, n := range locked.Reserved {
+		next = max(next, n+1)
+	}
+	numbers := make(map[string]int, len(m.fields))
+	for _, f := range m.fields {
+		typ := strings.TrimPrefix(f.typ, "optional ")
+		previous, ok := locked.Fields[f.name]
+		if ok && previous.Type == typ {
+			numbers[f.name] = previous.Number
+			continue
+		}
+		if ok {
+			locked.Reserved = append(locked.Reserved, previous.Number)
+		}
+		if next >= firstReserved && next <= lastReserved {
+			next = lastReserved + 1
+		}
+		locked.Fields[f.name] = LockedField{Number: next, Type: typ}
+		numbers[f.name] = next
+		next++
+	}
+	sort.Ints(locked.Reserved)
+	return numbers
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprint(v)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func writeDoc(b *strings.Builder, indent, doc string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		if line == "" {
+			b.WriteString(indent + "//\n")
+			continue
+		}
+		b.WriteString(indent + "// " + line + "\n")
+	}
+}
@@ -0,0 +1,197 @@
+package protogen_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	protogen "github.com/Rapid-Vision/rRPC/internal/gen/proto"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+)
+
+func generate(t *testing.T, src string, lock *protogen.Lock) string {
+	t.Helper()
+	schema, err := parser.Parse(src)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	out, err := protogen.Generate(schema, lock, protogen.Options{Package: "demo.v1", Service: "Demo"})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	return out
+}
+
+func TestGenerate(t *testing.T) {
+	lock, err := protogen.ReadLock(filepath.Join(t.TempDir(), "missing.lock"))
+	if err != nil {
+		t.Fatalf("read lock: %v", err)
+	}
+	got := generate(t, `# A text.
+model Text {
+    # The title.
+    title: string?
+    tags: list[string]?
+    meta: map[json]
+    grid: list[list[int]]
+    notes: map[Text]
+}
+
+# Creates a text.
+rpc CreateText(
+    text: Text,
+    data: raw,
+) Text
+
+rpc ListTexts() list[Text]
+
+rpc Ping()
+`, lock)
+	want := `// THIS CODE IS GENERATED
+
+syntax = "proto3";
+
+package demo.v1;
+
+import "google/protobuf/struct.proto";
+
+service Demo {
+  // Creates a text.
+  rpc CreateText(CreateTextRequest) returns (CreateTextResponse);
+  rpc ListTexts(ListTextsRequest) returns (ListTextsResponse);
+  rpc Ping(PingRequest) returns (PingResponse);
+}
+
+// A text.
+message Text {
+  // The title.
+  optional string title = 1;
+  repeated string tags = 2;
+  google.protobuf.Struct meta = 3;
+  repeated IntList grid = 4;
+  map<string, Text> notes = 5;
+}
+
+message CreateTextRequest {
+  Text text = 1;
+  google.protobuf.Value data = 2;
+}
+
+message CreateTextResponse {
+  Text text = 1;
+}
+
+message ListTextsRequest {
+}
+
+message ListTextsResponse {
+  repeated Text result = 1;
+}
+
+message PingRequest {
+}
+
+message PingResponse {
+}
+
+message IntList {
+  repeated int64 values = 1;
+}
+`
+	if got != want {
+		t.Fatalf("unexpected proto:\n%s", got)
+	}
+}
+
+func TestLockKeepsNumbers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.proto.lock")
+	lock, err := protogen.ReadLock(path)
+	if err != nil {
+		t.Fatalf("read lock: %v", err)
+	}
+	generate(t, `model Text {
+    title: string
+    body: string
+    views: int
+}
+`, lock)
+	if err := lock.Write(path); err != nil {
+		t.Fatalf("write lock: %v", err)
+	}
+	if lock, err = protogen.ReadLock(path); err != nil {
+		t.Fatalf("read lock: %v", err)
+	}
+
+	// body is removed, views changes type and author is added in front.
+	got := generate(t, `model Text {
+    author: string?
+    title: string
+    views: string
+}
+`, lock)
+	want := `message Text {
+  reserved 2, 3;
+  reserved "body";
+
+  optional string author = 4;
+  string title = 1;
+  string views = 5;
+}
+`
+	if !strings.HasSuffix(got, want) {
+		t.Fatalf("unexpected proto:\n%s", got)
+	}
+
+	// Restoring body with its old type restores its number.
+	got = generate(t, `model Text {
+    title: string
+    body: string
+}
+`, lock)
+	if !strings.Contains(got, "  string body = 2;\n") || !strings.Contains(got, "  reserved 3, 4, 5;\n") {
+		t.Fatalf("unexpected proto:\n%s", got)
+	}
+}
+
+func TestGenerateConflicts(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"model GetTextRequest {\n}\n\nrpc GetText()\n", "message GetTextRequest of rpc GetText conflicts with model GetTextRequest"},
+		{"model IntList {\n}\n\nrpc GetGrid() list[list[int]]\n", "message IntList for nested lists and maps conflicts with model IntList"},
+	}
+	for _, tt := range tests {
+		schema, err := parser.Parse(tt.src)
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		_, err = protogen.Generate(schema, &protogen.Lock{}, protogen.Options{Package: "demo", Service: "Demo"})
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("expected error %q, got %v", tt.want, err)
+		}
+	}
+}
+
+func TestLockIsNotHTMLEscaped(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.proto.lock")
+	lock, err := protogen.ReadLock(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	generate(t, `model Text {
+    tags: map[string]
+}
+`, lock)
+	if err := lock.Write(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), `"map<string, string>"`) {
+		t.Fatalf("unexpected lock:\n%s", data)
+	}
+}
@@ -1,30 +1,43 @@
 package pygen
 
 import (
-	"bytes"
-	_ "embed"
+	"embed"
 	"fmt"
-	"sort"
 	"strings"
 	"text/template"
 
+	"github.com/Rapid-Vision/rRPC/internal/gen/override"
 	"github.com/Rapid-Vision/rRPC/internal/parser"
 	"github.com/Rapid-Vision/rRPC/internal/utils"
 )
 
-//go:embed errors.py.tmpl
-var errorsTemplate string
+//go:embed *.tmpl
+var templateFS embed.FS
 
-//go:embed models.py.tmpl
-var modelsTemplate string
+// fileTemplates renders the files of a generated Python client package.
+var fileTemplates = override.Set{
+	FS:     templateFS,
+	Header: "header.py.tmpl",
+	Files: map[string]string{
+		"errors.py": "errors.py.tmpl",
+		"models.py": "models.py.tmpl",
+		"client.py": "client.py.tmpl",
+	},
+}
 
-//go:embed client.py.tmpl
-var clientTemplate string
+// Templates lists the template names an override directory may use.
+func Templates() []string {
+	return fileTemplates.Names()
+}
 
+// templateData is what the client templates see. docs/templates.md
+// documents it for template overrides, so changes must stay compatible.
 type templateData struct {
 	Models []parser.Model
 	RPCs   []parser.RPC
+	// Prefix is the URL path prefix with a leading slash, or empty.
 	Prefix string
+	// Pydantic is set when the client validates inputs with pydantic.
 	Pydantic bool
 }
 
@@ -40,27 +53,33 @@ func GenerateClientWithPrefix(schema *parser.Schema, prefix string) (map[string]
 }
 
 func GenerateClientWithPrefixAndPydantic(schema *parser.Schema, prefix string, pydantic bool) (map[string]string, error) {
+	return GenerateClientWithTemplates(schema, prefix, pydantic, nil)
+}
+
+// GenerateClientWithTemplates generates a client package, rendering
+// templates from overrides where given instead of the embedded ones.
+func GenerateClientWithTemplates(schema *parser.Schema, prefix string, pydantic bool, overrides override.Templates) (map[string]string, error) {
 	if schema == nil {
 		return nil, fmt.Errorf("schema is nil")
 	}
 	data := templateData{
-		Models: schema.Models,
-		RPCs:   schema.RPCs,
-		Prefix: prefixPath(prefix),
+		Models:   schema.Models,
+		RPCs:     schema.RPCs,
+		Prefix:   prefixPath(prefix),
 		Pydantic: pydantic,
 	}
 	funcMap := template.FuncMap{
-		"className":      className,
+		"className":       className,
 		"paramsClassName": paramsClassName,
-		"fieldName":      fieldName,
-		"jsonName":       jsonName,
-		"pythonType":     pythonType,
-		"rpcMethodName":  rpcMethodName,
-		"resultField":    resultField,
-		"decodeExpr":     decodeExpr,
-		"hasParameters":  hasParameters,
-		"hasModelFields": hasModelFields,
-		"hasReturn":      hasReturn,
+		"fieldName":       fieldName,
+		"jsonName":        jsonName,
+		"pythonType":      pythonType,
+		"rpcMethodName":   rpcMethodName,
+		"resultField":     resultField,
+		"decodeExpr":      decodeExpr,
+		"hasParameters":   hasParameters,
+		"hasModelFields":  hasModelFields,
+		"hasReturn":       hasReturn,
 		"hasModels": func(data templateData) bool {
 			return len(data.Models) > 0
 		},
@@ -69,37 +88,7 @@ func GenerateClientWithPrefixAndPydantic(schema *parser.Schema, prefix string, p
 		},
 	}
 
-	templates := map[string]string{
-		"errors.py": errorsTemplate,
-		"models.py": modelsTemplate,
-		"client.py": clientTemplate,
-	}
-
-	files := make(map[string]string, len(templates))
-	for name, tmplText := range templates {
-		tmpl, err := template.New(name).Funcs(funcMap).Parse(tmplText)
-		if err != nil {
-			return nil, fmt.Errorf("parse template %s: %w", name, err)
-		}
-		var buf bytes.Buffer
-		buf.WriteString("# THIS CODE IS GENERATED\n\n")
-		if err := tmpl.Execute(&buf, data); err != nil {
-			return nil, fmt.Errorf("execute template %s: %w", name, err)
-		}
-		files[name] = buf.String()
-	}
-
-	ordered := make([]string, 0, len(files))
-	for name := range files {
-		ordered = append(ordered, name)
-	}
-	sort.Strings(ordered)
-	for _, name := range ordered {
-		if strings.TrimSpace(files[name]) == "" {
-			delete(files, name)
-		}
-	}
-	return files, nil
+	return overrides.Render(fileTemplates, data, funcMap)
 }
 
 func GeneratePythonInit(schema *parser.Schema) string {
@@ -1,36 +1,52 @@
 package pythonserver
 
 import (
-	"bytes"
-	_ "embed"
+	"embed"
 	"fmt"
-	"sort"
 	"strings"
 	"text/template"
 
+	"github.com/Rapid-Vision/rRPC/internal/gen/override"
 	"github.com/Rapid-Vision/rRPC/internal/parser"
 	"github.com/Rapid-Vision/rRPC/internal/utils"
 )
 
-//go:embed app.py.tmpl
-var appTemplate string
-
-//go:embed errors.py.tmpl
-var errorsTemplate string
-
-//go:embed handlers.py.tmpl
-var handlersTemplate string
+//go:embed *.tmpl
+var templateFS embed.FS
+
+// fileTemplates renders the files of a generated Python server package.
+var fileTemplates = override.Set{
+	FS:     templateFS,
+	Header: "header.py.tmpl",
+	Files: map[string]string{
+		"app.py":      "app.py.tmpl",
+		"errors.py":   "errors.py.tmpl",
+		"handlers.py": "handlers.py.tmpl",
+		"models.py":   "models.py.tmpl",
+	},
+}
 
-//go:embed models.py.tmpl
-var modelsTemplate string
+// Templates lists the overridable templates of the server generator.
+func Templates() []string {
+	return fileTemplates.Names()
+}
 
+// templateData is the value every server template is executed with; see
+// docs/templates.md.
 type templateData struct {
 	Models []parser.Model
 	RPCs   []parser.RPC
+	// Prefix is the URL path prefix with a leading slash, or empty.
 	Prefix string
 }
 
 func GenerateWithPrefix(schema *parser.Schema, prefix string) (map[string]string, error) {
+	return GenerateWithTemplates(schema, prefix, nil)
+}
+
+// GenerateWithTemplates generates a server package, rendering templates
+// from overrides where given instead of the embedded ones.
+func GenerateWithTemplates(schema *parser.Schema, prefix string, overrides override.Templates) (map[string]string, error) {
 	if schema == nil {
 		return nil, fmt.Errorf("schema is nil")
 	}
@@ -40,16 +56,17 @@ func GenerateWithPrefix(schema *parser.Schema, prefix string) (map[string]string
 		Prefix: prefixPath(prefix),
 	}
 	funcMap := template.FuncMap{
-		"className":       className,
-		"paramsClassName": paramsClassName,
-		"fieldName":       fieldName,
-		"jsonName":        jsonName,
-		"pythonType":      pythonType,
-		"rpcMethodName":   rpcMethodName,
-		"resultField":     resultField,
-		"hasParameters":   hasParameters,
-		"hasModelFields":  hasModelFields,
-		"hasReturn":       hasReturn,
+		"className":             className,
+		"paramsClassName":       paramsClassName,
+		"fieldName":             fieldName,
+		"jsonName":              jsonName,
+		"pythonType":            pythonType,
+		"rpcMethodName":         rpcMethodName,
+		"resultField":           resultField,
+		"hasParameters":         hasParameters,
+		"hasRequiredParameters": hasRequiredParameters,
+		"hasModelFields":        hasModelFields,
+		"hasReturn":             hasReturn,
 		"hasModels": func(data templateData) bool {
 			return len(data.Models) > 0
 		},
@@ -63,38 +80,7 @@ func GenerateWithPrefix(schema *parser.Schema, prefix string) (map[string]string
 		},
 	}
 
-	templates := map[string]string{
-		"app.py":      appTemplate,
-		"errors.py":   errorsTemplate,
-		"handlers.py": handlersTemplate,
-		"models.py":   modelsTemplate,
-	}
-
-	files := make(map[string]string, len(templates))
-	for name, tmplText := range templates {
-		tmpl, err := template.New(name).Funcs(funcMap).Parse(tmplText)
-		if err != nil {
-			return nil, fmt.Errorf("parse template %s: %w", name, err)
-		}
-		var buf bytes.Buffer
-		buf.WriteString("# THIS CODE IS GENERATED\n\n")
-		if err := tmpl.Execute(&buf, data); err != nil {
-			return nil, fmt.Errorf("execute template %s: %w", name, err)
-		}
-		files[name] = buf.String()
-	}
-
-	ordered := make([]string, 0, len(files))
-	for name := range files {
-		ordered = append(ordered, name)
-	}
-	sort.Strings(ordered)
-	for _, name := range ordered {
-		if strings.TrimSpace(files[name]) == "" {
-			delete(files, name)
-		}
-	}
-	return files, nil
+	return overrides.Render(fileTemplates, data, funcMap)
 }
 
 func GenerateInit(schema *parser.Schema) string {
@@ -225,6 +211,15 @@ func hasParameters(rpc parser.RPC) bool {
 	return len(rpc.Parameters) > 0
 }
 
+func hasRequiredParameters(rpc parser.RPC) bool {
+	for _, param := range rpc.Parameters {
+		if !param.Type.Optional {
+			return true
+		}
+	}
+	return false
+}
+
 func hasReturn(rpc parser.RPC) bool {
 	return rpc.HasReturn
 }
@@ -1,32 +1,45 @@
 package tsgen
 
 import (
-	"bytes"
-	_ "embed"
+	"embed"
 	"fmt"
-	"sort"
 	"strings"
 	"text/template"
 	"unicode"
 
+	"github.com/Rapid-Vision/rRPC/internal/gen/override"
 	"github.com/Rapid-Vision/rRPC/internal/parser"
 	"github.com/Rapid-Vision/rRPC/internal/utils"
 )
 
-//go:embed errors.ts.tmpl
-var errorsTemplate string
-
-//go:embed models.ts.tmpl
-var modelsTemplate string
+//go:embed *.tmpl
+var templateFS embed.FS
+
+// fileTemplates renders the files of a generated TypeScript client package.
+var fileTemplates = override.Set{
+	FS:     templateFS,
+	Header: "header.ts.tmpl",
+	Files: map[string]string{
+		"errors.ts": "errors.ts.tmpl",
+		"models.ts": "models.ts.tmpl",
+		"client.ts": "client.ts.tmpl",
+	},
+}
 
-//go:embed client.ts.tmpl
-var clientTemplate string
+// Templates returns the names of the templates that can be overridden.
+func Templates() []string {
+	return fileTemplates.Names()
+}
 
+// templateData is the value every client template is executed with; see
+// docs/templates.md.
 type templateData struct {
 	Models []parser.Model
 	RPCs   []parser.RPC
+	// Prefix is the URL path prefix with a leading slash, or empty.
 	Prefix string
-	Zod    bool
+	// Zod is set when the client validates inputs with zod.
+	Zod bool
 }
 
 func GenerateClient(schema *parser.Schema) (map[string]string, error) {
@@ -41,6 +54,12 @@ func GenerateClientWithPrefix(schema *parser.Schema, prefix string) (map[string]
 }
 
 func GenerateClientWithPrefixAndZod(schema *parser.Schema, prefix string, zod bool) (map[string]string, error) {
+	return GenerateClientWithTemplates(schema, prefix, zod, nil)
+}
+
+// GenerateClientWithTemplates generates a client package, rendering
+// templates from overrides where given instead of the embedded ones.
+func GenerateClientWithTemplates(schema *parser.Schema, prefix string, zod bool, overrides override.Templates) (map[string]string, error) {
 	if schema == nil {
 		return nil, fmt.Errorf("schema is nil")
 	}
@@ -79,37 +98,7 @@ func GenerateClientWithPrefixAndZod(schema *parser.Schema, prefix string, zod bo
 		},
 	}
 
-	templates := map[string]string{
-		"errors.ts": errorsTemplate,
-		"models.ts": modelsTemplate,
-		"client.ts": clientTemplate,
-	}
-
-	files := make(map[string]string, len(templates))
-	for name, tmplText := range templates {
-		tmpl, err := template.New(name).Funcs(funcMap).Parse(tmplText)
-		if err != nil {
-			return nil, fmt.Errorf("parse template %s: %w", name, err)
-		}
-		var buf bytes.Buffer
-		buf.WriteString("// THIS CODE IS GENERATED\n\n")
-		if err := tmpl.Execute(&buf, data); err != nil {
-			return nil, fmt.Errorf("execute template %s: %w", name, err)
-		}
-		files[name] = buf.String()
-	}
-
-	ordered := make([]string, 0, len(files))
-	for name := range files {
-		ordered = append(ordered, name)
-	}
-	sort.Strings(ordered)
-	for _, name := range ordered {
-		if strings.TrimSpace(files[name]) == "" {
-			delete(files, name)
-		}
-	}
-	return files, nil
+	return overrides.Render(fileTemplates, data, funcMap)
 }
 
 func GenerateTypeScriptIndex(schema *parser.Schema) string {
@@ -0,0 +1,342 @@
+// Package importer converts schemas written in other formats into rRPC
+// schema source. Constructs that have no rRPC equivalent are reported as
+// warnings instead of being dropped silently.
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/formatter"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/protocol"
+	"github.com/Rapid-Vision/rRPC/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Warning is a construct of the source that was dropped or changed. Path
+// locates it in the source.
+type Warning struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (w Warning) String() string {
+	return w.Path + ": " + w.Message
+}
+
+// Result is an imported schema, formatted like rrpc format does.
+type Result struct {
+	Schema   string    `json:"schema"`
+	Warnings []Warning `json:"warnings"`
+}
+
+type field struct {
+	name string
+	doc  string
+	typ  string
+}
+
+type model struct {
+	name   string
+	doc    string
+	fields []field
+}
+
+type rpc struct {
+	name    string
+	doc     string
+	params  []field
+	returns string
+}
+
+// builder collects declarations and warnings and renders them as schema
+// source.
+type builder struct {
+	models   []model
+	rpcs     []rpc
+	names    utils.Set[string]
+	warnings []Warning
+}
+
+func newBuilder() *builder {
+	return &builder{names: utils.NewSet[string]()}
+}
+
+// warn records a warning once, as declarations may be converted more than
+// once.
+func (b *builder) warn(path, format string, args ...any) {
+	w := Warning{Path: path, Message: fmt.Sprintf(format, args...)}
+	if !slices.Contains(b.warnings, w) {
+		b.warnings = append(b.warnings, w)
+	}
+}
+
+// declName returns a free PascalCase declaration name based on name.
+func (b *builder) declName(name string) string {
+	base := utils.NewIdentifierName(identifier(name)).PascalCase()
+	if base == "" {
+		base = "Unnamed"
+	}
+	out := base
+	for i := 2; b.names.Has(out); i++ {
+		out = base + strconv.Itoa(i)
+	}
+	b.names.Add(out)
+	return out
+}
+
+// fieldName returns the rRPC field name for a wire key, warning when the
+// key cannot be kept on the wire.
+func (b *builder) fieldName(path, key string) string {
+	name := fieldIdentifier(key)
+	if wire := protocol.JSONName(name); wire != key {
+		b.warn(path, "field %s is sent as %q instead of %q", name, wire, key)
+	}
+	return name
+}
+
+// result formats the collected declarations. The source is parsed and
+// formatted, so the result is always a valid schema.
+func (b *builder) result() (*Result, error) {
+	var src strings.Builder
+	for _, m := range b.models {
+		writeDoc(&src, "", m.doc)
+		fmt.Fprintf(&src, "model %s {\n", m.name)
+		for _, f := range m.fields {
+			writeDoc(&src, "    ", f.doc)
+			fmt.Fprintf(&src, "    %s: %s\n", f.name, f.typ)
+		}
+		src.WriteString("}\n\n")
+	}
+	for _, r := range b.rpcs {
+		writeDoc(&src, "", r.doc)
+		if len(r.params) == 0 {
+			fmt.Fprintf(&src, "rpc %s() %s\n\n", r.name, r.returns)
+			continue
+		}
+		fmt.Fprintf(&src, "rpc %s(\n", r.name)
+		for _, p := range r.params {
+			writeDoc(&src, "    ", p.doc)
+			fmt.Fprintf(&src, "    %s: %s,\n", p.name, p.typ)
+		}
+		fmt.Fprintf(&src, ") %s\n\n", r.returns)
+	}
+	schema, err := parser.Parse(src.String())
+	if err != nil {
+		return nil, fmt.Errorf("imported schema is invalid: %w", err)
+	}
+	formatted, err := formatter.FormatSchema(schema)
+	if err != nil {
+		return nil, fmt.Errorf("format imported schema: %w", err)
+	}
+	warnings := b.warnings
+	if warnings == nil {
+		warnings = []Warning{}
+	}
+	return &Result{Schema: formatted, Warnings: warnings}, nil
+}
+
+func writeDoc(b *strings.Builder, indent, doc string) {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			b.WriteString(indent + "#\n")
+			continue
+		}
+		b.WriteString(indent + "# " + line + "\n")
+	}
+}
+
+// fieldIdentifier returns a field name that does not clash with keywords.
+func fieldIdentifier(name string) string {
+	name = identifier(name)
+	if name == "model" || name == "rpc" {
+		name += "_"
+	}
+	return name
+}
+
+// identifier replaces characters that are not allowed in rRPC identifiers.
+func identifier(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteRune('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+func optional(typ string, opt bool) string {
+	if opt && !strings.HasSuffix(typ, "?") {
+		return typ + "?"
+	}
+	return typ
+}
+
+// object is a decoded JSON or YAML mapping that keeps the order of its keys,
+// so declarations are imported in source order. Methods are safe to call on
+// a nil object.
+type object struct {
+	keys   []string
+	values map[string]any
+}
+
+func (o *object) get(key string) any {
+	if o == nil {
+		return nil
+	}
+	return o.values[key]
+}
+
+func (o *object) has(key string) bool {
+	if o == nil {
+		return false
+	}
+	_, ok := o.values[key]
+	return ok
+}
+
+func (o *object) obj(key string) *object {
+	v, _ := o.get(key).(*object)
+	return v
+}
+
+func (o *object) str(key string) string {
+	v, _ := o.get(key).(string)
+	return v
+}
+
+func (o *object) list(key string) []any {
+	v, _ := o.get(key).([]any)
+	return v
+}
+
+func (o *object) len() int {
+	if o == nil {
+		return 0
+	}
+	return len(o.keys)
+}
+
+func (o *object) each(fn func(key string, value any)) {
+	if o == nil {
+		return
+	}
+	for _, key := range o.keys {
+		fn(key, o.values[key])
+	}
+}
+
+// decode reads a JSON or YAML document.
+func decode(data []byte) (any, error) {
+	if json.Valid(data) {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		return decodeJSON(dec)
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	if len(node.Content) == 0 {
+		return nil, errors.New("empty document")
+	}
+	return decodeYAML(node.Content[0])
+}
+
+func decodeJSON(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		o := &object{values: make(map[string]any)}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyTok.(string)
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := o.values[key]; !dup {
+				o.keys = append(o.keys, key)
+			}
+			o.values[key] = value
+		}
+		_, err := dec.Token()
+		return o, err
+	case json.Delim('['):
+		items := []any{}
+		for dec.More() {
+			item, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		_, err := dec.Token()
+		return items, err
+	default:
+		return tok, nil
+	}
+}
+
+func decodeYAML(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return decodeYAML(node.Alias)
+	case yaml.MappingNode:
+		o := &object{values: make(map[string]any)}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			value, err := decodeYAML(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := o.values[key]; !dup {
+				o.keys = append(o.keys, key)
+			}
+			o.values[key] = value
+		}
+		return o, nil
+	case yaml.SequenceNode:
+		items := []any{}
+		for _, child := range node.Content {
+			item, err := decodeYAML(child)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	default:
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+}
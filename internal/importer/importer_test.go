@@ -0,0 +1,396 @@
+package importer_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Rapid-Vision/rRPC/internal/formatter"
+	"github.com/Rapid-Vision/rRPC/internal/gen/openapi"
+	"github.com/Rapid-Vision/rRPC/internal/importer"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+)
+
+const roundTripSchema = `model Empty {
+}
+
+model Text {
+    title: string?
+    body: string
+}
+
+model Nested {
+    text: Text
+    flags: map[bool]?
+    items: list[Text?]
+    data: json
+}
+
+rpc TestEmpty() Empty
+
+rpc TestNoReturn()
+
+rpc TestBasic(
+    text: Text,
+    count: int,
+    note: string?,
+) Text?
+
+rpc TestMap() map[Nested]
+
+rpc TestRaw(
+    flag: bool,
+) raw
+`
+
+func TestOpenAPIRoundTrip(t *testing.T) {
+	schema, err := parser.Parse(roundTripSchema)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	spec, err := openapi.GenerateWithPrefix(schema, "Test", "1.0.0", "rpc")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	result, err := importer.OpenAPI([]byte(spec))
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.Schema != roundTripSchema {
+		t.Fatalf("unexpected schema:\n%s", result.Schema)
+	}
+	if len(result.Warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", result.Warnings)
+	}
+}
+
+const petSpec = `openapi: 3.1.0
+info: {title: Pets, version: "1"}
+paths:
+  /api/pets:
+    get:
+      operationId: listPets
+      responses:
+        "200": {description: ok}
+  /api/create_pet:
+    post:
+      summary: Create a pet.
+      operationId: createPet
+      parameters:
+        - {name: X-Trace, in: header}
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string, description: The name.}
+                ownerId: {type: [integer, "null"], format: int64}
+                shape:
+                  type: object
+                  properties:
+                    weight: {type: number}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+        default:
+          description: error
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Error"}
+components:
+  schemas:
+    Pet:
+      allOf:
+        - $ref: "#/components/schemas/Named"
+        - type: object
+          required: [tags]
+          properties:
+            tags: {type: array, items: {type: string, enum: [a, b]}}
+            extra: {oneOf: [{type: string}, {type: integer}]}
+    Named:
+      type: object
+      properties:
+        name: {type: string}
+    Error:
+      type: object
+      properties:
+        code: {type: integer}
+`
+
+func TestOpenAPIWarnings(t *testing.T) {
+	result, err := importer.OpenAPI([]byte(petSpec))
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	want := `model Pet {
+    name: string?
+    tags: list[string]
+    extra: json?
+}
+
+model Named {
+    name: string?
+}
+
+model CreatePetShape {
+    weight: json?
+}
+
+# Create a pet.
+rpc CreatePet(
+    # The name.
+    name: string,
+    ownerId: int?,
+    shape: CreatePetShape?,
+) Pet
+`
+	if result.Schema != want {
+		t.Fatalf("unexpected schema:\n%s", result.Schema)
+	}
+	schema, err := parser.Parse(result.Schema)
+	if err != nil {
+		t.Fatalf("parse imported schema: %v", err)
+	}
+	if formatted, err := formatter.FormatSchema(schema); err != nil || formatted != result.Schema {
+		t.Fatalf("imported schema does not round-trip through the formatter: %v\n%s", err, formatted)
+	}
+
+	var warnings []string
+	for _, warning := range result.Warnings {
+		warnings = append(warnings, warning.String())
+	}
+	got := strings.Join(warnings, "\n")
+	for _, want := range []string{
+		"GET /api/pets: skipped, only POST operations are imported",
+		`POST /api/create_pet: header parameter "X-Trace" dropped`,
+		`ownerId: field ownerId is sent as "owner_id" instead of "ownerId"`,
+		"ownerId: int64 is imported as int",
+		"weight: number has no rRPC equivalent, imported as json",
+		`POST /api/create_pet: response is not wrapped in a result object, rRPC responds with {"pet": ...}`,
+		"#/components/schemas/Error: error schema not imported",
+		"#/components/schemas/Pet/properties/tags/items: enum values are not enforced",
+		"#/components/schemas/Pet/properties/extra: oneOf is imported as json",
+		`#/paths: operations are served under "/api", set the prefix when generating code`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing warning %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestOpenAPIErrors(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{`{"swagger": "2.0"}`, "swagger 2.0 documents are not supported"},
+		{`{"openapi": "4.0.0"}`, `unsupported OpenAPI version "4.0.0"`},
+		{`[1, 2]`, "expected an object"},
+		{"a: [", "parse spec"},
+	}
+	for _, tt := range tests {
+		_, err := importer.OpenAPI([]byte(tt.spec))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.spec, tt.want, err)
+		}
+	}
+}
+
+const userProto = `syntax = "proto3";
+
+package demo.v1;
+
+import "google/protobuf/empty.proto";
+import "google/protobuf/timestamp.proto";
+
+option go_package = "example.com/demo;demo";
+
+// A user of the service.
+message User {
+  int32 id = 1; // Not a doc comment.
+  optional string email = 2 [json_name = "mail"];
+  repeated string tags = 3;
+  map<string, Address> addresses = 4;
+  google.protobuf.Timestamp created_at = 5;
+  Role role = 6;
+  Address home = 7;
+  int64 views = 8;
+  oneof contact {
+    string phone = 9;
+    string fax = 10;
+  }
+  reserved 11, 12;
+
+  message Address {
+    string city = 1;
+  }
+
+  enum Role {
+    ROLE_UNSPECIFIED = 0;
+    ROLE_ADMIN = 1;
+  }
+}
+
+message GetUserRequest {
+  // Id of the user.
+  int32 id = 1;
+}
+
+message ListUsersResponse {
+  repeated User users = 1;
+}
+
+service UserService {
+  // Gets a user.
+  rpc GetUser(GetUserRequest) returns (User);
+  rpc ListUsers(google.protobuf.Empty) returns (ListUsersResponse) {
+    option (google.api.http) = { get: "/v1/users" };
+  }
+  rpc WatchUser(GetUserRequest) returns (stream User);
+  rpc DeleteUser(.demo.v1.GetUserRequest) returns (google.protobuf.Empty);
+}
+`
+
+func TestProto(t *testing.T) {
+	result, err := importer.Proto([]byte(userProto))
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	want := `# A user of the service.
+model User {
+    id: int
+    email: string?
+    tags: list[string]
+    addresses: map[UserAddress]
+    created_at: string
+    role: string
+    home: UserAddress?
+    views: int
+    phone: string?
+    fax: string?
+}
+
+model UserAddress {
+    city: string
+}
+
+model ListUsersResponse {
+    users: list[User]
+}
+
+# Gets a user.
+rpc GetUser(
+    # Id of the user.
+    id: int,
+) User
+
+rpc ListUsers() ListUsersResponse
+
+rpc WatchUser(
+    # Id of the user.
+    id: int,
+) User
+
+rpc DeleteUser(
+    # Id of the user.
+    id: int,
+)
+`
+	if result.Schema != want {
+		t.Fatalf("unexpected schema:\n%s", result.Schema)
+	}
+
+	var warnings []string
+	for _, warning := range result.Warnings {
+		warnings = append(warnings, warning.String())
+	}
+	wantWarnings := []string{
+		"rpc UserService.WatchUser: server streaming is not supported, imported as a unary call",
+		"message User.created_at: Timestamp is imported as an RFC 3339 string",
+		"message User.role: enum Role is imported as string, its values are not checked",
+		"message User.views: int64 is imported as int, which is 32-bit in some languages",
+		"message User.contact: oneof is imported as optional fields, only one of which should be set",
+	}
+	if got := strings.Join(warnings, "\n"); got != strings.Join(wantWarnings, "\n") {
+		t.Fatalf("unexpected warnings:\n%s", got)
+	}
+}
+
+func TestProtoErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`syntax = "proto2";`, `only proto3 files are supported, got syntax "proto2"`},
+		{`edition = "2023";`, "only proto3 files are supported"},
+		{`message A { required string a = 1; }`, "required fields are not allowed in proto3"},
+		{`message A { string a = 1;`, "unexpected end of file in message A"},
+		{`/* open`, "unterminated comment"},
+		{`service S { message A {} }`, `unexpected "message" in service S`},
+	}
+	for _, tt := range tests {
+		_, err := importer.Proto([]byte(tt.src))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.src, tt.want, err)
+		}
+	}
+}
+
+func TestOpenAPIRecursiveAllOf(t *testing.T) {
+	for name, schemas := range map[string]string{
+		"allOf only": `"A": {"allOf": [{"$ref": "#/components/schemas/A"}, {"$ref": "#/components/schemas/B"}]},
+			"B": {"type": "object", "properties": {"name": {"type": "string"}}}`,
+		"properties": `"A": {"allOf": [{"$ref": "#/components/schemas/A"}], "properties": {"name": {"type": "string"}}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			spec := `{"openapi": "3.0.0", "paths": {}, "components": {"schemas": {` + schemas + `}}}`
+			result, err := importer.OpenAPI([]byte(spec))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(result.Schema, "model A {\n    name: string?\n}") {
+				t.Fatalf("unexpected schema:\n%s", result.Schema)
+			}
+			var warnings []string
+			for _, warning := range result.Warnings {
+				warnings = append(warnings, warning.String())
+			}
+			want := "#/components/schemas/A: recursive allOf reference #/components/schemas/A was ignored"
+			if !slices.Contains(warnings, want) {
+				t.Fatalf("missing warning %q in %v", want, warnings)
+			}
+		})
+	}
+}
+
+func TestOpenAPISkippedComponents(t *testing.T) {
+	spec := `{"openapi": "3.0.0", "paths": {}, "components": {"schemas": {
+		"Color": {"type": "string", "enum": ["red", "green"]},
+		"Empty": {"type": "object"},
+		"Blank": {"type": "object", "properties": {}}
+	}}}`
+	result, err := importer.OpenAPI([]byte(spec))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Schema != "" {
+		t.Fatalf("unexpected schema:\n%s", result.Schema)
+	}
+	var warnings []string
+	for _, warning := range result.Warnings {
+		warnings = append(warnings, warning.String())
+	}
+	want := []string{
+		"#/components/schemas/Color: skipped, only object schemas with properties become models",
+		"#/components/schemas/Empty: skipped, only object schemas with properties become models",
+		"#/components/schemas/Blank: skipped, the object has no properties and is not referenced",
+	}
+	if !slices.Equal(warnings, want) {
+		t.Fatalf("unexpected warnings:\n%s", strings.Join(warnings, "\n"))
+	}
+}
@@ -0,0 +1,598 @@
+package importer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/protocol"
+	"github.com/Rapid-Vision/rRPC/internal/utils"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// errorSchemaName is the error schema of specs generated by rrpc openapi.
+const errorSchemaName = "RPCError"
+
+var skippedMethods = []string{"get", "put", "delete", "patch", "head", "options", "trace"}
+
+type openapiImporter struct {
+	*builder
+	root    *object
+	schemas *object
+	// models maps the components that become models to their names.
+	models map[string]string
+	// used holds components referenced as a type; wrappers holds those
+	// used as request bodies, result wrappers or errors. Wrappers are only
+	// declared when they are also used as a type.
+	used      utils.Set[string]
+	wrappers  utils.Set[string]
+	refs      utils.Set[string]
+	resolving utils.Set[string]
+	inline    []model
+	prefixes  []string
+}
+
+// OpenAPI converts an OpenAPI 3 document in JSON or YAML. Component
+// schemas become models and POST operations with JSON bodies become RPCs.
+func OpenAPI(data []byte) (*Result, error) {
+	doc, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	root, ok := doc.(*object)
+	if !ok {
+		return nil, fmt.Errorf("parse spec: expected an object")
+	}
+	if root.has("swagger") {
+		return nil, fmt.Errorf("swagger %v documents are not supported, convert them to OpenAPI 3 first", root.get("swagger"))
+	}
+	if version := root.str("openapi"); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q (expected 3.x)", version)
+	}
+	imp := &openapiImporter{
+		builder:   newBuilder(),
+		root:      root,
+		schemas:   root.obj("components").obj("schemas"),
+		models:    make(map[string]string),
+		used:      utils.NewSet[string](),
+		wrappers:  utils.NewSet[string](),
+		resolving: utils.NewSet[string](),
+		refs:      utils.NewSet[string](),
+	}
+	collectRefs(root, imp.refs)
+	imp.schemas.each(func(name string, value any) {
+		if s, ok := value.(*object); ok && imp.isModel(s) {
+			imp.models[name] = imp.declName(strings.TrimSuffix(name, "Model"))
+		}
+	})
+	root.obj("paths").each(imp.path)
+	imp.declareModels()
+	imp.models = nil
+	imp.builder.models = append(imp.builder.models, imp.inline...)
+
+	if len(imp.prefixes) > 1 {
+		imp.warn("#/paths", "operations use different path prefixes (%s), rRPC serves all RPCs under one prefix", strings.Join(imp.prefixes, ", "))
+	} else if len(imp.prefixes) == 1 && imp.prefixes[0] != "/rpc" {
+		imp.warn("#/paths", "operations are served under %q, set the prefix when generating code", imp.prefixes[0])
+	}
+	if root.obj("components").has("securitySchemes") {
+		imp.warn("#/components/securitySchemes", "security schemes are not imported, authentication is left to server code")
+	}
+	return imp.result()
+}
+
+// declareModels declares the model components that are not only wrappers,
+// in source order. Converting a model can mark more components as used, so
+// this repeats until nothing changes. Unreferenced components that are not
+// declared are reported.
+func (imp *openapiImporter) declareModels() {
+	declared := make(map[string]model)
+	for changed := true; changed; {
+		changed = false
+		imp.schemas.each(func(name string, value any) {
+			modelName, ok := imp.models[name]
+			if !ok {
+				return
+			}
+			if _, done := declared[name]; done || (imp.wrappers.Has(name) && !imp.used.Has(name)) {
+				return
+			}
+			s := value.(*object)
+			// Unreferenced objects without properties, such as the params of
+			// parameterless RPCs in specs from rrpc openapi, carry nothing.
+			if s.obj("properties").len() == 0 && !imp.refs.Has(schemaRefPrefix+name) {
+				return
+			}
+			path := schemaRefPrefix + name
+			declared[name] = model{
+				name:   modelName,
+				doc:    description(s),
+				fields: imp.fields(path, s, modelName),
+			}
+			changed = true
+		})
+	}
+	imp.schemas.each(func(name string, _ any) {
+		path := schemaRefPrefix + name
+		if m, ok := declared[name]; ok {
+			imp.builder.models = append(imp.builder.models, m)
+			return
+		}
+		if imp.refs.Has(path) {
+			return
+		}
+		if _, ok := imp.models[name]; !ok {
+			imp.warn(path, "skipped, only object schemas with properties become models")
+		} else if !imp.emptyParams(name) {
+			imp.warn(path, "skipped, the object has no properties and is not referenced")
+		}
+	})
+}
+
+// emptyParams reports whether a component is the params schema rrpc
+// openapi writes for an imported RPC without parameters.
+func (imp *openapiImporter) emptyParams(name string) bool {
+	rpcName, ok := strings.CutSuffix(name, "Params")
+	if !ok {
+		return false
+	}
+	for _, r := range imp.rpcs {
+		if r.name == rpcName && len(r.params) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (imp *openapiImporter) path(route string, value any) {
+	item, _ := value.(*object)
+	item.each(func(method string, value any) {
+		op, _ := value.(*object)
+		switch {
+		case method == "post":
+			imp.operation(route, op, item.list("parameters"))
+		case slices.Contains(skippedMethods, method):
+			imp.warn(strings.ToUpper(method)+" "+route, "skipped, only POST operations are imported")
+		}
+	})
+}
+
+func (imp *openapiImporter) operation(route string, op *object, shared []any) {
+	path := "POST " + route
+	for _, param := range append(shared, op.list("parameters")...) {
+		p := imp.resolve(param, "#/components/parameters/")
+		imp.warn(path, "%s parameter %q dropped, only the JSON body is supported", p.str("in"), p.str("name"))
+	}
+	if op.has("callbacks") {
+		imp.warn(path, "callbacks are not imported")
+	}
+
+	body := imp.resolve(op.get("requestBody"), "#/components/requestBodies/")
+	var media *object
+	if body != nil {
+		if media = body.obj("content").obj("application/json"); media == nil {
+			imp.warn(path, "skipped, the request body is not application/json")
+			return
+		}
+	}
+
+	name := op.str("operationId")
+	if name == "" {
+		name = strings.ReplaceAll(strings.Trim(route, "/"), "/", "_")
+	}
+	rpcName := imp.declName(name)
+	var params []field
+	if media != nil {
+		params = imp.params(path, media.get("schema"), rpcName)
+	}
+	dir, last := route[:strings.LastIndex(route, "/")+1], route[strings.LastIndex(route, "/")+1:]
+	prefix := strings.TrimSuffix(dir, "/")
+	if !slices.Contains(imp.prefixes, prefix) {
+		imp.prefixes = append(imp.prefixes, prefix)
+	}
+	if wire := utils.NewIdentifierName(rpcName).SnakeCase(); wire != last {
+		imp.warn(path, "served by rRPC at %s", protocol.RPCPath(prefix, rpcName))
+	}
+
+	doc := op.str("summary")
+	if d := op.str("description"); d != "" && d != doc {
+		doc = strings.TrimSpace(doc + "\n\n" + d)
+	}
+	imp.rpcs = append(imp.rpcs, rpc{
+		name:    rpcName,
+		doc:     doc,
+		params:  params,
+		returns: imp.returns(path, op.obj("responses"), rpcName),
+	})
+}
+
+func (imp *openapiImporter) params(path string, schema any, rpcName string) []field {
+	s, component := imp.component(schema)
+	if component != "" {
+		imp.wrappers.Add(component)
+	}
+	if s == nil || !imp.isModel(s) {
+		imp.warn(path, "request body is not an object and was dropped")
+		return nil
+	}
+	return imp.fields(path+" request body", s, rpcName)
+}
+
+func (imp *openapiImporter) returns(path string, responses *object, rpcName string) string {
+	var success *object
+	responses.each(func(code string, value any) {
+		response := imp.resolve(value, "#/components/responses/")
+		if strings.HasPrefix(code, "2") {
+			if success == nil {
+				success = response
+			}
+			return
+		}
+		schema := response.obj("content").obj("application/json").get("schema")
+		if schema == nil {
+			return
+		}
+		if _, component := imp.component(schema); component != "" {
+			if !imp.wrappers.Has(component) && component != errorSchemaName {
+				imp.warn(schemaRefPrefix+component, "error schema not imported, rRPC errors have a fixed shape (see docs/errors.md)")
+			}
+			imp.wrappers.Add(component)
+			return
+		}
+		imp.warn(path, "%s response schema dropped, rRPC errors have a fixed shape (see docs/errors.md)", code)
+	})
+	content := success.obj("content")
+	media := content.obj("application/json")
+	if media == nil {
+		if content != nil && len(content.keys) > 0 {
+			imp.warn(path, "response is not application/json and was dropped")
+		}
+		return ""
+	}
+	schema := media.get("schema")
+	s, component := imp.component(schema)
+	if props := s.obj("properties"); props != nil && !s.has("allOf") {
+		switch len(props.keys) {
+		case 0:
+			if component != "" {
+				imp.wrappers.Add(component)
+			}
+			return ""
+		case 1:
+			key, value := props.keys[0], props.get(props.keys[0])
+			want := imp.resultKey(value)
+			if key == "raw" && want == "json" {
+				// raw and json are both exported as an empty schema, the
+				// result key tells them apart.
+				if component != "" {
+					imp.wrappers.Add(component)
+				}
+				return optional("raw", props.obj(key).get("nullable") == true)
+			}
+			if key == want {
+				if component != "" {
+					imp.wrappers.Add(component)
+				}
+				return imp.typeOf(path+" response", value, rpcName+"Result")
+			}
+		}
+	}
+	t := imp.typeOf(path+" response", schema, rpcName+"Result")
+	imp.warn(path, "response is not wrapped in a result object, rRPC responds with {%q: ...}", imp.resultKeyOf(t))
+	return t
+}
+
+// resultKey returns the result key rRPC would use for a schema: the
+// snake_case type name for models and builtin types, and "result" for
+// lists and maps. It does not convert the schema.
+func (imp *openapiImporter) resultKey(schema any) string {
+	s, _ := schema.(*object)
+	if all := s.list("allOf"); len(all) == 1 {
+		s, _ = all[0].(*object)
+	}
+	if ref := s.str("$ref"); strings.HasPrefix(ref, schemaRefPrefix) {
+		if name, ok := imp.models[strings.TrimPrefix(ref, schemaRefPrefix)]; ok {
+			return imp.resultKeyOf(name)
+		}
+		return "result"
+	}
+	switch typ, _ := schemaType(s); typ {
+	case "string":
+		return "string"
+	case "boolean":
+		return "bool"
+	case "integer":
+		return "int"
+	case "":
+		if s != nil && len(s.keys) == 0 {
+			return "json"
+		}
+	}
+	return "result"
+}
+
+func (imp *openapiImporter) resultKeyOf(t string) string {
+	name := strings.TrimSuffix(t, "?")
+	if strings.Contains(name, "[") {
+		return "result"
+	}
+	return utils.NewIdentifierName(name).SnakeCase()
+}
+
+// fields converts the properties of an object schema, including those of
+// allOf parts. hint prefixes the names of inline models.
+func (imp *openapiImporter) fields(path string, s *object, hint string) []field {
+	if ap := s.get("additionalProperties"); ap != nil && ap != false {
+		imp.warn(path, "additionalProperties next to properties is not supported and was dropped")
+	}
+	type property struct {
+		key   string
+		field field
+	}
+	required := utils.NewSet[string]()
+	var properties []property
+	// resolving holds the schemas whose allOf parts are being collected.
+	resolving := utils.NewSet[*object]()
+	var collect func(s *object)
+	collect = func(s *object) {
+		resolving.Add(s)
+		defer delete(resolving, s)
+		for _, part := range s.list("allOf") {
+			p, name := imp.component(part)
+			if p == nil {
+				continue
+			}
+			if resolving.Has(p) {
+				imp.warn(path, "recursive allOf reference %s was ignored", schemaRefPrefix+name)
+				continue
+			}
+			collect(p)
+		}
+		for _, name := range s.list("required") {
+			if name, ok := name.(string); ok {
+				required.Add(name)
+			}
+		}
+		s.obj("properties").each(func(key string, value any) {
+			fieldPath := path + "/properties/" + key
+			prop, _ := value.(*object)
+			properties = append(properties, property{key, field{
+				name: imp.fieldName(fieldPath, key),
+				doc:  description(prop),
+				typ:  imp.typeOf(fieldPath, value, hint+utils.NewIdentifierName(identifier(key)).PascalCase()),
+			}})
+		})
+	}
+	collect(s)
+	out := make([]field, 0, len(properties))
+	for _, p := range properties {
+		p.field.typ = optional(p.field.typ, !required.Has(p.key))
+		out = append(out, p.field)
+	}
+	return out
+}
+
+// typeOf converts a schema to an rRPC type. hint names inline models.
+func (imp *openapiImporter) typeOf(path string, schema any, hint string) string {
+	s, ok := schema.(*object)
+	if !ok {
+		return "json"
+	}
+	nullable := s.get("nullable") == true
+	if ref := s.str("$ref"); ref != "" {
+		return optional(imp.refType(path, ref), nullable)
+	}
+	if all := s.list("allOf"); len(all) > 0 {
+		if len(all) == 1 && !s.has("properties") {
+			return optional(imp.typeOf(path, all[0], hint), nullable)
+		}
+		if imp.isModel(s) {
+			return optional(imp.inlineModel(path, s, hint), nullable)
+		}
+		imp.warn(path, "allOf of non-object schemas is imported as json")
+		return optional("json", nullable)
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		alternatives := s.list(key)
+		if len(alternatives) == 0 {
+			continue
+		}
+		var rest []any
+		for _, alt := range alternatives {
+			if typ, null := schemaType(alt); null && typ == "" {
+				nullable = true
+				continue
+			}
+			rest = append(rest, alt)
+		}
+		if len(rest) == 1 {
+			return optional(imp.typeOf(path, rest[0], hint), nullable)
+		}
+		imp.warn(path, "%s is imported as json", key)
+		return optional("json", nullable)
+	}
+
+	typ, null := schemaType(s)
+	nullable = nullable || null
+	switch typ {
+	case "string":
+		if s.has("enum") {
+			imp.warn(path, "enum values are not enforced, imported as string")
+		}
+		return optional("string", nullable)
+	case "integer":
+		if s.str("format") == "int64" {
+			imp.warn(path, "int64 is imported as int")
+		}
+		return optional("int", nullable)
+	case "number":
+		imp.warn(path, "number has no rRPC equivalent, imported as json")
+		return optional("json", nullable)
+	case "boolean":
+		return optional("bool", nullable)
+	case "array":
+		if !s.has("items") {
+			return optional("list[json]", nullable)
+		}
+		return optional("list["+imp.typeOf(path+"/items", s.get("items"), hint+"Item")+"]", nullable)
+	case "object", "":
+		if s.has("properties") {
+			return optional(imp.inlineModel(path, s, hint), nullable)
+		}
+		if ap, ok := s.get("additionalProperties").(*object); ok && len(ap.keys) > 0 {
+			return optional("map["+imp.typeOf(path+"/additionalProperties", ap, hint+"Value")+"]", nullable)
+		}
+		if typ == "object" || s.has("additionalProperties") {
+			return optional("map[json]", nullable)
+		}
+		return optional("json", nullable)
+	default:
+		imp.warn(path, "type %s is imported as json", typ)
+		return optional("json", nullable)
+	}
+}
+
+func (imp *openapiImporter) refType(path, ref string) string {
+	if !strings.HasPrefix(ref, schemaRefPrefix) {
+		imp.warn(path, "external reference %s is imported as json", ref)
+		return "json"
+	}
+	name := strings.TrimPrefix(ref, schemaRefPrefix)
+	if modelName, ok := imp.models[name]; ok {
+		imp.used.Add(name)
+		return modelName
+	}
+	if !imp.schemas.has(name) {
+		imp.warn(path, "unknown reference %s is imported as json", ref)
+		return "json"
+	}
+	if imp.resolving.Has(name) {
+		imp.warn(path, "recursive reference %s is imported as json", ref)
+		return "json"
+	}
+	imp.resolving.Add(name)
+	defer delete(imp.resolving, name)
+	return imp.typeOf(path, imp.schemas.get(name), strings.TrimSuffix(name, "Model"))
+}
+
+func (imp *openapiImporter) inlineModel(path string, s *object, hint string) string {
+	name := imp.declName(hint)
+	m := model{name: name, doc: description(s)}
+	m.fields = imp.fields(path, s, name)
+	imp.inline = append(imp.inline, m)
+	return name
+}
+
+// isModel reports whether a schema is an object with properties, directly
+// or through allOf. allOf parts that include the schema itself are
+// skipped; fields warns about them.
+func (imp *openapiImporter) isModel(s *object) bool {
+	resolving := utils.NewSet[*object]()
+	var check func(s *object) bool
+	check = func(s *object) bool {
+		if s.has("properties") {
+			return true
+		}
+		all := s.list("allOf")
+		if len(all) < 2 {
+			return false
+		}
+		resolving.Add(s)
+		defer delete(resolving, s)
+		found := false
+		for _, part := range all {
+			p, _ := imp.component(part)
+			if p != nil && resolving.Has(p) {
+				continue
+			}
+			if p == nil || !check(p) {
+				return false
+			}
+			found = true
+		}
+		return found
+	}
+	return check(s)
+}
+
+// component follows a reference to a component schema, returning the
+// schema and the component name, which is empty for inline schemas.
+func (imp *openapiImporter) component(schema any) (*object, string) {
+	s, _ := schema.(*object)
+	if ref := s.str("$ref"); strings.HasPrefix(ref, schemaRefPrefix) {
+		name := strings.TrimPrefix(ref, schemaRefPrefix)
+		return imp.schemas.obj(name), name
+	}
+	return s, ""
+}
+
+// resolve follows a $ref to another kind of component, such as a
+// parameter or response.
+func (imp *openapiImporter) resolve(value any, prefix string) *object {
+	o, _ := value.(*object)
+	for i := 0; i < 8; i++ {
+		ref := o.str("$ref")
+		if !strings.HasPrefix(ref, prefix) {
+			break
+		}
+		kind := strings.TrimSuffix(strings.TrimPrefix(prefix, "#/components/"), "/")
+		o = imp.root.obj("components").obj(kind).obj(strings.TrimPrefix(ref, prefix))
+	}
+	return o
+}
+
+// schemaType returns the type of a schema and whether it allows null,
+// accepting OpenAPI 3.1 type arrays.
+func schemaType(schema any) (string, bool) {
+	s, _ := schema.(*object)
+	switch typ := s.get("type").(type) {
+	case string:
+		if typ == "null" {
+			return "", true
+		}
+		return typ, false
+	case []any:
+		var types []string
+		null := false
+		for _, t := range typ {
+			if t == "null" {
+				null = true
+			} else if t, ok := t.(string); ok {
+				types = append(types, t)
+			}
+		}
+		if len(types) == 1 {
+			return types[0], null
+		}
+		if len(types) == 0 {
+			return "", null
+		}
+		return strings.Join(types, "|"), null
+	}
+	return "", false
+}
+
+// collectRefs adds every $ref in a document to refs.
+func collectRefs(value any, refs utils.Set[string]) {
+	switch v := value.(type) {
+	case *object:
+		if ref := v.str("$ref"); ref != "" {
+			refs.Add(ref)
+		}
+		v.each(func(_ string, value any) {
+			collectRefs(value, refs)
+		})
+	case []any:
+		for _, item := range v {
+			collectRefs(item, refs)
+		}
+	}
+}
+
+func description(s *object) string {
+	if d := s.str("description"); d != "" {
+		return d
+	}
+	return s.str("title")
+}
@@ -0,0 +1,773 @@
+package importer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/Rapid-Vision/rRPC/internal/utils"
+)
+
+// Proto converts a proto3 file. Messages become models and the methods of
+// all services become RPCs, with the fields of the request message as
+// parameters.
+func Proto(data []byte) (*Result, error) {
+	file, err := parseProto(string(data))
+	if err != nil {
+		return nil, err
+	}
+	imp := &protoImporter{
+		builder:    newBuilder(),
+		file:       file,
+		modelNames: make(map[string]string),
+		used:       utils.NewSet[string](),
+		requests:   utils.NewSet[string](),
+	}
+	for _, message := range file.messages {
+		if _, ok := wellKnownTypes[message.name]; !ok {
+			imp.modelNames[message.name] = imp.declName(strings.ReplaceAll(message.name, ".", "_"))
+		}
+	}
+	if len(file.services) > 1 {
+		names := make([]string, 0, len(file.services))
+		for _, service := range file.services {
+			names = append(names, service.name)
+		}
+		imp.warn("service "+names[0], "services %s are merged into one schema", strings.Join(names, ", "))
+	}
+	for _, service := range file.services {
+		for _, method := range service.rpcs {
+			imp.rpc(service, method)
+		}
+	}
+	imp.declareModels()
+	return imp.result()
+}
+
+// wellKnownTypes maps the google.protobuf types that have an rRPC
+// equivalent to it. Empty maps to no type.
+var wellKnownTypes = map[string]string{
+	"google.protobuf.Empty":       "",
+	"google.protobuf.Struct":      "map[json]",
+	"google.protobuf.Value":       "json",
+	"google.protobuf.ListValue":   "list[json]",
+	"google.protobuf.StringValue": "string?",
+	"google.protobuf.BoolValue":   "bool?",
+	"google.protobuf.Int32Value":  "int?",
+	"google.protobuf.UInt32Value": "int?",
+}
+
+// lossyWellKnownTypes are imported with a warning.
+var lossyWellKnownTypes = map[string]struct {
+	typ  string
+	note string
+}{
+	"google.protobuf.Timestamp":   {"string", "Timestamp is imported as an RFC 3339 string"},
+	"google.protobuf.Duration":    {"string", `Duration is imported as a string such as "1.5s"`},
+	"google.protobuf.FieldMask":   {"string", "FieldMask is imported as a comma-separated string"},
+	"google.protobuf.Any":         {"json", "Any is imported as json"},
+	"google.protobuf.BytesValue":  {"string?", "BytesValue is imported as a base64 string"},
+	"google.protobuf.Int64Value":  {"int?", "Int64Value is imported as int"},
+	"google.protobuf.UInt64Value": {"int?", "UInt64Value is imported as int"},
+	"google.protobuf.DoubleValue": {"json", "DoubleValue has no rRPC equivalent, imported as json"},
+	"google.protobuf.FloatValue":  {"json", "FloatValue has no rRPC equivalent, imported as json"},
+}
+
+type protoImporter struct {
+	*builder
+	file *protoFile
+	// modelNames maps message names to model names. Request messages are
+	// only declared when they are also used as a field type.
+	modelNames map[string]string
+	used       utils.Set[string]
+	requests   utils.Set[string]
+}
+
+func (imp *protoImporter) rpc(service *protoService, method protoRPC) {
+	path := "rpc " + service.name + "." + method.name
+	if method.clientStream || method.serverStream {
+		var kinds []string
+		if method.clientStream {
+			kinds = append(kinds, "client")
+		}
+		if method.serverStream {
+			kinds = append(kinds, "server")
+		}
+		imp.warn(path, "%s streaming is not supported, imported as a unary call", strings.Join(kinds, " and "))
+	}
+	r := rpc{name: imp.declName(method.name), doc: method.doc}
+
+	request := imp.resolve(method.request, "")
+	switch {
+	case request == "google.protobuf.Empty":
+	case imp.message(request) != nil:
+		imp.requests.Add(request)
+		r.params = imp.fields(imp.message(request))
+	default:
+		imp.warn(path, "request type %s is not a message of this file, imported without parameters", method.request)
+	}
+	r.returns = imp.typeOf(path+" returns", method.response, "", true)
+	imp.rpcs = append(imp.rpcs, r)
+}
+
+func (imp *protoImporter) declareModels() {
+	declared := utils.NewSet[string]()
+	for changed := true; changed; {
+		changed = false
+		for _, message := range imp.file.messages {
+			if _, ok := imp.modelNames[message.name]; !ok || declared.Has(message.name) {
+				continue
+			}
+			if imp.requests.Has(message.name) && !imp.used.Has(message.name) {
+				continue
+			}
+			declared.Add(message.name)
+			message.converted = imp.fields(message)
+			changed = true
+		}
+	}
+	for _, message := range imp.file.messages {
+		if declared.Has(message.name) {
+			imp.models = append(imp.models, model{
+				name:   imp.modelNames[message.name],
+				doc:    message.doc,
+				fields: message.converted,
+			})
+		}
+	}
+}
+
+func (imp *protoImporter) fields(message *protoMessage) []field {
+	out := make([]field, 0, len(message.fields))
+	oneofs := utils.NewSet[string]()
+	for _, f := range message.fields {
+		path := "message " + message.name + "." + f.name
+		if f.oneof != "" && !oneofs.Has(f.oneof) {
+			oneofs.Add(f.oneof)
+			imp.warn("message "+message.name+"."+f.oneof, "oneof is imported as optional fields, only one of which should be set")
+		}
+		typ := imp.typeOf(path, f.typ, message.name, f.key != "" || f.label == "repeated")
+		switch {
+		case typ == "":
+			imp.warn(path, "Empty is imported as json")
+			typ = "json"
+		case f.key != "":
+			typ = "map[" + typ + "]"
+		case f.label == "repeated":
+			typ = "list[" + typ + "]"
+		}
+		out = append(out, field{
+			name: fieldIdentifier(f.name),
+			doc:  f.doc,
+			typ:  optional(typ, f.label == "optional" || f.oneof != ""),
+		})
+	}
+	return out
+}
+
+// typeOf converts a type reference used in scope. Message fields have
+// presence in proto3 and are optional, unless bare is set for list and map
+// elements and return types.
+func (imp *protoImporter) typeOf(path, name, scope string, bare bool) string {
+	switch name {
+	case "string":
+		return "string"
+	case "bool":
+		return "bool"
+	case "int32", "sint32", "sfixed32":
+		return "int"
+	case "uint32", "fixed32":
+		imp.warn(path, "%s is imported as int, values above 2^31-1 may not fit", name)
+		return "int"
+	case "int64", "sint64", "sfixed64", "uint64", "fixed64":
+		imp.warn(path, "%s is imported as int, which is 32-bit in some languages", name)
+		return "int"
+	case "double", "float":
+		imp.warn(path, "%s has no rRPC equivalent, imported as json", name)
+		return "json"
+	case "bytes":
+		imp.warn(path, "bytes is imported as a base64 string")
+		return "string"
+	}
+	resolved := imp.resolve(name, scope)
+	if typ, ok := wellKnownTypes[resolved]; ok {
+		return bareType(typ, bare)
+	}
+	if lossy, ok := lossyWellKnownTypes[resolved]; ok {
+		imp.warn(path, "%s", lossy.note)
+		return bareType(lossy.typ, bare)
+	}
+	if imp.file.enums.Has(resolved) {
+		imp.warn(path, "enum %s is imported as string, its values are not checked", name)
+		return "string"
+	}
+	if modelName, ok := imp.modelNames[resolved]; ok {
+		imp.used.Add(resolved)
+		return optional(modelName, !bare)
+	}
+	imp.warn(path, "unknown type %s is imported as json", name)
+	return "json"
+}
+
+func bareType(typ string, bare bool) string {
+	if bare {
+		return strings.TrimSuffix(typ, "?")
+	}
+	return typ
+}
+
+// resolve returns the package-relative name of a type reference used in
+// scope, following protobuf scoping rules within the file.
+func (imp *protoImporter) resolve(name, scope string) string {
+	if strings.HasPrefix(name, ".") {
+		name = strings.TrimPrefix(name[1:], imp.file.pkg+".")
+		return name
+	}
+	if imp.file.pkg != "" {
+		if rest, ok := strings.CutPrefix(name, imp.file.pkg+"."); ok && imp.known(rest) {
+			return rest
+		}
+	}
+	for scope != "" {
+		if candidate := scope + "." + name; imp.known(candidate) {
+			return candidate
+		}
+		i := strings.LastIndex(scope, ".")
+		if i < 0 {
+			break
+		}
+		scope = scope[:i]
+	}
+	return name
+}
+
+func (imp *protoImporter) known(name string) bool {
+	return imp.message(name) != nil || imp.file.enums.Has(name)
+}
+
+func (imp *protoImporter) message(name string) *protoMessage {
+	for _, message := range imp.file.messages {
+		if message.name == name {
+			return message
+		}
+	}
+	return nil
+}
+
+type protoFile struct {
+	pkg      string
+	messages []*protoMessage
+	enums    utils.Set[string]
+	services []*protoService
+}
+
+// protoMessage is a message; nested messages are listed separately with a
+// dotted name.
+type protoMessage struct {
+	name      string
+	doc       string
+	fields    []protoField
+	converted []field
+}
+
+type protoField struct {
+	name  string
+	doc   string
+	label string
+	typ   string
+	// key is the key type of map fields, whose typ is the value type.
+	key   string
+	oneof string
+}
+
+type protoService struct {
+	name string
+	rpcs []protoRPC
+}
+
+type protoRPC struct {
+	name         string
+	doc          string
+	request      string
+	response     string
+	clientStream bool
+	serverStream bool
+}
+
+type protoToken struct {
+	text string
+	doc  string
+	line int
+	col  int
+	// str is set for string literals, whose text is unquoted.
+	str bool
+}
+
+type protoParser struct {
+	tokens []protoToken
+	pos    int
+	file   *protoFile
+}
+
+func parseProto(src string) (*protoFile, error) {
+	tokens, err := tokenizeProto(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &protoParser{tokens: tokens, file: &protoFile{enums: utils.NewSet[string]()}}
+	if err := p.parseFile(); err != nil {
+		return nil, err
+	}
+	return p.file, nil
+}
+
+func (p *protoParser) peek() protoToken {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	line := 1
+	if len(p.tokens) > 0 {
+		line = p.tokens[len(p.tokens)-1].line
+	}
+	return protoToken{line: line}
+}
+
+func (p *protoParser) next() protoToken {
+	tok := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *protoParser) errorf(tok protoToken, format string, args ...any) error {
+	return fmt.Errorf("parse proto: %d:%d: %s", tok.line, tok.col, fmt.Sprintf(format, args...))
+}
+
+func (p *protoParser) expect(text string) (protoToken, error) {
+	tok := p.next()
+	if tok.text != text || tok.str {
+		return tok, p.errorf(tok, "expected %q, got %s", text, describeToken(tok))
+	}
+	return tok, nil
+}
+
+func (p *protoParser) ident() (protoToken, error) {
+	tok := p.next()
+	if tok.str || tok.text == "" || !isProtoIdent(tok.text) {
+		return tok, p.errorf(tok, "expected identifier, got %s", describeToken(tok))
+	}
+	return tok, nil
+}
+
+func describeToken(tok protoToken) string {
+	if tok.text == "" && !tok.str {
+		return "end of file"
+	}
+	return fmt.Sprintf("%q", tok.text)
+}
+
+func (p *protoParser) parseFile() error {
+	for p.pos < len(p.tokens) {
+		tok := p.next()
+		switch tok.text {
+		case "syntax", "edition":
+			if _, err := p.expect("="); err != nil {
+				return err
+			}
+			value := p.next()
+			if tok.text == "edition" || value.text != "proto3" {
+				return p.errorf(value, "only proto3 files are supported, got %s %q", tok.text, value.text)
+			}
+			if _, err := p.expect(";"); err != nil {
+				return err
+			}
+		case "package":
+			name, err := p.ident()
+			if err != nil {
+				return err
+			}
+			p.file.pkg = name.text
+			if _, err := p.expect(";"); err != nil {
+				return err
+			}
+		case "import", "option":
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		case "message":
+			if err := p.parseMessage("", tok.doc); err != nil {
+				return err
+			}
+		case "enum":
+			if err := p.parseEnum(""); err != nil {
+				return err
+			}
+		case "service":
+			if err := p.parseService(); err != nil {
+				return err
+			}
+		case ";":
+		default:
+			return p.errorf(tok, "unexpected %s", describeToken(tok))
+		}
+	}
+	return nil
+}
+
+// skipStatement skips to the end of a statement, including any braced
+// option values.
+func (p *protoParser) skipStatement() error {
+	depth := 0
+	for {
+		tok := p.next()
+		switch {
+		case tok.text == "" && !tok.str:
+			return p.errorf(tok, "unexpected end of file")
+		case tok.str:
+		case tok.text == "{":
+			depth++
+		case tok.text == "}":
+			depth--
+		case tok.text == ";" && depth == 0:
+			return nil
+		}
+	}
+}
+
+// skipBlock skips a braced block whose "{" was already consumed.
+func (p *protoParser) skipBlock() error {
+	for depth := 1; depth > 0; {
+		tok := p.next()
+		switch {
+		case tok.text == "" && !tok.str:
+			return p.errorf(tok, "unexpected end of file")
+		case tok.str:
+		case tok.text == "{":
+			depth++
+		case tok.text == "}":
+			depth--
+		}
+	}
+	return nil
+}
+
+func (p *protoParser) parseMessage(parent, doc string) error {
+	nameTok, err := p.ident()
+	if err != nil {
+		return err
+	}
+	name := nameTok.text
+	if parent != "" {
+		name = parent + "." + name
+	}
+	message := &protoMessage{name: name, doc: doc}
+	p.file.messages = append(p.file.messages, message)
+	if _, err := p.expect("{"); err != nil {
+		return err
+	}
+	for {
+		tok := p.peek()
+		switch tok.text {
+		case "}":
+			p.next()
+			return nil
+		case "message":
+			p.next()
+			if err := p.parseMessage(name, tok.doc); err != nil {
+				return err
+			}
+		case "enum":
+			p.next()
+			if err := p.parseEnum(name); err != nil {
+				return err
+			}
+		case "option", "reserved", "extensions":
+			p.next()
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		case "oneof":
+			p.next()
+			oneof, err := p.ident()
+			if err != nil {
+				return err
+			}
+			if _, err := p.expect("{"); err != nil {
+				return err
+			}
+			for p.peek().text != "}" {
+				if p.peek().text == "option" {
+					p.next()
+					if err := p.skipStatement(); err != nil {
+						return err
+					}
+					continue
+				}
+				f, err := p.parseField()
+				if err != nil {
+					return err
+				}
+				f.oneof = oneof.text
+				message.fields = append(message.fields, f)
+			}
+			p.next()
+		case ";":
+			p.next()
+		case "":
+			return p.errorf(tok, "unexpected end of file in message %s", name)
+		default:
+			f, err := p.parseField()
+			if err != nil {
+				return err
+			}
+			message.fields = append(message.fields, f)
+		}
+	}
+}
+
+func (p *protoParser) parseField() (protoField, error) {
+	first := p.peek()
+	f := protoField{doc: first.doc}
+	switch first.text {
+	case "repeated", "optional":
+		f.label = p.next().text
+	case "required":
+		return f, p.errorf(first, "required fields are not allowed in proto3")
+	}
+	if p.peek().text == "map" && p.tokens[min(p.pos+1, len(p.tokens)-1)].text == "<" {
+		p.next()
+		p.next()
+		key, err := p.ident()
+		if err != nil {
+			return f, err
+		}
+		if _, err := p.expect(","); err != nil {
+			return f, err
+		}
+		value, err := p.ident()
+		if err != nil {
+			return f, err
+		}
+		if _, err := p.expect(">"); err != nil {
+			return f, err
+		}
+		f.key, f.typ = key.text, value.text
+	} else {
+		typ, err := p.ident()
+		if err != nil {
+			return f, err
+		}
+		f.typ = typ.text
+	}
+	name, err := p.ident()
+	if err != nil {
+		return f, err
+	}
+	f.name = name.text
+	if _, err := p.expect("="); err != nil {
+		return f, err
+	}
+	p.next()
+	return f, p.skipStatement()
+}
+
+func (p *protoParser) parseEnum(parent string) error {
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	full := name.text
+	if parent != "" {
+		full = parent + "." + full
+	}
+	p.file.enums.Add(full)
+	if _, err := p.expect("{"); err != nil {
+		return err
+	}
+	return p.skipBlock()
+}
+
+func (p *protoParser) parseService() error {
+	name, err := p.ident()
+	if err != nil {
+		return err
+	}
+	service := &protoService{name: name.text}
+	p.file.services = append(p.file.services, service)
+	if _, err := p.expect("{"); err != nil {
+		return err
+	}
+	for {
+		tok := p.next()
+		switch tok.text {
+		case "}":
+			return nil
+		case ";":
+		case "option":
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		case "rpc":
+			method, err := p.parseRPC(tok.doc)
+			if err != nil {
+				return err
+			}
+			service.rpcs = append(service.rpcs, method)
+		default:
+			return p.errorf(tok, "unexpected %s in service %s", describeToken(tok), service.name)
+		}
+	}
+}
+
+func (p *protoParser) parseRPC(doc string) (protoRPC, error) {
+	method := protoRPC{doc: doc}
+	name, err := p.ident()
+	if err != nil {
+		return method, err
+	}
+	method.name = name.text
+	method.request, method.clientStream, err = p.parseRPCType()
+	if err != nil {
+		return method, err
+	}
+	if _, err := p.expect("returns"); err != nil {
+		return method, err
+	}
+	method.response, method.serverStream, err = p.parseRPCType()
+	if err != nil {
+		return method, err
+	}
+	switch tok := p.next(); tok.text {
+	case ";":
+	case "{":
+		err = p.skipBlock()
+	default:
+		err = p.errorf(tok, "expected \";\" or \"{\", got %s", describeToken(tok))
+	}
+	return method, err
+}
+
+func (p *protoParser) parseRPCType() (string, bool, error) {
+	if _, err := p.expect("("); err != nil {
+		return "", false, err
+	}
+	stream := false
+	if p.peek().text == "stream" && p.tokens[min(p.pos+1, len(p.tokens)-1)].text != ")" {
+		p.next()
+		stream = true
+	}
+	typ, err := p.ident()
+	if err != nil {
+		return "", false, err
+	}
+	_, err = p.expect(")")
+	return typ.text, stream, err
+}
+
+func isProtoIdent(text string) bool {
+	for i, r := range text {
+		if !(r == '_' || r == '.' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenizeProto splits a proto file into tokens. Comments are attached as
+// doc to the next token when they end on the line above it and do not
+// follow other tokens on their line.
+func tokenizeProto(src string) ([]protoToken, error) {
+	var tokens []protoToken
+	var doc []string
+	docEnd := 0
+	line, col := 1, 1
+	lastLine := 0
+	runes := []rune(src)
+	advance := func(n int) {
+		for i := 0; i < n; i++ {
+			if runes[0] == '\n' {
+				line++
+				col = 1
+			} else {
+				col++
+			}
+			runes = runes[1:]
+		}
+	}
+	comment := func(text string, startLine int) {
+		if startLine == lastLine {
+			return
+		}
+		if docEnd != 0 && docEnd < startLine-1 {
+			doc = nil
+		}
+		doc = append(doc, text)
+		docEnd = line
+	}
+	for len(runes) > 0 {
+		r := runes[0]
+		switch {
+		case unicode.IsSpace(r):
+			advance(1)
+		case r == '/' && len(runes) > 1 && runes[1] == '/':
+			start := line
+			end := slices.Index(runes, '\n')
+			if end < 0 {
+				end = len(runes)
+			}
+			text := string(runes[2:end])
+			advance(end)
+			comment(strings.TrimPrefix(text, " "), start)
+		case r == '/' && len(runes) > 1 && runes[1] == '*':
+			start, startCol := line, col
+			end := strings.Index(string(runes[2:]), "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("parse proto: %d:%d: unterminated comment", start, startCol)
+			}
+			text := []rune(string(runes[2:])[:end])
+			advance(len(text) + 4)
+			for _, l := range strings.Split(string(text), "\n") {
+				l = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(l), "*"))
+				if l != "" {
+					comment(l, start)
+				}
+			}
+		case r == '"' || r == '\'':
+			start, startCol := line, col
+			i := 1
+			for i < len(runes) && runes[i] != r && runes[i] != '\n' {
+				if runes[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(runes) || runes[i] != r {
+				return nil, fmt.Errorf("parse proto: %d:%d: unterminated string", start, startCol)
+			}
+			tokens = append(tokens, protoToken{text: string(runes[1:i]), line: start, col: startCol, str: true})
+			advance(i + 1)
+			lastLine = line
+		case r == '_' || r == '.' || r == '-' || r == '+' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			i := 1
+			for i < len(runes) && (runes[i] == '_' || runes[i] == '.' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tok := protoToken{text: string(runes[:i]), line: line, col: col}
+			if docEnd == line-1 && len(doc) > 0 {
+				tok.doc = strings.Join(doc, "\n")
+			}
+			doc, docEnd = nil, 0
+			tokens = append(tokens, tok)
+			advance(i)
+			lastLine = line
+		default:
+			tokens = append(tokens, protoToken{text: string(r), line: line, col: col})
+			doc, docEnd = nil, 0
+			advance(1)
+			lastLine = line
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,270 @@
+// Package ir defines the versioned JSON representation of a validated schema
+// that is handed to generator plugins and printed by rrpc ir. The format is
+// documented in docs/ir.md.
+package ir
+
+import (
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/protocol"
+	"github.com/Rapid-Vision/rRPC/internal/utils"
+)
+
+// Version is bumped on every incompatible change to the representation.
+// Adding fields is not considered incompatible.
+const Version = 1
+
+const (
+	KindBuiltin = "builtin"
+	KindModel   = "model"
+	KindList    = "list"
+	KindMap     = "map"
+)
+
+type Schema struct {
+	Version  int       `json:"version"`
+	Models   []Model   `json:"models"`
+	RPCs     []RPC     `json:"rpcs"`
+	Comments []Comment `json:"comments"`
+}
+
+// Position is a 1-based location in the schema source.
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Comment is a comment as written in the source, including the leading #.
+type Comment struct {
+	Position Position `json:"position"`
+	Text     string   `json:"text"`
+}
+
+// AnnotationPrefix starts comment lines that are directives to rrpc
+// tooling rather than documentation, such as "# rrpc:query".
+const AnnotationPrefix = "rrpc:"
+
+// Annotations are the directive lines of a comment block without the
+// prefix, such as "query" or "folder Admin".
+type Annotations []string
+
+// Lookup returns the argument of the first annotation called name, which
+// is empty for annotations without one.
+func (a Annotations) Lookup(name string) (string, bool) {
+	for _, annotation := range a {
+		key, arg, _ := strings.Cut(annotation, " ")
+		if key == name {
+			return strings.TrimSpace(arg), true
+		}
+	}
+	return "", false
+}
+
+// Model is a model declaration. Doc is the text of the comment lines
+// directly above the declaration, without the # markers and annotations.
+type Model struct {
+	Name        string      `json:"name"`
+	CodeName    string      `json:"code_name"`
+	Doc         string      `json:"doc,omitempty"`
+	Annotations Annotations `json:"annotations,omitempty"`
+	Position    Position    `json:"position"`
+	Fields      []Field     `json:"fields"`
+}
+
+// Field is a model field or an RPC parameter. WireName is the JSON key and
+// CodeName the PascalCase identifier used by the built-in generators.
+type Field struct {
+	Name        string      `json:"name"`
+	WireName    string      `json:"wire_name"`
+	CodeName    string      `json:"code_name"`
+	Doc         string      `json:"doc,omitempty"`
+	Annotations Annotations `json:"annotations,omitempty"`
+	Position    Position    `json:"position"`
+	Type        Type        `json:"type"`
+}
+
+// Type is a type reference. Name is set for builtin and model types; Elem
+// is the element type of lists and the value type of maps, whose keys are
+// always strings.
+type Type struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name,omitempty"`
+	Elem     *Type  `json:"elem,omitempty"`
+	Optional bool   `json:"optional"`
+}
+
+// RPC describes one call. Returns is nil when the RPC has no result;
+// ResultKey is then empty as well.
+type RPC struct {
+	Name        string      `json:"name"`
+	CodeName    string      `json:"code_name"`
+	Doc         string      `json:"doc,omitempty"`
+	Annotations Annotations `json:"annotations,omitempty"`
+	Position    Position    `json:"position"`
+	Path        string      `json:"path"`
+	Params      []Field     `json:"params"`
+	Returns     *Type       `json:"returns"`
+	ResultKey   string      `json:"result_key,omitempty"`
+}
+
+// FromSchema converts a validated schema. prefix is the URL path prefix
+// used to compute RPC paths.
+func FromSchema(schema *parser.Schema, prefix string) *Schema {
+	out := &Schema{
+		Version:  Version,
+		Models:   make([]Model, 0, len(schema.Models)),
+		RPCs:     make([]RPC, 0, len(schema.RPCs)),
+		Comments: make([]Comment, 0, len(schema.Comments)),
+	}
+	docs := newDocIndex(schema)
+	for _, model := range schema.Models {
+		doc, annotations := docs.above(model.Line)
+		out.Models = append(out.Models, Model{
+			Name:        model.Name,
+			CodeName:    codeName(model.Name),
+			Doc:         doc,
+			Annotations: annotations,
+			Position:    Position{model.Line, model.Col},
+			Fields:      fields(model.Fields, docs),
+		})
+	}
+	for _, rpc := range schema.RPCs {
+		doc, annotations := docs.above(rpc.Line)
+		converted := RPC{
+			Name:        rpc.Name,
+			CodeName:    codeName(rpc.Name),
+			Doc:         doc,
+			Annotations: annotations,
+			Position:    Position{rpc.Line, rpc.Col},
+			Path:        protocol.RPCPath(prefix, rpc.Name),
+			Params:      fields(rpc.Parameters, docs),
+		}
+		if rpc.HasReturn {
+			returns := typeRef(rpc.Returns)
+			converted.Returns = &returns
+			converted.ResultKey = protocol.ResultKey(rpc.Returns)
+		}
+		out.RPCs = append(out.RPCs, converted)
+	}
+	for _, comment := range schema.Comments {
+		out.Comments = append(out.Comments, Comment{
+			Position: Position{comment.Line, comment.Col},
+			Text:     comment.Text,
+		})
+	}
+	return out
+}
+
+func fields(in []parser.Field, docs docIndex) []Field {
+	out := make([]Field, 0, len(in))
+	for _, field := range in {
+		doc, annotations := docs.above(field.Line)
+		out = append(out, Field{
+			Name:        field.Name,
+			WireName:    protocol.JSONName(field.Name),
+			CodeName:    codeName(field.Name),
+			Doc:         doc,
+			Annotations: annotations,
+			Position:    Position{field.Line, field.Col},
+			Type:        typeRef(field.Type),
+		})
+	}
+	return out
+}
+
+// docIndex holds the comments that are alone on their line, by line.
+// Comments following code on the same line are not documentation.
+type docIndex map[int]string
+
+func newDocIndex(schema *parser.Schema) docIndex {
+	code := make(map[int]int)
+	mark := func(line, col int) {
+		if first, ok := code[line]; !ok || col < first {
+			code[line] = col
+		}
+	}
+	for _, model := range schema.Models {
+		mark(model.Line, model.Col)
+		mark(model.EndLine, model.EndCol)
+		for _, field := range model.Fields {
+			mark(field.Line, field.Col)
+		}
+	}
+	for _, rpc := range schema.RPCs {
+		mark(rpc.Line, rpc.Col)
+		if len(rpc.Parameters) > 0 {
+			mark(rpc.ParamsEndLine, rpc.ParamsEndCol)
+		}
+		if rpc.HasReturn {
+			mark(rpc.Returns.Line, rpc.Returns.Col)
+		}
+		for _, param := range rpc.Parameters {
+			mark(param.Line, param.Col)
+		}
+	}
+	docs := make(docIndex)
+	for _, comment := range schema.Comments {
+		if col, ok := code[comment.Line]; ok && col < comment.Col {
+			continue
+		}
+		text := strings.TrimPrefix(comment.Text, "#")
+		docs[comment.Line] = strings.TrimPrefix(text, " ")
+	}
+	return docs
+}
+
+// above returns the block of comment lines directly above line, split
+// into documentation and annotations.
+func (d docIndex) above(line int) (string, Annotations) {
+	start := line
+	for {
+		if _, ok := d[start-1]; !ok {
+			break
+		}
+		start--
+	}
+	lines := make([]string, 0, line-start)
+	var annotations Annotations
+	for l := start; l < line; l++ {
+		if annotation, ok := strings.CutPrefix(strings.TrimSpace(d[l]), AnnotationPrefix); ok {
+			annotations = append(annotations, annotation)
+			continue
+		}
+		lines = append(lines, d[l])
+	}
+	if annotations != nil {
+		// Drop the blank lines that separated annotations from the text.
+		return strings.Trim(strings.Join(lines, "\n"), "\n"), annotations
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func typeRef(t parser.TypeRef) Type {
+	out := Type{Optional: t.Optional}
+	switch t.Kind {
+	case parser.TypeList:
+		out.Kind = KindList
+		if t.Elem != nil {
+			elem := typeRef(*t.Elem)
+			out.Elem = &elem
+		}
+	case parser.TypeMap:
+		out.Kind = KindMap
+		if t.Value != nil {
+			elem := typeRef(*t.Value)
+			out.Elem = &elem
+		}
+	default:
+		out.Kind = KindModel
+		if parser.IsBuiltinType(t.Name) {
+			out.Kind = KindBuiltin
+		}
+		out.Name = t.Name
+	}
+	return out
+}
+
+func codeName(name string) string {
+	return utils.NewIdentifierName(name).PascalCase()
+}
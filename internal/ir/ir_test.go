@@ -0,0 +1,83 @@
+package ir_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Rapid-Vision/rRPC/internal/ir"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+)
+
+func TestFromSchema(t *testing.T) {
+	schema, err := parser.Parse(`# Text is a document.
+#
+# It has a title.
+model Text {
+    # Optional title.
+    title: string? # shown in lists
+    body: string
+}
+
+# Unrelated note.
+
+rpc GetText(
+    # The id.
+    textId: int,
+) Text
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := ir.FromSchema(schema, "")
+
+	model := out.Models[0]
+	if model.Doc != "Text is a document.\n\nIt has a title." || model.Position != (ir.Position{Line: 4, Column: 1}) {
+		t.Fatalf("unexpected model: %+v", model)
+	}
+	if title := model.Fields[0]; title.Doc != "Optional title." || title.Position != (ir.Position{Line: 6, Column: 5}) {
+		t.Fatalf("unexpected field: %+v", title)
+	}
+	if body := model.Fields[1]; body.Doc != "" {
+		t.Fatalf("trailing comment taken as doc: %+v", body)
+	}
+	rpc := out.RPCs[0]
+	if rpc.Doc != "" || rpc.Path != "/get_text" || rpc.Params[0].Doc != "The id." {
+		t.Fatalf("unexpected rpc: %+v", rpc)
+	}
+	if len(out.Comments) != 7 || out.Comments[4].Text != "# shown in lists" || out.Comments[4].Position != (ir.Position{Line: 6, Column: 20}) {
+		t.Fatalf("unexpected comments: %+v", out.Comments)
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	schema, err := parser.Parse("# Fetch a text.\n#\n# rrpc:folder Admin Tools\n#rrpc:query\nrpc GetText()\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rpc := ir.FromSchema(schema, "").RPCs[0]
+	if rpc.Doc != "Fetch a text." || len(rpc.Annotations) != 2 {
+		t.Fatalf("unexpected rpc: %+v", rpc)
+	}
+	if folder, ok := rpc.Annotations.Lookup("folder"); !ok || folder != "Admin Tools" {
+		t.Fatalf("unexpected folder %q", folder)
+	}
+	if _, ok := rpc.Annotations.Lookup("query"); !ok {
+		t.Fatalf("missing query annotation in %v", rpc.Annotations)
+	}
+}
+
+func TestJSONKeys(t *testing.T) {
+	schema, err := parser.Parse("rpc Ping()\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := json.Marshal(ir.FromSchema(schema, "rpc"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"version":1,"models":[],"rpcs":[{"name":"Ping","code_name":"Ping","position":{"line":1,"column":1},"path":"/rpc/ping","params":[],"returns":null}],"comments":[]}`
+	if got := strings.TrimSpace(string(data)); got != want {
+		t.Fatalf("unexpected JSON:\n got %s\nwant %s", got, want)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 type TokenType int
@@ -135,6 +136,16 @@ func NewLexer(text string) *Lexer {
 }
 
 func (l *Lexer) Tokenize() (tokens []Token, err error) {
+	tokens, errs := l.TokenizeAll()
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return tokens, nil
+}
+
+// TokenizeAll skips characters that start no token and reports each run of
+// them as one error, so parsing can continue past them.
+func (l *Lexer) TokenizeAll() (tokens []Token, errs []LexerError) {
 	patterns := make([]string, 0, len(rules))
 	nameToType := make(map[string]TokenType, len(rules))
 	for _, rule := range rules {
@@ -147,11 +158,26 @@ func (l *Lexer) Tokenize() (tokens []Token, err error) {
 	text := l.text
 	line := 1
 	col := 1
+	inBadRun := false
 	for i := 0; i < len(text); {
 		loc := re.FindStringSubmatchIndex(text[i:])
 		if loc == nil || loc[0] != 0 {
-			return nil, LexerError{Line: line, Col: col, Ch: string(text[i])}
+			if !inBadRun {
+				r, _ := utf8.DecodeRuneInString(text[i:])
+				errs = append(errs, LexerError{Line: line, Col: col, Ch: string(r)})
+			}
+			inBadRun = true
+			r, size := utf8.DecodeRuneInString(text[i:])
+			if r == '\n' {
+				line++
+				col = 1
+			} else {
+				col++
+			}
+			i += size
+			continue
 		}
+		inBadRun = false
 
 		var tokenType TokenType
 		var value string
@@ -187,7 +213,7 @@ func (l *Lexer) Tokenize() (tokens []Token, err error) {
 		}
 		i += loc[1]
 	}
-	return tokens, nil
+	return tokens, errs
 }
 
 func TokenTypeName(tt TokenType) string {
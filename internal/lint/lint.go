@@ -0,0 +1,411 @@
+// Package lint checks schemas against style and API-design conventions.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/utils"
+)
+
+// SeverityOff disables a rule.
+const SeverityOff parser.Severity = "off"
+
+type Rule struct {
+	Name        string
+	Description string
+	Severity    parser.Severity
+	check       func(*pass)
+}
+
+// MaxNesting is the deepest list/map nesting accepted by the max-nesting rule.
+const MaxNesting = 2
+
+var rules = []Rule{
+	{
+		Name:        "pascal-case-names",
+		Description: "Model and RPC names are PascalCase",
+		Severity:    parser.SeverityWarning,
+		check:       checkPascalCase,
+	},
+	{
+		Name:        "snake-case-fields",
+		Description: "Field and parameter names are snake_case",
+		Severity:    parser.SeverityWarning,
+		check:       checkSnakeCase,
+	},
+	{
+		Name:        "unused-model",
+		Description: "Every model is reachable from an RPC parameter or result",
+		Severity:    parser.SeverityWarning,
+		check:       checkUnusedModels,
+	},
+	{
+		Name:        "bare-json-return",
+		Description: "RPCs do not return bare json or raw values",
+		Severity:    parser.SeverityWarning,
+		check:       checkBareJSONReturn,
+	},
+	{
+		Name:        "max-nesting",
+		Description: fmt.Sprintf("Types nest lists and maps at most %d levels deep", MaxNesting),
+		Severity:    parser.SeverityWarning,
+		check:       checkNesting,
+	},
+	{
+		Name:        "optional-id",
+		Description: "Fields named id or ending in _id are not optional",
+		Severity:    parser.SeverityWarning,
+		check:       checkOptionalIDs,
+	},
+	{
+		Name:        "verb-first-rpc",
+		Description: "RPC names start with a verb such as Get, List or Create",
+		Severity:    parser.SeverityInfo,
+		check:       checkVerbFirst,
+	},
+	{
+		Name:        "field-name-collision",
+		Description: "Field and parameter names stay distinct after normalization to wire and code names",
+		Severity:    parser.SeverityError,
+		check:       checkCollisions,
+	},
+}
+
+// Rules lists the available rules with their default severities.
+func Rules() []Rule {
+	out := make([]Rule, len(rules))
+	copy(out, rules)
+	return out
+}
+
+// Config overrides rule severities by rule name. SeverityOff disables a rule.
+type Config map[string]parser.Severity
+
+// ParseSeverity accepts off, info, warning and error.
+func ParseSeverity(value string) (parser.Severity, error) {
+	switch severity := parser.Severity(strings.ToLower(value)); severity {
+	case SeverityOff, parser.SeverityInfo, parser.SeverityWarning, parser.SeverityError:
+		return severity, nil
+	}
+	return "", fmt.Errorf("unknown severity %q (use off, info, warning or error)", value)
+}
+
+// Validate reports rule names in cfg that do not exist.
+func (cfg Config) Validate() error {
+	for name := range cfg {
+		if !ruleExists(name) {
+			return fmt.Errorf("unknown lint rule %q", name)
+		}
+	}
+	return nil
+}
+
+func ruleExists(name string) bool {
+	for _, rule := range rules {
+		if rule.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+type pass struct {
+	schema   *parser.Schema
+	rule     Rule
+	severity parser.Severity
+	findings []parser.Diagnostic
+}
+
+func (p *pass) report(line, col, length int, format string, args ...any) {
+	p.findings = append(p.findings, parser.Diagnostic{
+		Range: parser.Range{
+			Start: parser.Position{Line: line, Col: col},
+			End:   parser.Position{Line: line, Col: col + length},
+		},
+		Severity: p.severity,
+		Code:     p.rule.Name,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Run checks schema, which must be valid, and returns findings sorted by
+// position. source is the schema text, used to honor
+// "# rrpc:ignore rule-name" comments.
+func Run(schema *parser.Schema, source string, cfg Config) []parser.Diagnostic {
+	ignores := parseIgnores(schema.Comments, source)
+	var findings []parser.Diagnostic
+	for _, rule := range rules {
+		severity := rule.Severity
+		if override, ok := cfg[rule.Name]; ok {
+			severity = override
+		}
+		if severity == SeverityOff {
+			continue
+		}
+		p := &pass{schema: schema, rule: rule, severity: severity}
+		rule.check(p)
+		for _, finding := range p.findings {
+			if !ignores.suppresses(finding) {
+				findings = append(findings, finding)
+			}
+		}
+	}
+	parser.SortDiagnostics(findings)
+	return findings
+}
+
+var ignorePattern = regexp.MustCompile(`^#\s*rrpc:ignore\b(.*)$`)
+
+// ignoreSet maps a line to the rules ignored on it; an empty rule name
+// ignores every rule.
+type ignoreSet map[int][]string
+
+// parseIgnores applies a trailing ignore comment to its own line and a
+// comment on a line by itself to the next line.
+func parseIgnores(comments []parser.Comment, source string) ignoreSet {
+	lines := strings.Split(source, "\n")
+	ignores := make(ignoreSet)
+	for _, comment := range comments {
+		match := ignorePattern.FindStringSubmatch(strings.TrimSpace(comment.Text))
+		if match == nil {
+			continue
+		}
+		names := strings.FieldsFunc(match[1], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(names) == 0 {
+			names = []string{""}
+		}
+		line := comment.Line
+		if comment.Line-1 < len(lines) {
+			prefix := []rune(lines[comment.Line-1])
+			if comment.Col-1 <= len(prefix) && strings.TrimSpace(string(prefix[:comment.Col-1])) == "" {
+				line++
+			}
+		}
+		ignores[line] = append(ignores[line], names...)
+	}
+	return ignores
+}
+
+func (s ignoreSet) suppresses(finding parser.Diagnostic) bool {
+	for _, name := range s[finding.Range.Start.Line] {
+		if name == "" || name == finding.Code {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	pascalCasePattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	snakeCasePattern  = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+)
+
+func checkPascalCase(p *pass) {
+	for _, model := range p.schema.Models {
+		if !pascalCasePattern.MatchString(model.Name) {
+			p.report(model.NameLine, model.NameCol, nameLength(model.Name), "model name %q is not PascalCase (use %q)", model.Name, utils.NewIdentifierName(model.Name).PascalCase())
+		}
+	}
+	for _, rpc := range p.schema.RPCs {
+		if !pascalCasePattern.MatchString(rpc.Name) {
+			p.report(rpc.NameLine, rpc.NameCol, nameLength(rpc.Name), "rpc name %q is not PascalCase (use %q)", rpc.Name, utils.NewIdentifierName(rpc.Name).PascalCase())
+		}
+	}
+}
+
+func checkSnakeCase(p *pass) {
+	check := func(kind string, field parser.Field) {
+		if !snakeCasePattern.MatchString(field.Name) {
+			p.report(field.Line, field.Col, nameLength(field.Name), "%s name %q is not snake_case (use %q)", kind, field.Name, utils.NewIdentifierName(field.Name).SnakeCase())
+		}
+	}
+	for _, model := range p.schema.Models {
+		for _, field := range model.Fields {
+			check("field", field)
+		}
+	}
+	for _, rpc := range p.schema.RPCs {
+		for _, param := range rpc.Parameters {
+			check("parameter", param)
+		}
+	}
+}
+
+func checkUnusedModels(p *pass) {
+	models := make(map[string]parser.Model, len(p.schema.Models))
+	for _, model := range p.schema.Models {
+		models[model.Name] = model
+	}
+	used := make(map[string]bool)
+	var mark func(t parser.TypeRef)
+	mark = func(t parser.TypeRef) {
+		name := baseType(t).Name
+		model, ok := models[name]
+		if !ok || used[name] {
+			return
+		}
+		used[name] = true
+		for _, field := range model.Fields {
+			mark(field.Type)
+		}
+	}
+	for _, rpc := range p.schema.RPCs {
+		for _, param := range rpc.Parameters {
+			mark(param.Type)
+		}
+		if rpc.HasReturn {
+			mark(rpc.Returns)
+		}
+	}
+	for _, model := range p.schema.Models {
+		if !used[model.Name] {
+			p.report(model.NameLine, model.NameCol, nameLength(model.Name), "model %q is not used by any rpc", model.Name)
+		}
+	}
+}
+
+func checkBareJSONReturn(p *pass) {
+	for _, rpc := range p.schema.RPCs {
+		if !rpc.HasReturn || rpc.Returns.Kind != parser.TypeIdent {
+			continue
+		}
+		if rpc.Returns.Name == "json" || rpc.Returns.Name == "raw" {
+			p.report(rpc.Returns.Line, rpc.Returns.Col, nameLength(rpc.Returns.Name), "rpc %q returns bare %s; return a model so the result can evolve", rpc.Name, rpc.Returns.Name)
+		}
+	}
+}
+
+func checkNesting(p *pass) {
+	check := func(t parser.TypeRef, what string) {
+		if depth := nesting(t); depth > MaxNesting {
+			p.report(t.Line, t.Col, utf8.RuneCountInString(parser.FormatType(t)), "%s nests lists and maps %d levels deep (max %d); introduce a model", what, depth, MaxNesting)
+		}
+	}
+	for _, model := range p.schema.Models {
+		for _, field := range model.Fields {
+			check(field.Type, fmt.Sprintf("field %q", field.Name))
+		}
+	}
+	for _, rpc := range p.schema.RPCs {
+		for _, param := range rpc.Parameters {
+			check(param.Type, fmt.Sprintf("parameter %q", param.Name))
+		}
+		if rpc.HasReturn {
+			check(rpc.Returns, fmt.Sprintf("result of %q", rpc.Name))
+		}
+	}
+}
+
+func nesting(t parser.TypeRef) int {
+	switch {
+	case t.Kind == parser.TypeList && t.Elem != nil:
+		return 1 + nesting(*t.Elem)
+	case t.Kind == parser.TypeMap && t.Value != nil:
+		return 1 + nesting(*t.Value)
+	}
+	return 0
+}
+
+func checkOptionalIDs(p *pass) {
+	check := func(kind string, field parser.Field) {
+		name := utils.NewIdentifierName(field.Name).SnakeCase()
+		if field.Type.Optional && (name == "id" || strings.HasSuffix(name, "_id")) {
+			p.report(field.Line, field.Col, nameLength(field.Name), "%s %q looks like an identifier but is optional", kind, field.Name)
+		}
+	}
+	for _, model := range p.schema.Models {
+		for _, field := range model.Fields {
+			check("field", field)
+		}
+	}
+	for _, rpc := range p.schema.RPCs {
+		for _, param := range rpc.Parameters {
+			check("parameter", param)
+		}
+	}
+}
+
+// Verbs accepted as the first word of an RPC name by verb-first-rpc.
+var Verbs = []string{
+	"Add", "Apply", "Approve", "Archive", "Assign", "Authenticate", "Batch", "Calculate",
+	"Cancel", "Check", "Clear", "Close", "Complete", "Compute", "Confirm", "Connect",
+	"Convert", "Copy", "Count", "Create", "Delete", "Describe", "Disable", "Disconnect",
+	"Download", "Enable", "Estimate", "Execute", "Export", "Fetch", "Find", "Generate",
+	"Get", "Import", "Invite", "Join", "Leave", "List", "Load", "Lock", "Login", "Logout",
+	"Mark", "Merge", "Move", "Notify", "Open", "Parse", "Pause", "Ping", "Process",
+	"Publish", "Query", "Read", "Refresh", "Register", "Reject", "Reload", "Remove",
+	"Rename", "Render", "Replace", "Reset", "Resolve", "Restore", "Resume", "Retry",
+	"Revoke", "Run", "Save", "Schedule", "Search", "Send", "Set", "Share", "Sign",
+	"Start", "Stop", "Submit", "Subscribe", "Sync", "Test", "Toggle", "Track", "Transfer",
+	"Trigger", "Unlock", "Unsubscribe", "Update", "Upload", "Upsert", "Validate", "Verify",
+	"Watch", "Write",
+}
+
+func checkVerbFirst(p *pass) {
+	verbs := make(map[string]bool, len(Verbs))
+	for _, verb := range Verbs {
+		verbs[verb] = true
+	}
+	for _, rpc := range p.schema.RPCs {
+		word, _, _ := strings.Cut(utils.NewIdentifierName(rpc.Name).SnakeCase(), "_")
+		if !verbs[utils.NewIdentifierName(word).PascalCase()] {
+			p.report(rpc.NameLine, rpc.NameCol, nameLength(rpc.Name), "rpc name %q does not start with a verb", rpc.Name)
+		}
+	}
+}
+
+func checkCollisions(p *pass) {
+	check := func(owner string, fields []parser.Field) {
+		seen := make(map[string]parser.Field)
+		for _, field := range fields {
+			for _, key := range []string{
+				"wire:" + utils.NewIdentifierName(field.Name).SnakeCase(),
+				"code:" + utils.NewIdentifierName(field.Name).PascalCase(),
+			} {
+				if prev, ok := seen[key]; ok && prev.Name != field.Name {
+					p.report(field.Line, field.Col, nameLength(field.Name), "%s: %q collides with %q after normalization", owner, field.Name, prev.Name)
+					break
+				}
+				seen[key] = field
+			}
+		}
+	}
+	for _, model := range p.schema.Models {
+		check(fmt.Sprintf("model %q", model.Name), model.Fields)
+	}
+	for _, rpc := range p.schema.RPCs {
+		check(fmt.Sprintf("rpc %q", rpc.Name), rpc.Parameters)
+	}
+}
+
+func baseType(t parser.TypeRef) parser.TypeRef {
+	switch {
+	case t.Kind == parser.TypeList && t.Elem != nil:
+		return baseType(*t.Elem)
+	case t.Kind == parser.TypeMap && t.Value != nil:
+		return baseType(*t.Value)
+	}
+	return t
+}
+
+func nameLength(name string) int {
+	return utf8.RuneCountInString(name)
+}
+
+// Count returns the number of findings at or above severity.
+func Count(findings []parser.Diagnostic, severity parser.Severity) int {
+	rank := map[parser.Severity]int{parser.SeverityInfo: 1, parser.SeverityWarning: 2, parser.SeverityError: 3}
+	count := 0
+	for _, finding := range findings {
+		if rank[finding.Severity] >= rank[severity] {
+			count++
+		}
+	}
+	return count
+}
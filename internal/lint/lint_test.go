@@ -0,0 +1,154 @@
+package lint_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Rapid-Vision/rRPC/internal/lint"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+)
+
+func run(t *testing.T, source string, cfg lint.Config) string {
+	t.Helper()
+	schema, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, finding := range lint.Run(schema, source, cfg) {
+		got = append(got, fmt.Sprintf("%d:%d %s %s", finding.Range.Start.Line, finding.Range.Start.Col, finding.Severity, finding.Code))
+	}
+	return strings.Join(got, "\n")
+}
+
+func TestPascalCaseNames(t *testing.T) {
+	got := run(t, `model page_info {
+    total: int
+}
+
+rpc get_info() page_info
+`, nil)
+	want := "1:7 warning pascal-case-names\n5:5 warning pascal-case-names"
+	if got != want {
+		t.Fatalf("unexpected findings:\n%s", got)
+	}
+}
+
+func TestSnakeCaseFields(t *testing.T) {
+	got := run(t, `model Page {
+    Total: int
+    pageSize: int
+}
+
+rpc GetPage(pageID: int) Page
+`, nil)
+	want := "2:5 warning snake-case-fields\n3:5 warning snake-case-fields\n6:13 warning snake-case-fields"
+	if got != want {
+		t.Fatalf("unexpected findings:\n%s", got)
+	}
+}
+
+func TestUnusedModel(t *testing.T) {
+	got := run(t, `model Text {
+    body: string
+}
+
+model Used {
+    text: Text
+}
+
+model Unused {
+    body: string
+}
+
+rpc GetUsed() Used
+`, nil)
+	if got != "9:7 warning unused-model" {
+		t.Fatalf("unexpected findings:\n%s", got)
+	}
+}
+
+func TestBareJSONReturn(t *testing.T) {
+	got := run(t, "rpc GetData() json\n\nrpc GetRaw() raw\n\nrpc GetList() list[json]\n", nil)
+	if got != "1:15 warning bare-json-return\n3:14 warning bare-json-return" {
+		t.Fatalf("unexpected findings:\n%s", got)
+	}
+}
+
+func TestMaxNesting(t *testing.T) {
+	got := run(t, `model Grid {
+    cells: list[list[int]]
+    cubes: list[list[list[int]]]
+}
+
+rpc GetGrid() Grid
+`, nil)
+	if got != "3:12 warning max-nesting" {
+		t.Fatalf("unexpected findings:\n%s", got)
+	}
+}
+
+func TestOptionalID(t *testing.T) {
+	got := run(t, `model Text {
+    id: int?
+    author_id: int?
+    title: string?
+}
+
+rpc GetText(text_id: int?) Text
+`, nil)
+	want := "2:5 warning optional-id\n3:5 warning optional-id\n7:13 warning optional-id"
+	if got != want {
+		t.Fatalf("unexpected findings:\n%s", got)
+	}
+}
+
+func TestVerbFirstRPC(t *testing.T) {
+	got := run(t, "rpc TextCount()\n\nrpc GetTextCount()\n", nil)
+	if got != "1:5 info verb-first-rpc" {
+		t.Fatalf("unexpected findings:\n%s", got)
+	}
+}
+
+func TestFieldNameCollision(t *testing.T) {
+	got := run(t, `model Pair {
+    user_id: int
+    userId: int
+}
+
+rpc GetPair() Pair
+`, lint.Config{"snake-case-fields": lint.SeverityOff})
+	if got != "3:5 error field-name-collision" {
+		t.Fatalf("unexpected findings:\n%s", got)
+	}
+}
+
+func TestRunConfig(t *testing.T) {
+	got := run(t, "rpc text_count() json\n", lint.Config{
+		"pascal-case-names": lint.SeverityOff,
+		"bare-json-return":  lint.SeverityOff,
+		"verb-first-rpc":    parser.SeverityError,
+	})
+	if got != "1:5 error verb-first-rpc" {
+		t.Fatalf("unexpected findings:\n%s", got)
+	}
+	if err := (lint.Config{"no-such-rule": lint.SeverityOff}).Validate(); err == nil {
+		t.Fatalf("expected unknown rule error")
+	}
+}
+
+func TestIgnoreComments(t *testing.T) {
+	got := run(t, `model Text {
+    # rrpc:ignore snake-case-fields
+    Title: string
+    Body: string # rrpc:ignore
+    owner_id: int? # rrpc:ignore snake-case-fields, verb-first-rpc
+}
+
+rpc GetText() Text
+`, nil)
+	if got != "5:5 warning optional-id" {
+		t.Fatalf("unexpected findings:\n%s", got)
+	}
+}
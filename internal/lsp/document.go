@@ -0,0 +1,190 @@
+package lsp
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/Rapid-Vision/rRPC/internal/lexer"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+)
+
+type symbolKind int
+
+const (
+	symbolModel symbolKind = iota
+	symbolRPC
+	symbolField
+	symbolParam
+	symbolType
+)
+
+// symbol is a name occurrence found by scanning tokens, so navigation keeps
+// working while the document does not parse.
+type symbol struct {
+	kind   symbolKind
+	token  lexer.Token
+	parent string
+}
+
+type document struct {
+	uri    string
+	text   string
+	lines  []string
+	schema *parser.Schema
+	// problems holds parse and validation errors. schema is partial when
+	// there are any.
+	problems []parser.Diagnostic
+	symbols  []symbol
+}
+
+func newDocument(uri, text string) *document {
+	doc := &document{
+		uri:   uri,
+		text:  text,
+		lines: strings.Split(text, "\n"),
+	}
+	doc.schema, doc.problems = parser.ParseWithDiagnostics(text)
+	tokens, _ := lexer.NewLexer(text).TokenizeAll()
+	doc.symbols = scanSymbols(tokens)
+	return doc
+}
+
+func scanSymbols(all []lexer.Token) []symbol {
+	tokens := make([]lexer.Token, 0, len(all))
+	for _, token := range all {
+		if token.Type != lexer.TokenComment {
+			tokens = append(tokens, token)
+		}
+	}
+	var symbols []symbol
+	parent := ""
+	parentKind := symbolModel
+	for i, token := range tokens {
+		if token.Type != lexer.TokenIdentifier {
+			continue
+		}
+		var prev, next lexer.TokenType = -1, -1
+		if i > 0 {
+			prev = tokens[i-1].Type
+		}
+		if i+1 < len(tokens) {
+			next = tokens[i+1].Type
+		}
+		switch {
+		case prev == lexer.TokenModel:
+			parent, parentKind = token.Value, symbolModel
+			symbols = append(symbols, symbol{kind: symbolModel, token: token})
+		case prev == lexer.TokenRpc:
+			parent, parentKind = token.Value, symbolRPC
+			symbols = append(symbols, symbol{kind: symbolRPC, token: token})
+		case prev == lexer.TokenColon || prev == lexer.TokenLBrack || prev == lexer.TokenRParen:
+			if (token.Value == "list" || token.Value == "map") && next == lexer.TokenLBrack {
+				continue
+			}
+			symbols = append(symbols, symbol{kind: symbolType, token: token, parent: parent})
+		case next == lexer.TokenColon:
+			kind := symbolField
+			if parentKind == symbolRPC {
+				kind = symbolParam
+			}
+			symbols = append(symbols, symbol{kind: kind, token: token, parent: parent})
+		}
+	}
+	return symbols
+}
+
+// symbolAt returns the symbol whose token contains pos.
+func (d *document) symbolAt(pos Position) (symbol, bool) {
+	for _, sym := range d.symbols {
+		r := d.tokenRange(sym.token)
+		if r.Start.Line == pos.Line && r.Start.Character <= pos.Character && pos.Character <= r.End.Character {
+			return sym, true
+		}
+	}
+	return symbol{}, false
+}
+
+func (d *document) modelDecl(name string) (symbol, bool) {
+	for _, sym := range d.symbols {
+		if sym.kind == symbolModel && sym.token.Value == name {
+			return sym, true
+		}
+	}
+	return symbol{}, false
+}
+
+// modelOccurrences lists the declaration and all type references of a model.
+func (d *document) modelOccurrences(name string, includeDecl bool) []symbol {
+	var out []symbol
+	for _, sym := range d.symbols {
+		if sym.token.Value != name {
+			continue
+		}
+		if sym.kind == symbolType || (includeDecl && sym.kind == symbolModel) {
+			out = append(out, sym)
+		}
+	}
+	return out
+}
+
+func (d *document) diagnostics() []Diagnostic {
+	diagnostics := make([]Diagnostic, 0, len(d.problems))
+	for _, problem := range d.problems {
+		diagnostics = append(diagnostics, Diagnostic{
+			Range: Range{
+				Start: d.position(problem.Range.Start.Line, problem.Range.Start.Col),
+				End:   d.position(problem.Range.End.Line, problem.Range.End.Col),
+			},
+			Severity: lspSeverity(problem.Severity),
+			Code:     problem.Code,
+			Source:   "rrpc",
+			Message:  problem.Message,
+		})
+	}
+	return diagnostics
+}
+
+func lspSeverity(severity parser.Severity) int {
+	switch severity {
+	case parser.SeverityWarning:
+		return severityWarning
+	case parser.SeverityInfo:
+		return severityInformation
+	default:
+		return severityError
+	}
+}
+
+func (d *document) tokenRange(token lexer.Token) Range {
+	return Range{
+		Start: d.position(token.Line, token.Col),
+		End:   d.position(token.Line, token.Col+utf8.RuneCountInString(token.Value)),
+	}
+}
+
+// position converts a 1-based line and rune column to an LSP position,
+// which counts UTF-16 code units.
+func (d *document) position(line, col int) Position {
+	pos := Position{Line: line - 1}
+	if line-1 >= len(d.lines) {
+		return pos
+	}
+	n := 0
+	for _, r := range d.lines[line-1] {
+		if n >= col-1 {
+			break
+		}
+		pos.Character += len(utf16.Encode([]rune{r}))
+		n++
+	}
+	return pos
+}
+
+func (d *document) fullRange() Range {
+	last := len(d.lines)
+	return Range{
+		Start: Position{},
+		End:   d.position(last, utf8.RuneCountInString(d.lines[last-1])+1),
+	}
+}
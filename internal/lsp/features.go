@@ -0,0 +1,377 @@
+package lsp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/formatter"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+)
+
+var builtinDocs = []struct {
+	name string
+	doc  string
+}{
+	{"string", "UTF-8 string"},
+	{"int", "Integer number"},
+	{"bool", "Boolean value"},
+	{"json", "Any JSON value, decoded by the generated code"},
+	{"raw", "Any JSON value, passed through as raw bytes"},
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func (s *Server) hover(params textDocumentPositionParams) (any, error) {
+	doc := s.docs[params.TextDocument.URI]
+	if doc == nil {
+		return nil, nil
+	}
+	sym, ok := doc.symbolAt(params.Position)
+	if !ok {
+		return nil, nil
+	}
+	var text string
+	switch sym.kind {
+	case symbolModel:
+		text = codeBlock(describeModel(doc.schema, sym.token.Value))
+	case symbolRPC:
+		text = codeBlock(describeRPC(doc.schema, sym.token.Value))
+	case symbolType:
+		for _, builtin := range builtinDocs {
+			if builtin.name == sym.token.Value {
+				text = codeBlock(builtin.name) + "\n" + builtin.doc
+			}
+		}
+		if text == "" {
+			text = codeBlock(describeModel(doc.schema, sym.token.Value))
+		}
+	case symbolField, symbolParam:
+		field, ok := findField(doc.schema, sym)
+		if !ok {
+			return nil, nil
+		}
+		text = codeBlock(field.Name + ": " + parser.FormatType(field.Type))
+		if model := baseTypeName(field.Type); model != "" {
+			if def := describeModel(doc.schema, model); def != "" {
+				text += "\n" + codeBlock(def)
+			}
+		}
+	}
+	if text == "" {
+		return nil, nil
+	}
+	return Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: text},
+		Range:    doc.tokenRange(sym.token),
+	}, nil
+}
+
+func (s *Server) definition(params textDocumentPositionParams) (any, error) {
+	doc := s.docs[params.TextDocument.URI]
+	if doc == nil {
+		return nil, nil
+	}
+	sym, ok := doc.symbolAt(params.Position)
+	if !ok {
+		return nil, nil
+	}
+	if sym.kind != symbolType && sym.kind != symbolModel {
+		return []Location{{URI: doc.uri, Range: doc.tokenRange(sym.token)}}, nil
+	}
+	decl, ok := doc.modelDecl(sym.token.Value)
+	if !ok {
+		return nil, nil
+	}
+	return []Location{{URI: doc.uri, Range: doc.tokenRange(decl.token)}}, nil
+}
+
+func (s *Server) references(params referenceParams) (any, error) {
+	doc := s.docs[params.TextDocument.URI]
+	if doc == nil {
+		return nil, nil
+	}
+	sym, ok := doc.symbolAt(params.Position)
+	if !ok || (sym.kind != symbolType && sym.kind != symbolModel) {
+		return nil, nil
+	}
+	locations := []Location{}
+	for _, occ := range doc.modelOccurrences(sym.token.Value, params.Context.IncludeDeclaration) {
+		locations = append(locations, Location{URI: doc.uri, Range: doc.tokenRange(occ.token)})
+	}
+	return locations, nil
+}
+
+func (s *Server) prepareRename(params textDocumentPositionParams) (any, error) {
+	doc := s.docs[params.TextDocument.URI]
+	if doc == nil {
+		return nil, nil
+	}
+	sym, ok := doc.symbolAt(params.Position)
+	if !ok || !renamable(doc, sym) {
+		return nil, nil
+	}
+	return doc.tokenRange(sym.token), nil
+}
+
+func (s *Server) rename(params renameParams) (any, error) {
+	doc := s.docs[params.TextDocument.URI]
+	if doc == nil {
+		return nil, nil
+	}
+	sym, ok := doc.symbolAt(params.Position)
+	if !ok || !renamable(doc, sym) {
+		return nil, fmt.Errorf("nothing to rename at this position")
+	}
+	if !identifierPattern.MatchString(params.NewName) || isReserved(params.NewName) {
+		return nil, fmt.Errorf("invalid name %q", params.NewName)
+	}
+	var targets []symbol
+	switch sym.kind {
+	case symbolModel, symbolType:
+		if _, exists := doc.modelDecl(params.NewName); exists && params.NewName != sym.token.Value {
+			return nil, fmt.Errorf("model %q already exists", params.NewName)
+		}
+		targets = doc.modelOccurrences(sym.token.Value, true)
+	default:
+		targets = []symbol{sym}
+	}
+	edits := make([]TextEdit, 0, len(targets))
+	for _, target := range targets {
+		edits = append(edits, TextEdit{Range: doc.tokenRange(target.token), NewText: params.NewName})
+	}
+	return WorkspaceEdit{Changes: map[string][]TextEdit{doc.uri: edits}}, nil
+}
+
+func renamable(doc *document, sym symbol) bool {
+	if sym.kind != symbolType {
+		return true
+	}
+	_, ok := doc.modelDecl(sym.token.Value)
+	return ok
+}
+
+func isReserved(name string) bool {
+	switch name {
+	case "model", "rpc", "list", "map":
+		return true
+	}
+	for _, builtin := range builtinDocs {
+		if builtin.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) completion(params textDocumentPositionParams) (any, error) {
+	doc := s.docs[params.TextDocument.URI]
+	if doc == nil {
+		return []CompletionItem{}, nil
+	}
+	items := []CompletionItem{}
+	ctx := completionContextAt(doc.text, doc.lines, params.Position)
+	if ctx.typePosition {
+		for _, builtin := range builtinDocs {
+			items = append(items, CompletionItem{Label: builtin.name, Kind: completionKindKeyword, Detail: builtin.doc})
+		}
+		items = append(items,
+			CompletionItem{Label: "list", Kind: completionKindKeyword, Detail: "List of values", InsertText: "list[$1]", InsertTextFormat: insertTextFormatSnippet},
+			CompletionItem{Label: "map", Kind: completionKindKeyword, Detail: "Map from string keys to values", InsertText: "map[$1]", InsertTextFormat: insertTextFormatSnippet},
+		)
+		for _, sym := range doc.symbols {
+			if sym.kind == symbolModel {
+				items = append(items, CompletionItem{Label: sym.token.Value, Kind: completionKindStruct, Detail: "model"})
+			}
+		}
+	}
+	if ctx.topLevel {
+		items = append(items,
+			CompletionItem{Label: "model", Kind: completionKindKeyword, InsertText: "model $1 {\n    $0\n}", InsertTextFormat: insertTextFormatSnippet},
+			CompletionItem{Label: "rpc", Kind: completionKindKeyword, InsertText: "rpc $1($2) $0", InsertTextFormat: insertTextFormatSnippet},
+		)
+	}
+	return items, nil
+}
+
+type completionContext struct {
+	typePosition bool
+	topLevel     bool
+}
+
+// completionContextAt looks at the text before the cursor, skipping the
+// identifier being typed, to decide what can be written there.
+func completionContextAt(text string, lines []string, pos Position) completionContext {
+	offset := 0
+	for i := 0; i < pos.Line && i < len(lines); i++ {
+		offset += len(lines[i]) + 1
+	}
+	if pos.Line < len(lines) {
+		offset += utf16Offset(lines[pos.Line], pos.Character)
+	}
+	if offset > len(text) {
+		offset = len(text)
+	}
+	before := stripComments(text[:offset])
+	trimmed := strings.TrimRight(before, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_")
+	trimmed = strings.TrimRight(trimmed, " \t\r\n")
+
+	depth := 0
+	for _, r := range trimmed {
+		switch r {
+		case '{', '(':
+			depth++
+		case '}', ')':
+			depth--
+		}
+	}
+	var ctx completionContext
+	if trimmed == "" {
+		ctx.topLevel = true
+		return ctx
+	}
+	switch trimmed[len(trimmed)-1] {
+	case ':', '[':
+		ctx.typePosition = true
+	case ')':
+		ctx.typePosition = depth == 0
+		ctx.topLevel = depth == 0
+	default:
+		ctx.topLevel = depth == 0
+	}
+	return ctx
+}
+
+func stripComments(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if idx := strings.IndexByte(line, '#'); idx >= 0 {
+			lines[i] = line[:idx]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// utf16Offset converts a UTF-16 character offset within line to a byte offset.
+func utf16Offset(line string, character int) int {
+	units := 0
+	for i, r := range line {
+		if units >= character {
+			return i
+		}
+		if r >= 0x10000 {
+			units += 2
+		} else {
+			units++
+		}
+	}
+	return len(line)
+}
+
+func (s *Server) formatting(params formattingParams) (any, error) {
+	doc := s.docs[params.TextDocument.URI]
+	if doc == nil || len(doc.problems) > 0 {
+		return nil, nil
+	}
+	formatted, err := formatter.FormatSchema(doc.schema)
+	if err != nil {
+		return nil, err
+	}
+	if formatted == doc.text {
+		return []TextEdit{}, nil
+	}
+	return []TextEdit{{Range: doc.fullRange(), NewText: formatted}}, nil
+}
+
+func codeBlock(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "```rrpc\n" + text + "\n```"
+}
+
+func describeModel(schema *parser.Schema, name string) string {
+	if schema == nil {
+		return ""
+	}
+	for _, model := range schema.Models {
+		if model.Name != name {
+			continue
+		}
+		var b strings.Builder
+		b.WriteString("model " + model.Name + " {\n")
+		for _, field := range model.Fields {
+			b.WriteString("    " + field.Name + ": " + parser.FormatType(field.Type) + "\n")
+		}
+		b.WriteString("}")
+		return b.String()
+	}
+	return ""
+}
+
+func describeRPC(schema *parser.Schema, name string) string {
+	if schema == nil {
+		return ""
+	}
+	for _, rpc := range schema.RPCs {
+		if rpc.Name != name {
+			continue
+		}
+		params := make([]string, 0, len(rpc.Parameters))
+		for _, param := range rpc.Parameters {
+			params = append(params, param.Name+": "+parser.FormatType(param.Type))
+		}
+		text := "rpc " + rpc.Name + "(" + strings.Join(params, ", ") + ")"
+		if rpc.HasReturn {
+			text += " " + parser.FormatType(rpc.Returns)
+		}
+		return text
+	}
+	return ""
+}
+
+func findField(schema *parser.Schema, sym symbol) (parser.Field, bool) {
+	if schema == nil {
+		return parser.Field{}, false
+	}
+	var fields []parser.Field
+	if sym.kind == symbolParam {
+		for _, rpc := range schema.RPCs {
+			if rpc.Name == sym.parent {
+				fields = rpc.Parameters
+			}
+		}
+	} else {
+		for _, model := range schema.Models {
+			if model.Name == sym.parent {
+				fields = model.Fields
+			}
+		}
+	}
+	for _, field := range fields {
+		if field.Name == sym.token.Value {
+			return field, true
+		}
+	}
+	return parser.Field{}, false
+}
+
+// baseTypeName returns the model referenced by t through lists and maps,
+// or "" for builtins.
+func baseTypeName(t parser.TypeRef) string {
+	switch t.Kind {
+	case parser.TypeList:
+		if t.Elem != nil {
+			return baseTypeName(*t.Elem)
+		}
+	case parser.TypeMap:
+		if t.Value != nil {
+			return baseTypeName(*t.Value)
+		}
+	default:
+		if !isReserved(t.Name) {
+			return t.Name
+		}
+	}
+	return ""
+}
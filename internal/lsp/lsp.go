@@ -0,0 +1,194 @@
+// Package lsp implements a Language Server Protocol server for .rrpc files.
+// It supports full document sync, diagnostics, hover, go-to-definition,
+// references, rename, completion and formatting.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+type Server struct {
+	in       *bufio.Reader
+	out      io.Writer
+	docs     map[string]*document
+	shutdown bool
+}
+
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		in:   bufio.NewReader(in),
+		out:  out,
+		docs: make(map[string]*document),
+	}
+}
+
+// Run serves requests until the client sends exit or closes the input.
+func (s *Server) Run() error {
+	for {
+		body, err := s.readMessage()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := s.reply(nil, nil, &responseError{Code: codeParseError, Message: err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			return nil
+		}
+		result, rpcErr := s.handle(req)
+		if req.ID == nil {
+			continue
+		}
+		if err := s.reply(req.ID, result, rpcErr); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(req request) (any, *responseError) {
+	if s.shutdown && req.ID != nil {
+		return nil, &responseError{Code: codeInvalidRequest, Message: "server is shut down"}
+	}
+	switch req.Method {
+	case "initialize":
+		return map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync":           1,
+				"hoverProvider":              true,
+				"definitionProvider":         true,
+				"referencesProvider":         true,
+				"renameProvider":             map[string]any{"prepareProvider": true},
+				"completionProvider":         map[string]any{"triggerCharacters": []string{":", "["}},
+				"documentFormattingProvider": true,
+			},
+			"serverInfo": map[string]any{"name": "rrpc"},
+		}, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		s.update(params.TextDocument.URI, params.TextDocument.Text)
+		return nil, nil
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		if n := len(params.ContentChanges); n > 0 {
+			s.update(params.TextDocument.URI, params.ContentChanges[n-1].Text)
+		}
+		return nil, nil
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		delete(s.docs, params.TextDocument.URI)
+		s.publish(params.TextDocument.URI, []Diagnostic{})
+		return nil, nil
+	case "textDocument/hover":
+		return call(req.Params, s.hover)
+	case "textDocument/definition":
+		return call(req.Params, s.definition)
+	case "textDocument/references":
+		return call(req.Params, s.references)
+	case "textDocument/prepareRename":
+		return call(req.Params, s.prepareRename)
+	case "textDocument/rename":
+		return call(req.Params, s.rename)
+	case "textDocument/completion":
+		return call(req.Params, s.completion)
+	case "textDocument/formatting":
+		return call(req.Params, s.formatting)
+	}
+	if req.ID == nil {
+		return nil, nil
+	}
+	return nil, &responseError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+}
+
+func call[P any](raw json.RawMessage, fn func(P) (any, error)) (any, *responseError) {
+	var params P
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, invalidParams(err)
+	}
+	result, err := fn(params)
+	if err != nil {
+		return nil, &responseError{Code: codeRequestFailed, Message: err.Error()}
+	}
+	return result, nil
+}
+
+func invalidParams(err error) *responseError {
+	return &responseError{Code: codeInvalidParams, Message: err.Error()}
+}
+
+func (s *Server) update(uri, text string) {
+	doc := newDocument(uri, text)
+	s.docs[uri] = doc
+	s.publish(uri, doc.diagnostics())
+}
+
+func (s *Server) publish(uri string, diagnostics []Diagnostic) {
+	// A failed write surfaces on the next reply.
+	_ = s.write(notification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  publishDiagnosticsParams{URI: uri, Diagnostics: diagnostics},
+	})
+}
+
+func (s *Server) reply(id json.RawMessage, result any, rpcErr *responseError) error {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return s.write(response{JSONRPC: "2.0", ID: id, Result: result, Error: rpcErr})
+}
+
+func (s *Server) readMessage() ([]byte, error) {
+	headers, err := textproto.NewReader(s.in).ReadMIMEHeader()
+	if err != nil {
+		if err == io.EOF || (len(headers) == 0 && err == io.ErrUnexpectedEOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	length, err := strconv.Atoi(strings.TrimSpace(headers.Get("Content-Length")))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length header %q", headers.Get("Content-Length"))
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.in, body); err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	return body, nil
+}
+
+func (s *Server) write(message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n", len(data)); err != nil {
+		return err
+	}
+	_, err = s.out.Write(data)
+	return err
+}
@@ -0,0 +1,247 @@
+package lsp_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Rapid-Vision/rRPC/internal/lsp"
+)
+
+const testURI = "file:///api.rrpc"
+
+type client struct {
+	t      *testing.T
+	in     *io.PipeWriter
+	out    *bufio.Reader
+	nextID int
+	done   chan error
+}
+
+func startServer(t *testing.T) *client {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := &client{t: t, in: inW, out: bufio.NewReader(outR), done: make(chan error, 1)}
+	go func() {
+		c.done <- lsp.NewServer(inR, outW).Run()
+		outW.Close()
+	}()
+	t.Cleanup(func() {
+		c.notify("exit", nil)
+		if err := <-c.done; err != nil {
+			t.Errorf("server: %v", err)
+		}
+	})
+	c.request("initialize", map[string]any{"capabilities": map[string]any{}})
+	return c
+}
+
+func (c *client) send(message map[string]any) {
+	c.t.Helper()
+	message["jsonrpc"] = "2.0"
+	data, err := json.Marshal(message)
+	if err != nil {
+		c.t.Fatalf("marshal: %v", err)
+	}
+	if _, err := fmt.Fprintf(c.in, "Content-Length: %d\r\n\r\n%s", len(data), data); err != nil {
+		c.t.Fatalf("write: %v", err)
+	}
+}
+
+func (c *client) read() map[string]json.RawMessage {
+	c.t.Helper()
+	headers, err := textproto.NewReader(c.out).ReadMIMEHeader()
+	if err != nil {
+		c.t.Fatalf("read header: %v", err)
+	}
+	length, _ := strconv.Atoi(headers.Get("Content-Length"))
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.out, body); err != nil {
+		c.t.Fatalf("read body: %v", err)
+	}
+	var message map[string]json.RawMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		c.t.Fatalf("decode: %v", err)
+	}
+	return message
+}
+
+func (c *client) notify(method string, params any) {
+	c.send(map[string]any{"method": method, "params": params})
+}
+
+func (c *client) request(method string, params any) json.RawMessage {
+	c.t.Helper()
+	c.nextID++
+	c.send(map[string]any{"id": c.nextID, "method": method, "params": params})
+	for {
+		message := c.read()
+		if _, ok := message["id"]; !ok {
+			continue
+		}
+		if errData, ok := message["error"]; ok {
+			c.t.Fatalf("%s failed: %s", method, errData)
+		}
+		return message["result"]
+	}
+}
+
+func (c *client) open(text string) []map[string]any {
+	c.t.Helper()
+	c.notify("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": testURI, "languageId": "rrpc", "version": 1, "text": text},
+	})
+	message := c.read()
+	var params struct {
+		Diagnostics []map[string]any `json:"diagnostics"`
+	}
+	if err := json.Unmarshal(message["params"], &params); err != nil {
+		c.t.Fatalf("decode diagnostics: %v", err)
+	}
+	return params.Diagnostics
+}
+
+func at(line, character int) map[string]any {
+	return map[string]any{
+		"textDocument": map[string]any{"uri": testURI},
+		"position":     map[string]any{"line": line, "character": character},
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	c := startServer(t)
+	if diags := c.open("model Text {\n    body: string\n}\n\nrpc GetText() Text\n"); len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	diags := c.open("model Text {\n    title string\n}\n")
+	if len(diags) != 1 {
+		t.Fatalf("expected one diagnostic, got %v", diags)
+	}
+	data, _ := json.Marshal(diags[0]["range"])
+	if string(data) != `{"end":{"character":16,"line":1},"start":{"character":10,"line":1}}` {
+		t.Fatalf("unexpected range: %s", data)
+	}
+}
+
+func TestNavigation(t *testing.T) {
+	c := startServer(t)
+	c.open(`model Text {
+    title: string?
+    body: string
+}
+
+model Page {
+    texts: list[Text]
+}
+
+rpc GetPage(
+    page_id: int,
+) Page
+
+rpc SubmitText(text: Text)
+`)
+
+	// "Text" inside list[Text] on line 6.
+	definition := c.request("textDocument/definition", at(6, 17))
+	if !strings.Contains(string(definition), `"start":{"line":0,"character":6}`) {
+		t.Fatalf("unexpected definition: %s", definition)
+	}
+
+	refs := at(0, 7)
+	refs["context"] = map[string]any{"includeDeclaration": true}
+	var locations []json.RawMessage
+	if err := json.Unmarshal(c.request("textDocument/references", refs), &locations); err != nil {
+		t.Fatalf("decode references: %v", err)
+	}
+	if len(locations) != 3 {
+		t.Fatalf("expected 3 references, got %d", len(locations))
+	}
+
+	rename := at(0, 7)
+	rename["newName"] = "Note"
+	var edit struct {
+		Changes map[string][]struct {
+			NewText string `json:"newText"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal(c.request("textDocument/rename", rename), &edit); err != nil {
+		t.Fatalf("decode rename: %v", err)
+	}
+	if len(edit.Changes[testURI]) != 3 {
+		t.Fatalf("expected 3 edits, got %+v", edit)
+	}
+}
+
+func TestHover(t *testing.T) {
+	c := startServer(t)
+	c.open(`model Text {
+    title: string?
+    body: string
+}
+
+model Page {
+    texts: list[Text]
+}
+
+rpc GetPage(
+    page_id: int,
+) Page
+
+rpc SubmitText(text: Text)
+`)
+
+	var hover struct {
+		Contents struct {
+			Value string `json:"value"`
+		} `json:"contents"`
+	}
+	// The "texts" field of Page.
+	if err := json.Unmarshal(c.request("textDocument/hover", at(6, 5)), &hover); err != nil {
+		t.Fatalf("decode hover: %v", err)
+	}
+	if !strings.Contains(hover.Contents.Value, "texts: list[Text]") || !strings.Contains(hover.Contents.Value, "body: string") {
+		t.Fatalf("unexpected hover: %q", hover.Contents.Value)
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	c := startServer(t)
+	c.open("model Text {\n    body: string\n}\n\nrpc Get(id: \n")
+
+	var items []struct {
+		Label string `json:"label"`
+	}
+	if err := json.Unmarshal(c.request("textDocument/completion", at(4, 12)), &items); err != nil {
+		t.Fatalf("decode completion: %v", err)
+	}
+	labels := make([]string, 0, len(items))
+	for _, item := range items {
+		labels = append(labels, item.Label)
+	}
+	got := strings.Join(labels, ",")
+	if got != "string,int,bool,json,raw,list,map,Text" {
+		t.Fatalf("unexpected completion items: %s", got)
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	c := startServer(t)
+	c.open("model Text {\n  body:   string\n}\n")
+
+	var edits []struct {
+		NewText string `json:"newText"`
+	}
+	params := map[string]any{"textDocument": map[string]any{"uri": testURI}, "options": map[string]any{"tabSize": 4}}
+	if err := json.Unmarshal(c.request("textDocument/formatting", params), &edits); err != nil {
+		t.Fatalf("decode formatting: %v", err)
+	}
+	if len(edits) != 1 || edits[0].NewText != "model Text {\n    body: string\n}\n" {
+		t.Fatalf("unexpected edits: %+v", edits)
+	}
+}
@@ -0,0 +1,103 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/protocol"
+)
+
+const maxFakeDepth = 4
+
+var fakeWords = []string{
+	"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel",
+	"india", "juliet", "kilo", "lima", "mike", "november", "oscar", "papa",
+}
+
+type faker struct {
+	rng    *rand.Rand
+	models map[string]parser.Model
+}
+
+func (f *faker) value(t parser.TypeRef, depth int) any {
+	if t.Optional && (depth > maxFakeDepth || f.rng.Intn(4) == 0) {
+		return nil
+	}
+	switch t.Kind {
+	case parser.TypeList:
+		items := []any{}
+		if t.Elem == nil || depth > maxFakeDepth {
+			return items
+		}
+		for i := 0; i < 1+f.rng.Intn(3); i++ {
+			items = append(items, f.value(*t.Elem, depth+1))
+		}
+		return items
+	case parser.TypeMap:
+		entries := map[string]any{}
+		if t.Value == nil || depth > maxFakeDepth {
+			return entries
+		}
+		for i := 0; i < 1+f.rng.Intn(3); i++ {
+			entries[fmt.Sprintf("%s_%d", f.word(), i)] = f.value(*t.Value, depth+1)
+		}
+		return entries
+	}
+	switch t.Name {
+	case "string":
+		return f.word() + "-" + f.word()
+	case "int":
+		return f.rng.Intn(1000)
+	case "bool":
+		return f.rng.Intn(2) == 1
+	case "json", "raw":
+		return map[string]any{f.word(): f.word()}
+	}
+	model, ok := f.models[t.Name]
+	if !ok || depth > maxFakeDepth {
+		return object{}
+	}
+	out := make(object, 0, len(model.Fields))
+	for _, field := range model.Fields {
+		out = append(out, member{Key: protocol.JSONName(field.Name), Value: f.value(field.Type, depth+1)})
+	}
+	return out
+}
+
+func (f *faker) word() string {
+	return fakeWords[f.rng.Intn(len(fakeWords))]
+}
+
+// object is a JSON object that keeps the schema field order when encoded.
+type object []member
+
+type member struct {
+	Key   string
+	Value any
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,115 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/protocol"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixtures maps RPC names, as written in the schema, to canned responses.
+type Fixtures map[string]Fixture
+
+// Fixture replaces the generated response of one RPC. Result is the
+// unwrapped return value; Error makes the RPC fail with the given error.
+type Fixture struct {
+	Result any             `json:"result" yaml:"result"`
+	Error  *protocol.Error `json:"error" yaml:"error"`
+}
+
+// ParseFixtures reads fixtures from JSON or YAML, picked by the file
+// extension of name.
+func ParseFixtures(name string, data []byte) (Fixtures, error) {
+	var raw map[string]any
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("parse fixtures: %w", err)
+		}
+	default:
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("parse fixtures: %w", err)
+		}
+	}
+	// Round-trip through JSON so that YAML and JSON input produce the same
+	// value types.
+	normalized, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse fixtures: %w", err)
+	}
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(normalized, &entries); err != nil {
+		return nil, fmt.Errorf("parse fixtures: %w", err)
+	}
+	fixtures := make(Fixtures, len(entries))
+	for name, entry := range entries {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(entry, &fields); err != nil {
+			return nil, fmt.Errorf("fixture %q: expected object with result or error", name)
+		}
+		var fixture Fixture
+		for key, value := range fields {
+			switch key {
+			case "result":
+				decoded, err := protocol.Decode(value)
+				if err != nil {
+					return nil, fmt.Errorf("fixture %q: %w", name, err)
+				}
+				fixture.Result = decoded
+			case "error":
+				var rpcErr protocol.Error
+				if err := json.Unmarshal(value, &rpcErr); err != nil {
+					return nil, fmt.Errorf("fixture %q: error: %w", name, err)
+				}
+				fixture.Error = &rpcErr
+			default:
+				return nil, fmt.Errorf("fixture %q: unknown key %q (expected result or error)", name, key)
+			}
+		}
+		fixtures[name] = fixture
+	}
+	return fixtures, nil
+}
+
+func (f Fixtures) validate(schema *parser.Schema, models map[string]parser.Model) error {
+	rpcs := make(map[string]parser.RPC, len(schema.RPCs))
+	for _, rpc := range schema.RPCs {
+		rpcs[rpc.Name] = rpc
+	}
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fixture := f[name]
+		rpc, ok := rpcs[name]
+		if !ok {
+			return fmt.Errorf("fixture %q: unknown rpc", name)
+		}
+		if fixture.Error != nil && fixture.Result != nil {
+			return fmt.Errorf("fixture %q: result and error are mutually exclusive", name)
+		}
+		if fixture.Error != nil {
+			if _, ok := protocol.ErrorStatus[fixture.Error.Type]; !ok {
+				return fmt.Errorf("fixture %q: unknown error type %q", name, fixture.Error.Type)
+			}
+			continue
+		}
+		if fixture.Result == nil {
+			continue
+		}
+		if !rpc.HasReturn {
+			return fmt.Errorf("fixture %q: rpc has no return type", name)
+		}
+		if err := protocol.ValidateValue(fixture.Result, rpc.Returns, models, "result"); err != nil {
+			return fmt.Errorf("fixture %q: %w", name, err)
+		}
+	}
+	return nil
+}
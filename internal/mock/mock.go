@@ -0,0 +1,121 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/protocol"
+)
+
+type Options struct {
+	Prefix string
+	// Seed makes responses deterministic: every call of an RPC returns the
+	// same data. Ignored when Random is set.
+	Seed int64
+	// Random generates new data for every call.
+	Random   bool
+	Fixtures Fixtures
+}
+
+type server struct {
+	schema *parser.Schema
+	models map[string]parser.Model
+	opts   Options
+	mu     sync.Mutex
+	rng    *rand.Rand
+}
+
+func NewHandler(schema *parser.Schema, opts Options) (http.Handler, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("schema is nil")
+	}
+	models := protocol.ModelIndex(schema)
+	if err := opts.Fixtures.validate(schema, models); err != nil {
+		return nil, err
+	}
+	s := &server{
+		schema: schema,
+		models: models,
+		opts:   opts,
+		rng:    rand.New(rand.NewSource(opts.Seed)),
+	}
+	mux := http.NewServeMux()
+	for _, rpc := range schema.RPCs {
+		mux.Handle("POST "+protocol.RPCPath(opts.Prefix, rpc.Name), s.rpcHandler(rpc))
+	}
+	return mux, nil
+}
+
+func (s *server) rpcHandler(rpc parser.RPC) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(rpc.Parameters) > 0 {
+			raw, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, protocol.Error{Type: protocol.ErrorTypeInput, Message: err.Error()})
+				return
+			}
+			var params any = map[string]any{}
+			if len(bytes.TrimSpace(raw)) > 0 {
+				params, err = protocol.Decode(raw)
+				if err != nil {
+					writeJSON(w, http.StatusBadRequest, protocol.Error{Type: protocol.ErrorTypeInput, Message: err.Error()})
+					return
+				}
+			}
+			if err := protocol.ValidateParams(params, rpc.Parameters, s.models); err != nil {
+				writeJSON(w, http.StatusBadRequest, protocol.Error{Type: protocol.ErrorTypeInput, Message: err.Error()})
+				return
+			}
+		}
+
+		if fixture, ok := s.opts.Fixtures[rpc.Name]; ok {
+			if fixture.Error != nil {
+				writeJSON(w, protocol.ErrorStatus[fixture.Error.Type], fixture.Error)
+				return
+			}
+			if fixture.Result != nil {
+				writeJSON(w, http.StatusOK, map[string]any{protocol.ResultKey(rpc.Returns): fixture.Result})
+				return
+			}
+		}
+		if !rpc.HasReturn {
+			writeJSON(w, http.StatusOK, map[string]any{})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{protocol.ResultKey(rpc.Returns): s.fake(rpc)})
+	})
+}
+
+func (s *server) fake(rpc parser.RPC) any {
+	if s.opts.Random {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		f := faker{rng: s.rng, models: s.models}
+		return f.value(rpc.Returns, 0)
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(rpc.Name))
+	f := faker{rng: rand.New(rand.NewSource(s.opts.Seed ^ int64(h.Sum64()))), models: s.models}
+	return f.value(rpc.Returns, 0)
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(payload); err != nil {
+		status = http.StatusInternalServerError
+		buf.Reset()
+		_ = encoder.Encode(protocol.Error{Type: protocol.ErrorTypeCustom, Message: err.Error()})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
@@ -0,0 +1,207 @@
+package mock_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Rapid-Vision/rRPC/internal/conformance"
+	"github.com/Rapid-Vision/rRPC/internal/mock"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+)
+
+func newServer(t *testing.T, schema *parser.Schema, opts mock.Options) *httptest.Server {
+	t.Helper()
+	handler, err := mock.NewHandler(schema, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func post(t *testing.T, url, body string) (int, string) {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp.StatusCode, string(raw)
+}
+
+func TestMockPassesConformance(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string?
+    body: string
+}
+
+model Page {
+    texts: list[Text]
+    meta: map[int]
+    next: Page?
+}
+
+rpc GetPage(
+    page_id: int,
+    lang: string?,
+) Page
+
+rpc GetText() Text
+
+rpc Ping()
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := newServer(t, schema, mock.Options{Prefix: "rpc", Seed: 1})
+	results, err := conformance.Run(context.Background(), schema, conformance.Options{BaseURL: server.URL, Prefix: "rpc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, result := range results {
+		if !result.Passed {
+			t.Fatalf("%s %s failed: %s", result.RPC, result.Name, result.Detail)
+		}
+	}
+}
+
+func TestMockIsDeterministic(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string?
+    body: string
+}
+
+model Page {
+    texts: list[Text]
+    meta: map[int]
+    next: Page?
+}
+
+rpc GetPage(
+    page_id: int,
+    lang: string?,
+) Page
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := newServer(t, schema, mock.Options{Prefix: "rpc", Seed: 7})
+	second := newServer(t, schema, mock.Options{Prefix: "rpc", Seed: 7})
+	_, a := post(t, first.URL+"/rpc/get_page", `{"page_id": 1}`)
+	_, b := post(t, first.URL+"/rpc/get_page", `{"page_id": 2}`)
+	_, c := post(t, second.URL+"/rpc/get_page", `{"page_id": 1}`)
+	if a != b || a != c {
+		t.Fatalf("expected identical responses, got:\n%s\n%s\n%s", a, b, c)
+	}
+}
+
+func TestMockRejectsInvalidParams(t *testing.T) {
+	schema, err := parser.Parse("rpc GetPage(page_id: int)\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := newServer(t, schema, mock.Options{Prefix: "rpc"})
+	status, body := post(t, server.URL+"/rpc/get_page", `{"page_id": "one"}`)
+	if status != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", status)
+	}
+	want := `{"type":"input","message":"page_id: expected int, got string"}`
+	if body != want {
+		t.Fatalf("unexpected body %s, want %s", body, want)
+	}
+}
+
+func TestMockFixtures(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string?
+    body: string
+}
+
+rpc GetText() Text
+
+rpc Ping()
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fixtures, err := mock.ParseFixtures("fixtures.yaml", []byte(`GetText:
+  result:
+    title: null
+    body: fixed
+Ping:
+  error:
+    type: forbidden
+    message: nope
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := newServer(t, schema, mock.Options{Prefix: "rpc", Fixtures: fixtures})
+	status, body := post(t, server.URL+"/rpc/get_text", "")
+	if status != http.StatusOK || body != `{"text":{"body":"fixed","title":null}}` {
+		t.Fatalf("unexpected response %d %s", status, body)
+	}
+	status, body = post(t, server.URL+"/rpc/ping", "")
+	if status != http.StatusForbidden || body != `{"type":"forbidden","message":"nope"}` {
+		t.Fatalf("unexpected response %d %s", status, body)
+	}
+}
+
+func TestMockRejectsInvalidFixtures(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    body: string
+}
+
+rpc GetText() Text
+
+rpc Ping()
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		fixtures string
+		wantErr  string
+	}{
+		{`{"Missing": {"result": 1}}`, `fixture "Missing": unknown rpc`},
+		{`{"GetText": {"result": {"body": 1}}}`, `fixture "GetText": result.body: expected string, got number`},
+		{`{"Ping": {"result": {}}}`, `fixture "Ping": rpc has no return type`},
+		{`{"Ping": {"error": {"type": "oops", "message": ""}}}`, `fixture "Ping": unknown error type "oops"`},
+	}
+	for _, tt := range tests {
+		fixtures, err := mock.ParseFixtures("fixtures.json", []byte(tt.fixtures))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = mock.NewHandler(schema, mock.Options{Fixtures: fixtures})
+		if err == nil || err.Error() != tt.wantErr {
+			t.Fatalf("NewHandler(%s) = %v, want %q", tt.fixtures, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMockRecursiveModel(t *testing.T) {
+	schema, err := parser.Parse(`model Node {
+    next: Node
+}
+
+rpc GetNode() Node
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := newServer(t, schema, mock.Options{Prefix: "rpc"})
+	status, body := post(t, server.URL+"/rpc/get_node", "")
+	if status != http.StatusOK || !strings.HasPrefix(body, `{"node":{"next":{"next":`) {
+		t.Fatalf("unexpected response %d %s", status, body)
+	}
+}
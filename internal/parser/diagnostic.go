@@ -0,0 +1,216 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Diagnostic codes reported by the parser and ValidateSchema.
+const (
+	CodeUnexpectedCharacter = "unexpected_character"
+	CodeSyntax              = "syntax_error"
+	CodeDuplicateModel      = "duplicate_model"
+	CodeDuplicateRPC        = "duplicate_rpc"
+	CodeDuplicateField      = "duplicate_field"
+	CodeDuplicateParameter  = "duplicate_parameter"
+	CodeEmptyName           = "empty_name"
+	CodeInvalidType         = "invalid_type"
+	CodeUnknownType         = "unknown_type"
+)
+
+// Position is a 1-based line and column. Columns count runes.
+type Position struct {
+	Line int `json:"line"`
+	Col  int `json:"column"`
+}
+
+// Range is a span of source text; End is exclusive.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Diagnostic is a problem found in a schema. File is empty unless set by
+// the caller, since the parser only sees text.
+type Diagnostic struct {
+	File     string   `json:"file,omitempty"`
+	Range    Range    `json:"range"`
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+}
+
+// newDiagnostic returns an error spanning length runes on one line.
+func newDiagnostic(code string, line, col, length int, message string) Diagnostic {
+	return Diagnostic{
+		Range: Range{
+			Start: Position{Line: line, Col: col},
+			End:   Position{Line: line, Col: col + length},
+		},
+		Severity: SeverityError,
+		Code:     code,
+		Message:  message,
+	}
+}
+
+func (d Diagnostic) Error() string {
+	var b strings.Builder
+	if d.File != "" {
+		b.WriteString(d.File + ":")
+	}
+	if d.Range.Start.Line > 0 {
+		fmt.Fprintf(&b, "%d:%d:", d.Range.Start.Line, d.Range.Start.Col)
+	}
+	if b.Len() > 0 {
+		b.WriteString(" ")
+	}
+	b.WriteString(d.Message)
+	return b.String()
+}
+
+// Diagnostics is the error returned by Parse and ValidateSchema.
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) Error() string {
+	if len(d) == 1 {
+		return d[0].Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d errors:", len(d))
+	for _, diagnostic := range d {
+		b.WriteString("\n  ")
+		b.WriteString(diagnostic.Error())
+	}
+	return b.String()
+}
+
+// WithFile returns a copy of the diagnostics attributed to file.
+func (d Diagnostics) WithFile(file string) Diagnostics {
+	out := make(Diagnostics, len(d))
+	for i, diagnostic := range d {
+		diagnostic.File = file
+		out[i] = diagnostic
+	}
+	return out
+}
+
+// SortDiagnostics orders diagnostics by file and start position.
+func SortDiagnostics(diagnostics []Diagnostic) {
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i], diagnostics[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Range.Start.Line != b.Range.Start.Line {
+			return a.Range.Start.Line < b.Range.Start.Line
+		}
+		return a.Range.Start.Col < b.Range.Start.Col
+	})
+}
+
+// WriteDiagnosticsText renders each diagnostic with the offending source
+// line and a caret underline. sources maps file names to their contents.
+func WriteDiagnosticsText(w io.Writer, diagnostics []Diagnostic, sources map[string]string) error {
+	for i, diagnostic := range diagnostics {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(w, renderDiagnostic(diagnostic, sources[diagnostic.File])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func WriteDiagnosticsJSON(w io.Writer, diagnostics []Diagnostic) error {
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Diagnostics []Diagnostic `json:"diagnostics"`
+	}{Diagnostics: diagnostics})
+}
+
+func renderDiagnostic(d Diagnostic, source string) string {
+	var b strings.Builder
+	b.WriteString(string(d.Severity))
+	if d.Code != "" {
+		b.WriteString("[" + d.Code + "]")
+	}
+	b.WriteString(": " + d.Message + "\n")
+
+	start := d.Range.Start
+	location := d.File
+	if location == "" {
+		location = "<schema>"
+	}
+	if start.Line > 0 {
+		location += fmt.Sprintf(":%d:%d", start.Line, start.Col)
+	}
+	lines := strings.Split(source, "\n")
+	if start.Line < 1 || start.Line > len(lines) {
+		b.WriteString(" --> " + location + "\n")
+		return b.String()
+	}
+
+	line := strings.TrimRight(lines[start.Line-1], "\r")
+	number := strconv.Itoa(start.Line)
+	gutter := strings.Repeat(" ", len(number))
+	b.WriteString(gutter + "--> " + location + "\n")
+	b.WriteString(gutter + " |\n")
+	b.WriteString(number + " | " + line + "\n")
+	b.WriteString(gutter + " | " + underline(line, start.Col, underlineWidth(d.Range, line)) + "\n")
+	return b.String()
+}
+
+// underlineWidth is the number of carets for r on line, which ends at the
+// end of the line for multi-line ranges and is at least one.
+func underlineWidth(r Range, line string) int {
+	width := r.End.Col - r.Start.Col
+	if r.End.Line > r.Start.Line {
+		width = utf8.RuneCountInString(line) - r.Start.Col + 1
+	}
+	if width < 1 {
+		width = 1
+	}
+	return width
+}
+
+// underline keeps tabs from the source so carets line up in any editor.
+func underline(line string, col, width int) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range line {
+		if n >= col-1 {
+			break
+		}
+		if r == '\t' {
+			b.WriteRune('\t')
+		} else {
+			b.WriteRune(' ')
+		}
+		n++
+	}
+	for ; n < col-1; n++ {
+		b.WriteRune(' ')
+	}
+	b.WriteString(strings.Repeat("^", width))
+	return b.String()
+}
@@ -1,8 +1,10 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/Rapid-Vision/rRPC/internal/lexer"
 )
@@ -62,12 +64,14 @@ func (s *Schema) Dump() string {
 }
 
 type Model struct {
-	Name    string
-	Fields  []Field
-	Line    int
-	Col     int
-	EndLine int
-	EndCol  int
+	Name     string
+	Fields   []Field
+	Line     int
+	Col      int
+	NameLine int
+	NameCol  int
+	EndLine  int
+	EndCol   int
 }
 
 type RPC struct {
@@ -77,6 +81,8 @@ type RPC struct {
 	HasReturn     bool
 	Line          int
 	Col           int
+	NameLine      int
+	NameCol       int
 	ParamsEndLine int
 	ParamsEndCol  int
 }
@@ -126,14 +132,31 @@ const (
 )
 
 type Parser struct {
-	tokens []lexer.Token
-	pos    int
+	tokens      []lexer.Token
+	pos         int
+	eofLine     int
+	eofCol      int
+	diagnostics []Diagnostic
 }
 
+// Parse parses and validates a schema. All problems are returned together
+// as Diagnostics.
 func Parse(text string) (*Schema, error) {
-	tokens, err := lexer.NewLexer(text).Tokenize()
-	if err != nil {
-		return nil, err
+	schema, diagnostics := ParseWithDiagnostics(text)
+	if len(diagnostics) > 0 {
+		return nil, Diagnostics(diagnostics)
+	}
+	return schema, nil
+}
+
+// ParseWithDiagnostics parses and validates a schema, recovering from
+// errors at declaration boundaries. It always returns a schema, which may
+// be partial, and the problems found sorted by position.
+func ParseWithDiagnostics(text string) (*Schema, []Diagnostic) {
+	tokens, lexErrs := lexer.NewLexer(text).TokenizeAll()
+	var diagnostics []Diagnostic
+	for _, lexErr := range lexErrs {
+		diagnostics = append(diagnostics, newDiagnostic(CodeUnexpectedCharacter, lexErr.Line, lexErr.Col, 1, fmt.Sprintf("unexpected character %q", lexErr.Ch)))
 	}
 	parseTokens := make([]lexer.Token, 0, len(tokens))
 	comments := make([]Comment, 0)
@@ -149,29 +172,27 @@ func Parse(text string) (*Schema, error) {
 		parseTokens = append(parseTokens, token)
 	}
 	p := NewParser(parseTokens)
-	schema, err := p.parseSchema()
-	if err != nil {
-		return nil, err
-	}
-	if err := ValidateSchema(schema); err != nil {
-		return nil, err
-	}
+	p.eofLine, p.eofCol = endPosition(text)
+	schema := p.parseSchema()
+	diagnostics = append(diagnostics, p.diagnostics...)
+	diagnostics = append(diagnostics, validateSchema(schema)...)
 	schema.Comments = comments
-	return schema, nil
+	SortDiagnostics(diagnostics)
+	return schema, diagnostics
 }
 
 func NewParser(tokens []lexer.Token) *Parser {
-	return &Parser{tokens: tokens}
+	return &Parser{tokens: tokens, eofLine: 1, eofCol: 1}
 }
 
-func (p *Parser) parseSchema() (*Schema, error) {
+func (p *Parser) parseSchema() *Schema {
 	var schema Schema
 	for !p.atEnd() {
 		switch p.peek().Type {
 		case lexer.TokenModel:
-			model, err := p.parseModel()
-			if err != nil {
-				return nil, err
+			model, ok := p.parseModel()
+			if !ok {
+				continue
 			}
 			schema.Models = append(schema.Models, model)
 			schema.Decls = append(schema.Decls, Decl{
@@ -179,9 +200,9 @@ func (p *Parser) parseSchema() (*Schema, error) {
 				Model: &schema.Models[len(schema.Models)-1],
 			})
 		case lexer.TokenRpc:
-			rpc, err := p.parseRPC()
-			if err != nil {
-				return nil, err
+			rpc, ok := p.parseRPC()
+			if !ok {
+				continue
 			}
 			schema.RPCs = append(schema.RPCs, rpc)
 			schema.Decls = append(schema.Decls, Decl{
@@ -189,76 +210,137 @@ func (p *Parser) parseSchema() (*Schema, error) {
 				RPC:  &schema.RPCs[len(schema.RPCs)-1],
 			})
 		default:
-			return nil, p.unexpected("model or rpc")
+			p.report(p.unexpected("model or rpc"))
+			p.pos++
+			p.skipTo()
 		}
 	}
-	return &schema, nil
+	return &schema
 }
 
-func (p *Parser) parseModel() (Model, error) {
-	modelToken, err := p.expect(lexer.TokenModel)
-	if err != nil {
-		return Model{}, err
-	}
+// parseModel returns false when not even the model name could be parsed.
+// Otherwise syntax errors are reported and the fields parsed so far are kept.
+func (p *Parser) parseModel() (Model, bool) {
+	modelToken := p.next()
 	name, err := p.expect(lexer.TokenIdentifier)
 	if err != nil {
-		return Model{}, err
+		p.report(err)
+		p.skipTo()
+		return Model{}, false
+	}
+	model := Model{
+		Name:     name.Value,
+		Line:     modelToken.Line,
+		Col:      modelToken.Col,
+		NameLine: name.Line,
+		NameCol:  name.Col,
+		EndLine:  name.Line,
+		EndCol:   name.Col,
 	}
 	if _, err := p.expect(lexer.TokenLBrace); err != nil {
-		return Model{}, err
+		p.report(err)
+		p.recoverModel(&model)
+		return model, true
 	}
 
-	var fields []Field
-	for !p.atEnd() && p.peek().Type != lexer.TokenRBrace {
+	for !p.atEnd() && p.peek().Type != lexer.TokenRBrace && !p.atDecl() {
 		if p.peek().Type != lexer.TokenIdentifier {
-			return Model{}, p.unexpected("field name or }")
+			p.report(p.unexpected("field name or }"))
+			p.recoverModel(&model)
+			return model, true
 		}
 		field, err := p.parseField()
 		if err != nil {
-			return Model{}, err
+			p.report(err)
+			p.recoverModel(&model)
+			return model, true
 		}
-		fields = append(fields, field)
+		model.Fields = append(model.Fields, field)
 	}
 	rbrace, err := p.expect(lexer.TokenRBrace)
 	if err != nil {
-		return Model{}, err
+		p.report(err)
+		return model, true
 	}
-	return Model{
-		Name:    name.Value,
-		Fields:  fields,
-		Line:    modelToken.Line,
-		Col:     modelToken.Col,
-		EndLine: rbrace.Line,
-		EndCol:  rbrace.Col,
-	}, nil
+	model.EndLine = rbrace.Line
+	model.EndCol = rbrace.Col
+	return model, true
 }
 
-func (p *Parser) parseRPC() (RPC, error) {
-	rpcToken, err := p.expect(lexer.TokenRpc)
-	if err != nil {
-		return RPC{}, err
+func (p *Parser) recoverModel(model *Model) {
+	if rbrace, ok := p.skipTo(lexer.TokenRBrace); ok {
+		model.EndLine = rbrace.Line
+		model.EndCol = rbrace.Col
 	}
+}
+
+// parseRPC returns false when not even the RPC name could be parsed.
+// Otherwise syntax errors are reported and the parts parsed so far are kept.
+func (p *Parser) parseRPC() (RPC, bool) {
+	rpcToken := p.next()
 	name, err := p.expect(lexer.TokenIdentifier)
 	if err != nil {
-		return RPC{}, err
+		p.report(err)
+		p.skipTo()
+		return RPC{}, false
+	}
+	rpc := RPC{
+		Name:     name.Value,
+		Line:     rpcToken.Line,
+		Col:      rpcToken.Col,
+		NameLine: name.Line,
+		NameCol:  name.Col,
 	}
 	if _, err := p.expect(lexer.TokenLParen); err != nil {
-		return RPC{}, err
+		p.report(err)
+		p.skipTo()
+		return rpc, true
+	}
+
+	rparen, err := p.parseParams(&rpc)
+	if err != nil {
+		p.report(err)
+		var ok bool
+		if rparen, ok = p.skipTo(lexer.TokenRParen); !ok {
+			return rpc, true
+		}
 	}
+	rpc.ParamsEndLine = rparen.Line
+	rpc.ParamsEndCol = rparen.Col
 
-	var params []Field
+	if p.atEnd() || p.atDecl() {
+		return rpc, true
+	}
+	if p.peek().Type != lexer.TokenIdentifier {
+		p.report(p.unexpected("return type or definition"))
+		p.skipTo()
+		return rpc, true
+	}
+	retType, err := p.parseType()
+	if err != nil {
+		p.report(err)
+		p.skipTo()
+		return rpc, true
+	}
+	rpc.Returns = retType
+	rpc.HasReturn = true
+	return rpc, true
+}
+
+// parseParams parses parameters up to and including the closing paren.
+func (p *Parser) parseParams(rpc *RPC) (lexer.Token, error) {
 	for !p.atEnd() && p.peek().Type != lexer.TokenRParen {
 		if p.peek().Type != lexer.TokenIdentifier {
-			return RPC{}, p.unexpected("parameter name or )")
+			return lexer.Token{}, p.unexpected("parameter name or )")
 		}
 		field, err := p.parseField()
 		if err != nil {
-			return RPC{}, err
+			return lexer.Token{}, err
 		}
-		params = append(params, field)
+		rpc.Parameters = append(rpc.Parameters, field)
 		if p.match(lexer.TokenComma) {
 			if p.atEnd() {
-				return RPC{}, p.unexpected("parameter name or )")
+				return lexer.Token{}, p.unexpected("parameter name or )")
 			}
 			if p.peek().Type == lexer.TokenRParen {
 				break
@@ -266,43 +348,10 @@ func (p *Parser) parseRPC() (RPC, error) {
 			continue
 		}
 		if p.atEnd() || p.peek().Type != lexer.TokenRParen {
-			return RPC{}, p.unexpected("comma or )")
+			return lexer.Token{}, p.unexpected("comma or )")
 		}
 	}
-	rparen, err := p.expect(lexer.TokenRParen)
-	if err != nil {
-		return RPC{}, err
-	}
-
-	if p.atEnd() || p.peek().Type == lexer.TokenModel || p.peek().Type == lexer.TokenRpc {
-		return RPC{
-			Name:          name.Value,
-			Parameters:    params,
-			HasReturn:     false,
-			Line:          rpcToken.Line,
-			Col:           rpcToken.Col,
-			ParamsEndLine: rparen.Line,
-			ParamsEndCol:  rparen.Col,
-		}, nil
-	}
-	if p.peek().Type != lexer.TokenIdentifier {
-		return RPC{}, p.unexpected("return type or definition")
-	}
-	retType, err := p.parseType()
-	if err != nil {
-		return RPC{}, err
-	}
-
-	return RPC{
-		Name:          name.Value,
-		Parameters:    params,
-		Returns:       retType,
-		HasReturn:     true,
-		Line:          rpcToken.Line,
-		Col:           rpcToken.Col,
-		ParamsEndLine: rparen.Line,
-		ParamsEndCol:  rparen.Col,
-	}, nil
+	return p.expect(lexer.TokenRParen)
 }
 
 func (p *Parser) parseField() (Field, error) {
@@ -385,31 +434,87 @@ func (p *Parser) match(tt lexer.TokenType) bool {
 }
 
 func (p *Parser) expect(tt lexer.TokenType) (lexer.Token, error) {
-	if p.atEnd() {
-		return lexer.Token{}, fmt.Errorf("unexpected end of input, expected %s", lexer.TokenTypeName(tt))
-	}
-	token := p.peek()
-	if token.Type != tt {
+	if p.atEnd() || p.peek().Type != tt {
 		return lexer.Token{}, p.unexpected(lexer.TokenTypeName(tt))
 	}
+	return p.next(), nil
+}
+
+func (p *Parser) next() lexer.Token {
+	token := p.tokens[p.pos]
 	p.pos++
-	return token, nil
+	return token
 }
 
 func (p *Parser) atEnd() bool {
 	return p.pos >= len(p.tokens)
 }
 
+// atDecl reports whether the next token starts a declaration.
+func (p *Parser) atDecl() bool {
+	if p.atEnd() {
+		return false
+	}
+	tt := p.peek().Type
+	return tt == lexer.TokenModel || tt == lexer.TokenRpc
+}
+
+// skipTo advances past the first token of type closer, stopping early
+// before a model or rpc keyword or at the end of input. It reports
+// whether the closer was consumed.
+func (p *Parser) skipTo(closer ...lexer.TokenType) (lexer.Token, bool) {
+	for !p.atEnd() && !p.atDecl() {
+		token := p.next()
+		for _, tt := range closer {
+			if token.Type == tt {
+				return token, true
+			}
+		}
+	}
+	return lexer.Token{}, false
+}
+
 func (p *Parser) peek() lexer.Token {
 	return p.tokens[p.pos]
 }
 
+func (p *Parser) report(err error) {
+	var diagnostic Diagnostic
+	if errors.As(err, &diagnostic) {
+		p.diagnostics = append(p.diagnostics, diagnostic)
+		return
+	}
+	p.diagnostics = append(p.diagnostics, p.diagnosticAt(p.pos, CodeSyntax, err.Error()))
+}
+
 func (p *Parser) unexpected(expected string) error {
 	if p.atEnd() {
-		return fmt.Errorf("unexpected end of input, expected %s", expected)
+		return p.diagnosticAt(p.pos, CodeSyntax, fmt.Sprintf("unexpected end of input, expected %s", expected))
 	}
-	token := p.peek()
-	return fmt.Errorf("unexpected token %q at line %d, column %d, expected %s", token.Value, token.Line, token.Col, expected)
+	return p.diagnosticAt(p.pos, CodeSyntax, fmt.Sprintf("unexpected token %q, expected %s", p.peek().Value, expected))
+}
+
+// diagnosticAt spans the token at index i, or the end of input.
+func (p *Parser) diagnosticAt(i int, code, message string) Diagnostic {
+	if i >= len(p.tokens) {
+		return newDiagnostic(code, p.eofLine, p.eofCol, 0, message)
+	}
+	token := p.tokens[i]
+	return newDiagnostic(code, token.Line, token.Col, utf8.RuneCountInString(token.Value), message)
+}
+
+// endPosition returns the 1-based line and column just after text.
+func endPosition(text string) (int, int) {
+	line, col := 1, 1
+	for _, r := range text {
+		if r == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return line, col
 }
 
 func formatType(t TypeRef) string {
@@ -468,27 +573,44 @@ func ValidateType(t TypeRef) error {
 	return nil
 }
 
+// ValidateSchema checks declarations for duplicate names and unknown types.
+// All problems are returned together as Diagnostics.
 func ValidateSchema(schema *Schema) error {
 	if schema == nil {
 		return fmt.Errorf("schema is nil")
 	}
+	diagnostics := validateSchema(schema)
+	if len(diagnostics) > 0 {
+		SortDiagnostics(diagnostics)
+		return Diagnostics(diagnostics)
+	}
+	return nil
+}
+
+func validateSchema(schema *Schema) []Diagnostic {
+	var diagnostics []Diagnostic
+	report := func(code string, line, col, length int, format string, args ...any) {
+		diagnostics = append(diagnostics, newDiagnostic(code, line, col, length, fmt.Sprintf(format, args...)))
+	}
 	models := make(map[string]struct{}, len(schema.Models))
 	for _, model := range schema.Models {
 		if model.Name == "" {
-			return fmt.Errorf("model name is empty")
+			report(CodeEmptyName, model.Line, model.Col, len("model"), "model name is empty")
+			continue
 		}
 		if _, exists := models[model.Name]; exists {
-			return fmt.Errorf("duplicate model %q", model.Name)
+			report(CodeDuplicateModel, model.NameLine, model.NameCol, utf8.RuneCountInString(model.Name), "duplicate model %q", model.Name)
 		}
 		models[model.Name] = struct{}{}
 	}
 	rpcs := make(map[string]struct{}, len(schema.RPCs))
 	for _, rpc := range schema.RPCs {
 		if rpc.Name == "" {
-			return fmt.Errorf("rpc name is empty")
+			report(CodeEmptyName, rpc.Line, rpc.Col, len("rpc"), "rpc name is empty")
+			continue
 		}
 		if _, exists := rpcs[rpc.Name]; exists {
-			return fmt.Errorf("duplicate rpc %q", rpc.Name)
+			report(CodeDuplicateRPC, rpc.NameLine, rpc.NameCol, utf8.RuneCountInString(rpc.Name), "duplicate rpc %q", rpc.Name)
 		}
 		rpcs[rpc.Name] = struct{}{}
 	}
@@ -496,66 +618,69 @@ func ValidateSchema(schema *Schema) error {
 		fields := make(map[string]struct{}, len(model.Fields))
 		for _, field := range model.Fields {
 			if field.Name == "" {
-				return fmt.Errorf("model %q has empty field name", model.Name)
+				report(CodeEmptyName, field.Line, field.Col, 0, "model %q has empty field name", model.Name)
+				continue
 			}
 			if _, exists := fields[field.Name]; exists {
-				return fmt.Errorf("model %q has duplicate field %q", model.Name, field.Name)
+				report(CodeDuplicateField, field.Line, field.Col, utf8.RuneCountInString(field.Name), "model %q has duplicate field %q", model.Name, field.Name)
 			}
 			fields[field.Name] = struct{}{}
-			if err := validateTypeRef(field.Type, models); err != nil {
-				return fmt.Errorf("model %q field %q: %w", model.Name, field.Name, err)
-			}
+			diagnostics = append(diagnostics, validateTypeRef(field.Type, models, fmt.Sprintf("model %q field %q", model.Name, field.Name))...)
 		}
 	}
 	for _, rpc := range schema.RPCs {
 		params := make(map[string]struct{}, len(rpc.Parameters))
 		for _, param := range rpc.Parameters {
 			if param.Name == "" {
-				return fmt.Errorf("rpc %q has empty parameter name", rpc.Name)
+				report(CodeEmptyName, param.Line, param.Col, 0, "rpc %q has empty parameter name", rpc.Name)
+				continue
 			}
 			if _, exists := params[param.Name]; exists {
-				return fmt.Errorf("rpc %q has duplicate parameter %q", rpc.Name, param.Name)
+				report(CodeDuplicateParameter, param.Line, param.Col, utf8.RuneCountInString(param.Name), "rpc %q has duplicate parameter %q", rpc.Name, param.Name)
 			}
 			params[param.Name] = struct{}{}
-			if err := validateTypeRef(param.Type, models); err != nil {
-				return fmt.Errorf("rpc %q parameter %q: %w", rpc.Name, param.Name, err)
-			}
+			diagnostics = append(diagnostics, validateTypeRef(param.Type, models, fmt.Sprintf("rpc %q parameter %q", rpc.Name, param.Name))...)
 		}
 		if rpc.HasReturn {
-			if err := validateTypeRef(rpc.Returns, models); err != nil {
-				return fmt.Errorf("rpc %q returns: %w", rpc.Name, err)
-			}
+			diagnostics = append(diagnostics, validateTypeRef(rpc.Returns, models, fmt.Sprintf("rpc %q returns", rpc.Name))...)
 		}
 	}
-	return nil
+	return diagnostics
 }
 
-func validateTypeRef(t TypeRef, models map[string]struct{}) error {
-	if err := ValidateType(t); err != nil {
-		return err
+func validateTypeRef(t TypeRef, models map[string]struct{}, context string) []Diagnostic {
+	report := func(code string, length int, format string, args ...any) []Diagnostic {
+		return []Diagnostic{newDiagnostic(code, t.Line, t.Col, length, context+": "+fmt.Sprintf(format, args...))}
 	}
 	switch t.Kind {
 	case TypeList:
 		if t.Elem == nil {
-			return fmt.Errorf("list type missing element")
+			return report(CodeInvalidType, len("list"), "list type missing element")
 		}
-		return validateTypeRef(*t.Elem, models)
+		return validateTypeRef(*t.Elem, models, context)
 	case TypeMap:
 		if t.Value == nil {
-			return fmt.Errorf("map type missing value")
+			return report(CodeInvalidType, len("map"), "map type missing value")
 		}
-		return validateTypeRef(*t.Value, models)
+		return validateTypeRef(*t.Value, models, context)
 	case TypeIdent:
+		if t.Name == "" {
+			return report(CodeInvalidType, 0, "identifier type is empty")
+		}
 		if isBuiltinType(t.Name) {
 			return nil
 		}
 		if _, ok := models[t.Name]; !ok {
-			return fmt.Errorf("unknown type %q", t.Name)
+			return report(CodeUnknownType, utf8.RuneCountInString(t.Name), "unknown type %q", t.Name)
 		}
 	}
 	return nil
 }
 
+func IsBuiltinType(name string) bool {
+	return isBuiltinType(name)
+}
+
 func isBuiltinType(name string) bool {
 	switch name {
 	case "string", "int", "bool", "json", "raw":
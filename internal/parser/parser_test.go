@@ -1,6 +1,7 @@
 package parser_test
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -200,3 +201,91 @@ rpc GetUser() User
 		})
 	}
 }
+
+func TestParseRecoversAndReportsAllErrors(t *testing.T) {
+	input := `model Text {
+    title string
+    body: string
+}
+
+model Page {
+    texts: list[Text]
+    owner: User
+}
+
+rpc GetPage(
+    page_id: int
+    lang: string,
+) Page
+
+model Page {
+    id: int
+}
+
+rpc Broken Page
+`
+	schema, diagnostics := parser.ParseWithDiagnostics(input)
+	want := []string{
+		`2:11: unexpected token "string", expected :`,
+		`8:12: model "Page" field "owner": unknown type "User"`,
+		`13:5: unexpected token "lang", expected comma or )`,
+		`16:7: duplicate model "Page"`,
+		`20:12: unexpected token "Page", expected (`,
+	}
+	var got []string
+	for _, diagnostic := range diagnostics {
+		got = append(got, diagnostic.Error())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected diagnostics:\n%s", strings.Join(got, "\n"))
+	}
+	if len(schema.Models) != 3 || len(schema.RPCs) != 2 {
+		t.Fatalf("expected partial schema with 3 models and 2 rpcs, got %d and %d", len(schema.Models), len(schema.RPCs))
+	}
+	if !schema.RPCs[0].HasReturn || schema.RPCs[0].Returns.Name != "Page" {
+		t.Fatalf("expected return type to be parsed after recovering from parameter error")
+	}
+
+	_, err := parser.Parse(input)
+	var all parser.Diagnostics
+	if !errors.As(err, &all) || len(all) != len(want) {
+		t.Fatalf("expected Parse to return all diagnostics, got %v", err)
+	}
+}
+
+func TestParseReportsLexerErrorsWithSyntaxErrors(t *testing.T) {
+	_, diagnostics := parser.ParseWithDiagnostics("model A {\n    x: int$$\n}\nmodel {\n")
+	var got []string
+	for _, diagnostic := range diagnostics {
+		got = append(got, diagnostic.Error())
+	}
+	want := `2:11: unexpected character "$"` + "\n" + `4:7: unexpected token "{", expected identifier`
+	if strings.Join(got, "\n") != want {
+		t.Fatalf("unexpected diagnostics:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestWriteDiagnosticsText(t *testing.T) {
+	input := "model Text {\n\ttitle string\n}\n"
+	_, diagnostics := parser.ParseWithDiagnostics(input)
+	diagnostics = parser.Diagnostics(diagnostics).WithFile("api.rrpc")
+	var b strings.Builder
+	if err := parser.WriteDiagnosticsText(&b, diagnostics, map[string]string{"api.rrpc": input}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	want := `error[syntax_error]: unexpected token "string", expected :
+ --> api.rrpc:2:8
+  |
+2 | 	title string
+  | 	      ^^^^^^
+`
+	if b.String() != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", b.String(), want)
+	}
+	if diagnostics[0].Code != parser.CodeSyntax || diagnostics[0].Severity != parser.SeverityError {
+		t.Fatalf("unexpected code or severity: %+v", diagnostics[0])
+	}
+	if diagnostics[0].Range.End != (parser.Position{Line: 2, Col: 14}) {
+		t.Fatalf("unexpected range end: %+v", diagnostics[0].Range.End)
+	}
+}
@@ -0,0 +1,95 @@
+// Package plugin runs external generators. A plugin named foo is an
+// executable called rrpc-gen-foo on PATH that reads a Request as JSON on
+// stdin and writes a Response as JSON on stdout.
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/ir"
+)
+
+// ProtocolVersion is bumped on every incompatible change to Request or
+// Response. The schema carries its own version, see ir.Version.
+const ProtocolVersion = 1
+
+// ExecutablePrefix is prepended to a plugin name to get its executable name.
+const ExecutablePrefix = "rrpc-gen-"
+
+type Request struct {
+	ProtocolVersion int `json:"protocol_version"`
+	// Kind is the kind of the target: client or server.
+	Kind    string `json:"kind"`
+	Package string `json:"package"`
+	// Options are the plugin options of the target, passed through as is.
+	Options map[string]string `json:"options"`
+	Schema  *ir.Schema        `json:"schema"`
+}
+
+// Response holds the generated files keyed by slash-separated paths
+// relative to the package directory. A non-empty Error fails generation.
+type Response struct {
+	Files map[string]string `json:"files"`
+	Error string            `json:"error,omitempty"`
+}
+
+// Run executes the plugin called name with req and returns the files it
+// generated.
+func Run(name string, req Request) (map[string]string, error) {
+	if name == "" {
+		return nil, fmt.Errorf("plugin name is empty")
+	}
+	executable, err := exec.LookPath(ExecutablePrefix + name)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", name, err)
+	}
+	req.ProtocolVersion = ProtocolVersion
+	if req.Options == nil {
+		req.Options = map[string]string{}
+	}
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: encode request: %w", name, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(executable)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("plugin %s: %w: %s", name, err, msg)
+		}
+		return nil, fmt.Errorf("plugin %s: %w", name, err)
+	}
+
+	decoder := json.NewDecoder(&stdout)
+	decoder.DisallowUnknownFields()
+	var resp Response
+	if err := decoder.Decode(&resp); err != nil {
+		return nil, fmt.Errorf("plugin %s: decode response: %w", name, err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("plugin %s: %s", name, resp.Error)
+	}
+	for file := range resp.Files {
+		if err := CheckPath(file); err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", name, err)
+		}
+	}
+	return resp.Files, nil
+}
+
+// CheckPath rejects file names that would escape the package directory.
+func CheckPath(name string) error {
+	if name == "" || path.IsAbs(name) || strings.Contains(name, "\\") || path.Clean(name) != name || name == ".." || strings.HasPrefix(name, "../") {
+		return fmt.Errorf("invalid file name %q (expected a clean relative path)", name)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package plugin_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Rapid-Vision/rRPC/internal/ir"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/plugin"
+)
+
+// TestMain lets the test binary act as a plugin when it is started through
+// an rrpc-gen-* link, so the tests do not depend on a shell or compiler.
+func TestMain(m *testing.M) {
+	name := filepath.Base(os.Args[0])
+	if !strings.HasPrefix(name, plugin.ExecutablePrefix) {
+		os.Exit(m.Run())
+	}
+	var req plugin.Request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	switch strings.TrimPrefix(name, plugin.ExecutablePrefix) {
+	case "echo":
+		data, _ := json.Marshal(req)
+		json.NewEncoder(os.Stdout).Encode(plugin.Response{Files: map[string]string{"request.json": string(data)}})
+	case "fail":
+		json.NewEncoder(os.Stdout).Encode(plugin.Response{Error: "unsupported option " + req.Options["mode"]})
+	case "crash":
+		fmt.Fprintln(os.Stderr, "boom")
+		os.Exit(3)
+	case "escape":
+		json.NewEncoder(os.Stdout).Encode(plugin.Response{Files: map[string]string{"../outside.ex": ""}})
+	}
+	os.Exit(0)
+}
+
+func installPlugins(t *testing.T, names ...string) {
+	t.Helper()
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatalf("executable: %v", err)
+	}
+	dir := t.TempDir()
+	for _, name := range names {
+		if err := os.Symlink(self, filepath.Join(dir, plugin.ExecutablePrefix+name)); err != nil {
+			t.Skipf("symlink: %v", err)
+		}
+	}
+	t.Setenv("PATH", dir)
+}
+
+func TestRun(t *testing.T) {
+	installPlugins(t, "echo")
+	schema, err := parser.Parse(`model TextInfo {
+    author_id: int?
+    tags: list[string]
+}
+
+rpc GetTextInfo(textId: int) TextInfo
+
+rpc Ping()
+`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	files, err := plugin.Run("echo", plugin.Request{
+		Kind:    "client",
+		Package: "rpcclient",
+		Options: map[string]string{"mode": "fast"},
+		Schema:  ir.FromSchema(schema, "api"),
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	var req plugin.Request
+	if err := json.Unmarshal([]byte(files["request.json"]), &req); err != nil {
+		t.Fatalf("decode echoed request: %v", err)
+	}
+	if req.ProtocolVersion != plugin.ProtocolVersion || req.Schema.Version != ir.Version || req.Options["mode"] != "fast" {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	field := req.Schema.Models[0].Fields[0]
+	if field.WireName != "author_id" || field.CodeName != "AuthorId" || field.Type.Kind != ir.KindBuiltin || !field.Type.Optional {
+		t.Fatalf("unexpected field: %+v", field)
+	}
+	if elem := req.Schema.Models[0].Fields[1].Type.Elem; elem == nil || elem.Name != "string" {
+		t.Fatalf("unexpected list element: %+v", req.Schema.Models[0].Fields[1].Type)
+	}
+	rpc := req.Schema.RPCs[0]
+	if rpc.Path != "/api/get_text_info" || rpc.Params[0].WireName != "text_id" || rpc.Returns.Kind != ir.KindModel || rpc.ResultKey != "text_info" {
+		t.Fatalf("unexpected rpc: %+v", rpc)
+	}
+	if req.Schema.RPCs[1].Returns != nil {
+		t.Fatalf("expected no return type for Ping")
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	installPlugins(t, "fail", "crash", "escape")
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"missing", "rrpc-gen-missing"},
+		{"fail", "plugin fail: unsupported option fast"},
+		{"crash", "exit status 3: boom"},
+		{"escape", `invalid file name "../outside.ex"`},
+	}
+	for _, tt := range tests {
+		_, err := plugin.Run(tt.name, plugin.Request{Options: map[string]string{"mode": "fast"}, Schema: &ir.Schema{}})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Fatalf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Drift is a generated file whose contents on disk do not match a fresh
+// generation.
+type Drift struct {
+	// Path is the file path on disk.
+	Path string
+	// Missing is set when the file does not exist.
+	Missing bool
+	// Stale is set when the file was generated before but is no longer
+	// produced, so regeneration would remove it.
+	Stale bool
+	// Diff is a unified diff from the file on disk to the generated file.
+	Diff string
+}
+
+// Compare checks the files produced by generator, keyed as returned by
+// Generate, against their counterparts below dir without writing anything.
+// Files that the manifest of dir records for generator but that are no
+// longer generated are reported as stale. Drifts are sorted by path.
+func Compare(dir, generator string, files map[string]string) ([]Drift, error) {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := sortedNames(files)
+	if manifest != nil {
+		names = append(names, manifest.stale(generator, files)...)
+		sort.Strings(names)
+	}
+	var drifts []Drift
+	for _, name := range names {
+		contents, generated := files[name]
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		data, err := os.ReadFile(path)
+		missing := errors.Is(err, fs.ErrNotExist)
+		if err != nil && !missing {
+			return nil, fmt.Errorf("read generated file: %w", err)
+		}
+		if !generated && missing || generated && !missing && string(data) == contents {
+			continue
+		}
+		fromFile, before := path, difflib.SplitLines(string(data))
+		if missing {
+			fromFile, before = "/dev/null", nil
+		}
+		toFile, after := path, difflib.SplitLines(contents)
+		if !generated {
+			toFile, after = "/dev/null", nil
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        before,
+			B:        after,
+			FromFile: fromFile,
+			ToFile:   toFile,
+			Context:  3,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("diff %s: %w", path, err)
+		}
+		drifts = append(drifts, Drift{Path: path, Missing: missing, Stale: !generated, Diff: diff})
+	}
+	return drifts, nil
+}
@@ -0,0 +1,256 @@
+package project
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the project config file looked up by default.
+const FileName = "rrpc.yaml"
+
+const (
+	KindClient  = "client"
+	KindServer  = "server"
+	KindOpenAPI = "openapi"
+)
+
+// Config describes a schema and the code generated from it.
+type Config struct {
+	// Schema is the path of the schema file, relative to Dir.
+	Schema string `yaml:"schema"`
+	// Prefix is the URL path prefix shared by all targets. A nil Prefix
+	// means the default "rpc"; an empty one means no prefix.
+	Prefix  *string  `yaml:"prefix"`
+	Targets []Target `yaml:"targets"`
+
+	// Dir is the directory containing the config file. Relative paths in
+	// the config are resolved against it.
+	Dir string `yaml:"-"`
+}
+
+// Target is one generated artifact.
+type Target struct {
+	Name    string  `yaml:"name"`
+	Kind    string  `yaml:"kind"`
+	Lang    string  `yaml:"lang"`
+	Package string  `yaml:"package"`
+	Output  string  `yaml:"output"`
+	Options Options `yaml:"options"`
+	// Templates is a directory of template overrides for the built-in
+	// generators.
+	Templates string `yaml:"templates"`
+}
+
+// Options holds language- and kind-specific settings of a target.
+type Options struct {
+	TSZod      bool   `yaml:"ts-zod"`
+	PyPydantic bool   `yaml:"py-pydantic"`
+	Title      string `yaml:"title"`
+	Version    string `yaml:"version"`
+	// Plugin holds options passed to plugin generators as is.
+	Plugin map[string]string `yaml:"plugin"`
+}
+
+// Load reads the config file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.Dir = filepath.Dir(path)
+	for i := range cfg.Targets {
+		if cfg.Targets[i].Templates != "" {
+			cfg.Targets[i].Templates = cfg.resolve(cfg.Targets[i].Templates)
+		}
+	}
+	return cfg, nil
+}
+
+// Parse decodes and validates a config. Unknown keys are rejected so that
+// typos do not silently change the generated code.
+func Parse(data []byte) (*Config, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var cfg Config
+	if err := decoder.Decode(&cfg); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("parse config: empty file")
+		}
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// URLPrefix returns the configured prefix, defaulting to "rpc".
+func (c *Config) URLPrefix() string {
+	if c.Prefix == nil {
+		return "rpc"
+	}
+	return *c.Prefix
+}
+
+// SchemaPath returns the schema path resolved against Dir.
+func (c *Config) SchemaPath() string {
+	return c.resolve(c.Schema)
+}
+
+// TargetDir returns the directory the files of t are written to, resolved
+// against Dir.
+func (c *Config) TargetDir(t Target) string {
+	if t.Output == "" {
+		return t.Dir(c.resolve("."))
+	}
+	return t.Dir(c.resolve(t.Output))
+}
+
+func (c *Config) resolve(path string) string {
+	if filepath.IsAbs(path) || c.Dir == "" {
+		return path
+	}
+	return filepath.Join(c.Dir, path)
+}
+
+func (c *Config) validate() error {
+	if c.Schema == "" {
+		return fmt.Errorf("config: schema is required")
+	}
+	if len(c.Targets) == 0 {
+		return fmt.Errorf("config: at least one target is required")
+	}
+	destinations := make(map[string]int, len(c.Targets))
+	for i := range c.Targets {
+		target := &c.Targets[i]
+		if err := target.Validate(); err != nil {
+			return fmt.Errorf("config: target %d: %w", i+1, err)
+		}
+		destination := target.Dir(target.Output)
+		if target.Kind == KindOpenAPI {
+			destination = filepath.Join(destination, "openapi.json")
+		}
+		if prev, ok := destinations[destination]; ok {
+			return fmt.Errorf("config: targets %d and %d both write to %s", prev, i+1, destination)
+		}
+		destinations[destination] = i + 1
+	}
+	return nil
+}
+
+// Validate checks the target and normalizes language aliases such as py
+// and ts.
+func (t *Target) Validate() error {
+	t.Lang = normalizeLang(t.Lang)
+	plugin, isPlugin := t.PluginName()
+	switch t.Kind {
+	case KindClient:
+		if !isPlugin && t.Lang != "go" && t.Lang != "python" && t.Lang != "typescript" {
+			return fmt.Errorf("unsupported language %q for client", t.Lang)
+		}
+	case KindServer:
+		if !isPlugin && t.Lang != "go" && t.Lang != "python" {
+			return fmt.Errorf("unsupported language %q for server", t.Lang)
+		}
+	case KindOpenAPI:
+		if t.Lang != "" {
+			return fmt.Errorf("openapi targets do not take a language")
+		}
+		if t.Package != "" {
+			return fmt.Errorf("openapi targets do not take a package")
+		}
+	case "":
+		return fmt.Errorf("kind is required")
+	default:
+		return fmt.Errorf("unknown kind %q (expected client, server or openapi)", t.Kind)
+	}
+	if t.Options.TSZod && (t.Kind != KindClient || t.Lang != "typescript") {
+		return fmt.Errorf("option ts-zod only applies to typescript clients")
+	}
+	if t.Options.PyPydantic && (t.Kind != KindClient || t.Lang != "python") {
+		return fmt.Errorf("option py-pydantic only applies to python clients")
+	}
+	if (t.Options.Title != "" || t.Options.Version != "") && t.Kind != KindOpenAPI {
+		return fmt.Errorf("options title and version only apply to openapi targets")
+	}
+	if isPlugin && (plugin == "" || strings.ContainsAny(plugin, `/\`)) {
+		return fmt.Errorf("invalid plugin name %q", plugin)
+	}
+	if t.Templates != "" && isPlugin {
+		return fmt.Errorf("templates do not apply to plugin targets")
+	}
+	if len(t.Options.Plugin) > 0 && !isPlugin {
+		return fmt.Errorf("option plugin only applies to plugin targets")
+	}
+	return nil
+}
+
+// PluginName returns the plugin name of a target whose language is
+// plugin:<name>.
+func (t Target) PluginName() (string, bool) {
+	return strings.CutPrefix(t.Lang, "plugin:")
+}
+
+// String returns the target name, or a description built from its kind,
+// language and package when no name is set.
+func (t Target) String() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	if t.Kind == KindOpenAPI {
+		return t.Kind
+	}
+	return t.Kind + " " + t.Lang + " " + t.PackageName()
+}
+
+// Generator names what produces the files of t, such as "openapi" or
+// "client typescript". It owns the entries of those files in the manifest.
+func (t Target) Generator() string {
+	if t.Kind == KindOpenAPI {
+		return t.Kind
+	}
+	return t.Kind + " " + normalizeLang(t.Lang)
+}
+
+// PackageName returns the package or directory name of generated code,
+// defaulting to rpcclient for clients and rpcserver for servers.
+func (t Target) PackageName() string {
+	if t.Package != "" {
+		return t.Package
+	}
+	switch t.Kind {
+	case KindClient:
+		return "rpcclient"
+	case KindServer:
+		return "rpcserver"
+	}
+	return ""
+}
+
+// Dir returns the directory below outputDir that holds the files of t:
+// the package directory for clients and servers, outputDir itself for
+// OpenAPI specs.
+func (t Target) Dir(outputDir string) string {
+	return filepath.Join(outputDir, t.PackageName())
+}
+
+func normalizeLang(lang string) string {
+	switch lang {
+	case "py":
+		return "python"
+	case "ts":
+		return "typescript"
+	}
+	return lang
+}
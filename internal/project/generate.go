@@ -0,0 +1,90 @@
+package project
+
+import (
+	"fmt"
+
+	gogen "github.com/Rapid-Vision/rRPC/internal/gen/go"
+	"github.com/Rapid-Vision/rRPC/internal/gen/openapi"
+	"github.com/Rapid-Vision/rRPC/internal/gen/override"
+	pygen "github.com/Rapid-Vision/rRPC/internal/gen/python"
+	pyserver "github.com/Rapid-Vision/rRPC/internal/gen/pythonserver"
+	tsgen "github.com/Rapid-Vision/rRPC/internal/gen/typescript"
+	"github.com/Rapid-Vision/rRPC/internal/ir"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/plugin"
+)
+
+// Generate renders a target. The returned map is keyed by slash-separated
+// paths relative to the target directory (see Target.Dir).
+func Generate(schema *parser.Schema, prefix string, t Target) (map[string]string, error) {
+	var overrides override.Templates
+	if t.Templates != "" {
+		var err error
+		if overrides, err = override.Load(t.Templates); err != nil {
+			return nil, err
+		}
+	}
+	return GenerateWithTemplates(schema, prefix, t, overrides)
+}
+
+// GenerateWithTemplates is like Generate, with template overrides given
+// directly instead of loaded from t.Templates.
+func GenerateWithTemplates(schema *parser.Schema, prefix string, t Target, overrides override.Templates) (map[string]string, error) {
+	if name, ok := t.PluginName(); ok {
+		files, err := plugin.Run(name, plugin.Request{
+			Kind:    t.Kind,
+			Package: t.PackageName(),
+			Options: t.Options.Plugin,
+			Schema:  ir.FromSchema(schema, prefix),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := files[ManifestName]; ok {
+			return nil, fmt.Errorf("plugin %s: %s is reserved", name, ManifestName)
+		}
+		return files, nil
+	}
+	var files map[string]string
+	var err error
+	switch {
+	case t.Kind == KindOpenAPI:
+		title, version := t.Options.Title, t.Options.Version
+		if title == "" {
+			title = "rRPC API"
+		}
+		if version == "" {
+			version = "0.1.0"
+		}
+		spec, err := openapi.GenerateWithTemplates(schema, title, version, prefix, overrides)
+		if err != nil {
+			return nil, fmt.Errorf("generate openapi: %w", err)
+		}
+		return map[string]string{"openapi.json": spec}, nil
+	case t.Kind == KindServer && t.Lang == "go":
+		files, err = gogen.GenerateWithTemplates(schema, t.PackageName(), prefix, overrides)
+	case t.Kind == KindServer && t.Lang == "python":
+		files, err = pyserver.GenerateWithTemplates(schema, prefix, overrides)
+		if err == nil {
+			files["__init__.py"] = pyserver.GenerateInit(schema)
+		}
+	case t.Kind == KindClient && t.Lang == "go":
+		files, err = gogen.GenerateClientWithTemplates(schema, t.PackageName(), prefix, overrides)
+	case t.Kind == KindClient && t.Lang == "python":
+		files, err = pygen.GenerateClientWithTemplates(schema, prefix, t.Options.PyPydantic, overrides)
+		if err == nil {
+			files["__init__.py"] = pygen.GeneratePythonInit(schema)
+		}
+	case t.Kind == KindClient && t.Lang == "typescript":
+		files, err = tsgen.GenerateClientWithTemplates(schema, prefix, t.Options.TSZod, overrides)
+		if err == nil {
+			files["index.ts"] = tsgen.GenerateTypeScriptIndexWithZod(schema, t.Options.TSZod)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported %s language %q", t.Kind, t.Lang)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("generate code: %w", err)
+	}
+	return files, nil
+}
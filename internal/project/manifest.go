@@ -0,0 +1,223 @@
+package project
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Rapid-Vision/rRPC/internal/plugin"
+)
+
+// ManifestName is the name of the file that records what rrpc generated
+// into a target directory.
+const ManifestName = ".rrpc-manifest.json"
+
+const manifestVersion = 1
+
+// Manifest lists the files generated into a directory together with the
+// SHA-256 of their contents at generation time, keyed by the generator
+// that produced them. It lets regeneration tell stale and hand-edited files
+// apart from ones it can safely replace, and lets several generators share
+// a directory without removing each other's files.
+type Manifest struct {
+	Version    int                          `json:"version"`
+	Generators map[string]map[string]string `json:"generators"`
+}
+
+// ReadManifest reads the manifest of dir. A directory without a manifest
+// yields nil and no error.
+func ReadManifest(dir string) (*Manifest, error) {
+	path := filepath.Join(dir, ManifestName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("read manifest %s: %w", path, err)
+	}
+	if manifest.Version != manifestVersion {
+		return nil, fmt.Errorf("read manifest %s: unsupported version %d", path, manifest.Version)
+	}
+	for _, files := range manifest.Generators {
+		for name := range files {
+			if err := plugin.CheckPath(name); err != nil {
+				return nil, fmt.Errorf("read manifest %s: %w", path, err)
+			}
+		}
+	}
+	return &manifest, nil
+}
+
+// recorded returns the hash a file had when it was generated, by any
+// generator.
+func (m *Manifest) recorded(name string) (string, bool) {
+	for _, files := range m.Generators {
+		if sum, ok := files[name]; ok {
+			return sum, true
+		}
+	}
+	return "", false
+}
+
+// stale returns the files generator produced before but no longer does.
+func (m *Manifest) stale(generator string, files map[string]string) []string {
+	var names []string
+	for name := range m.Generators[generator] {
+		if _, ok := files[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WriteFiles writes the files produced by generator below dir and records
+// them in the manifest of dir. Files that generator produced before but no
+// longer does are removed; files of other generators are left alone.
+//
+// Unless force is set, nothing is written when a file would overwrite or
+// remove content rrpc did not produce: a file that was edited since it was
+// generated, or an existing file that no manifest accounts for.
+func WriteFiles(dir, generator string, files map[string]string, force bool) error {
+	for name := range files {
+		if err := plugin.CheckPath(name); err != nil {
+			return err
+		}
+	}
+	previous, err := ReadManifest(dir)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		previous = &Manifest{}
+	}
+	stale := previous.stale(generator, files)
+
+	if !force {
+		for _, name := range sortedNames(files) {
+			if err := checkOverwrite(dir, name, files[name], previous); err != nil {
+				return err
+			}
+		}
+		for _, name := range stale {
+			if err := checkOverwrite(dir, name, "", previous); err != nil {
+				return err
+			}
+		}
+	}
+
+	manifest := Manifest{Version: manifestVersion, Generators: map[string]map[string]string{}}
+	for owner, owned := range previous.Generators {
+		if owner == generator {
+			continue
+		}
+		kept := make(map[string]string, len(owned))
+		for name, sum := range owned {
+			// Files are owned by the generator that wrote them last.
+			if _, ok := files[name]; !ok {
+				kept[name] = sum
+			}
+		}
+		if len(kept) > 0 {
+			manifest.Generators[owner] = kept
+		}
+	}
+	written := make(map[string]string, len(files))
+	for _, name := range sortedNames(files) {
+		if err := writeAtomic(filepath.Join(dir, filepath.FromSlash(name)), []byte(files[name])); err != nil {
+			return err
+		}
+		written[name] = hash([]byte(files[name]))
+	}
+	if len(written) > 0 {
+		manifest.Generators[generator] = written
+	}
+	for _, name := range stale {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("remove stale file: %w", err)
+		}
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	return writeAtomic(filepath.Join(dir, ManifestName), append(data, '\n'))
+}
+
+// checkOverwrite reports an error when replacing the file name with
+// contents, or removing it when contents is empty, would lose changes that
+// were not generated.
+func checkOverwrite(dir, name, contents string, previous *Manifest) error {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read output: %w", err)
+	}
+	if recorded, ok := previous.recorded(name); ok {
+		if hash(data) != recorded {
+			return fmt.Errorf("%s was edited since it was generated (use --force to overwrite)", path)
+		}
+		return nil
+	}
+	if string(data) != contents {
+		return fmt.Errorf("output file exists: %s (use --force to overwrite)", path)
+	}
+	return nil
+}
+
+// writeAtomic writes data to a temporary file next to path and renames it
+// into place, so readers never observe a partially written file.
+func writeAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write output: %w", err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write output: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func sortedNames(files map[string]string) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
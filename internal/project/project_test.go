@@ -0,0 +1,280 @@
+package project_test
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/project"
+)
+
+const testConfig = `schema: api.rrpc
+targets:
+  - kind: server
+    lang: go
+    output: server
+  - kind: client
+    lang: ts
+    package: web
+    output: clients
+    options:
+      ts-zod: true
+  - kind: client
+    lang: py
+    output: clients
+  - kind: openapi
+    options:
+      title: Text API
+`
+
+func TestParse(t *testing.T) {
+	cfg, err := project.Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if cfg.URLPrefix() != "rpc" {
+		t.Fatalf("expected default prefix, got %q", cfg.URLPrefix())
+	}
+	var names []string
+	for _, target := range cfg.Targets {
+		names = append(names, target.String())
+	}
+	want := "server go rpcserver, client typescript web, client python rpcclient, openapi"
+	if got := strings.Join(names, ", "); got != want {
+		t.Fatalf("unexpected targets: %s", got)
+	}
+
+	cfg, err = project.Parse([]byte("schema: api.rrpc\nprefix: \"\"\ntargets:\n  - kind: server\n    lang: plugin:elixir\n    options:\n      plugin:\n        otp: \"26\"\n"))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if cfg.URLPrefix() != "" {
+		t.Fatalf("expected empty prefix, got %q", cfg.URLPrefix())
+	}
+	if name, ok := cfg.Targets[0].PluginName(); !ok || name != "elixir" || cfg.Targets[0].Options.Plugin["otp"] != "26" {
+		t.Fatalf("unexpected plugin target: %+v", cfg.Targets[0])
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{"empty", "", "empty file"},
+		{"no schema", "targets:\n  - kind: openapi\n", "schema is required"},
+		{"no targets", "schema: api.rrpc\n", "at least one target"},
+		{"unknown key", "schema: api.rrpc\ntargets:\n  - kind: openapi\n    outptu: docs\n", "field outptu not found"},
+		{"unknown kind", "schema: api.rrpc\ntargets:\n  - kind: docs\n", `unknown kind "docs"`},
+		{"server lang", "schema: api.rrpc\ntargets:\n  - kind: server\n    lang: ts\n", `unsupported language "typescript" for server`},
+		{"option", "schema: api.rrpc\ntargets:\n  - kind: client\n    lang: go\n    options:\n      ts-zod: true\n", "ts-zod only applies"},
+		{"plugin kind", "schema: api.rrpc\ntargets:\n  - kind: openapi\n    lang: plugin:elixir\n", "openapi targets do not take a language"},
+		{"plugin name", "schema: api.rrpc\ntargets:\n  - kind: client\n    lang: \"plugin:\"\n", `invalid plugin name ""`},
+		{"plugin templates", "schema: api.rrpc\ntargets:\n  - kind: client\n    lang: plugin:elixir\n    templates: tmpl\n", "templates do not apply to plugin targets"},
+		{"plugin option", "schema: api.rrpc\ntargets:\n  - kind: client\n    lang: go\n    options:\n      plugin:\n        otp: \"26\"\n", "option plugin only applies"},
+		{"overlap", "schema: api.rrpc\ntargets:\n  - kind: client\n    lang: go\n  - kind: client\n    lang: py\n", "targets 1 and 2 both write to rpcclient"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := project.Parse([]byte(tt.config))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	schema, err := parser.Parse("model Text {\n    title: string\n}\n\nrpc GetText() Text\n")
+	if err != nil {
+		t.Fatalf("parse schema: %v", err)
+	}
+	cfg, err := project.Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := map[string]string{
+		"server go rpcserver":     "models.go",
+		"client typescript web":   "index.ts",
+		"client python rpcclient": "__init__.py",
+		"openapi":                 "openapi.json",
+	}
+	for _, target := range cfg.Targets {
+		files, err := project.Generate(schema, cfg.URLPrefix(), target)
+		if err != nil {
+			t.Fatalf("%s: %v", target, err)
+		}
+		if _, ok := files[want[target.String()]]; !ok {
+			t.Fatalf("%s: missing %s in %v", target, want[target.String()], keys(files))
+		}
+	}
+	files, err := project.Generate(schema, "", cfg.Targets[3])
+	if err != nil {
+		t.Fatalf("generate openapi: %v", err)
+	}
+	if !strings.Contains(files["openapi.json"], `"title": "Text API"`) || !strings.Contains(files["openapi.json"], `"/get_text"`) {
+		t.Fatalf("unexpected openapi spec:\n%s", files["openapi.json"])
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{"pkg/a.txt": "a", "b.txt": "b"}
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := project.WriteFiles(dir, "gen", files, false); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "pkg", "a.txt"))
+	if err != nil || string(data) != "a" {
+		t.Fatalf("unexpected contents %q: %v", data, err)
+	}
+	manifest, err := project.ReadManifest(dir)
+	if err != nil || manifest == nil || len(manifest.Generators["gen"]) != 2 {
+		t.Fatalf("unexpected manifest %+v: %v", manifest, err)
+	}
+
+	// Unchanged generated files are replaced, files no longer generated are removed.
+	files = map[string]string{"pkg/a.txt": "a2"}
+	if err := project.WriteFiles(dir, "gen", files, false); err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected stale file to be removed, got %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "pkg", "a.txt"), []byte("edited"), 0o644); err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	err = project.WriteFiles(dir, "gen", map[string]string{"pkg/a.txt": "a3"}, false)
+	if err == nil || !strings.Contains(err.Error(), "was edited since it was generated") {
+		t.Fatalf("expected edited file error, got %v", err)
+	}
+	err = project.WriteFiles(dir, "gen", map[string]string{}, false)
+	if err == nil || !strings.Contains(err.Error(), "was edited since it was generated") {
+		t.Fatalf("expected edited stale file error, got %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "c.txt"), []byte("mine"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	err = project.WriteFiles(dir, "gen", map[string]string{"pkg/a.txt": "a3", "c.txt": "generated"}, true)
+	if err != nil {
+		t.Fatalf("write with force: %v", err)
+	}
+	err = project.WriteFiles(t.TempDir(), "gen", map[string]string{"c.txt": "generated"}, false)
+	if err != nil {
+		t.Fatalf("write to fresh dir: %v", err)
+	}
+
+	other := t.TempDir()
+	if err := os.WriteFile(filepath.Join(other, "c.txt"), []byte("mine"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	err = project.WriteFiles(other, "gen", map[string]string{"c.txt": "generated"}, false)
+	if err == nil || !strings.Contains(err.Error(), "use --force") {
+		t.Fatalf("expected existing file error, got %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			t.Fatalf("temporary file left behind: %s", entry.Name())
+		}
+	}
+}
+
+func TestWriteFilesSharedDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := project.WriteFiles(dir, "openapi", map[string]string{"openapi.json": "{}"}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := project.WriteFiles(dir, "jsonschema", map[string]string{"Text.schema.json": "{}"}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"openapi.json", "Text.schema.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("expected %s to be kept: %v", name, err)
+		}
+	}
+	drifts, err := project.Compare(dir, "openapi", map[string]string{"openapi.json": "{}"})
+	if err != nil || len(drifts) != 0 {
+		t.Fatalf("unexpected drifts %+v: %v", drifts, err)
+	}
+
+	// Only stale files of the same generator are removed.
+	if err := project.WriteFiles(dir, "jsonschema", map[string]string{}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Text.schema.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected stale file to be removed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "openapi.json")); err != nil {
+		t.Fatalf("expected openapi.json to be kept: %v", err)
+	}
+}
+
+func TestReadManifestRejectsEscapingPaths(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	outside := filepath.Join(filepath.Dir(dir), "keep.txt")
+	if err := os.WriteFile(outside, []byte("keep"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	manifest := `{"version": 1, "generators": {"gen": {"../keep.txt": "sha256:0"}}}`
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, project.ManifestName), []byte(manifest), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := project.WriteFiles(dir, "gen", map[string]string{}, true)
+	if err == nil || !strings.Contains(err.Error(), "expected a clean relative path") {
+		t.Fatalf("expected invalid path error, got %v", err)
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Fatalf("expected file outside dir to be kept: %v", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	dir := t.TempDir()
+	if err := project.WriteFiles(dir, "gen", map[string]string{"pkg/a.txt": "one\ntwo\n", "pkg/b.txt": "same\n", "old.txt": "old\n"}, false); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	drifts, err := project.Compare(dir, "gen", map[string]string{
+		"pkg/a.txt": "one\nthree\n",
+		"pkg/b.txt": "same\n",
+		"pkg/c.txt": "new\n",
+	})
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	if len(drifts) != 3 {
+		t.Fatalf("expected 3 drifts, got %+v", drifts)
+	}
+	old, a, c := drifts[0], drifts[1], drifts[2]
+	if !old.Stale || !strings.Contains(old.Diff, "+++ /dev/null") || !strings.Contains(old.Diff, "-old\n") {
+		t.Fatalf("unexpected drift: %+v", old)
+	}
+	if a.Missing || a.Path != filepath.Join(dir, "pkg", "a.txt") || !strings.Contains(a.Diff, "-two\n+three\n") {
+		t.Fatalf("unexpected drift: %+v", a)
+	}
+	if !c.Missing || !strings.Contains(c.Diff, "--- /dev/null") || !strings.Contains(c.Diff, "+new\n") {
+		t.Fatalf("unexpected drift: %+v", c)
+	}
+}
+
+func keys(files map[string]string) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,284 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/utils"
+)
+
+const (
+	ErrorTypeCustom         = "custom"
+	ErrorTypeValidation     = "validation"
+	ErrorTypeInput          = "input"
+	ErrorTypeUnauthorized   = "unauthorized"
+	ErrorTypeForbidden      = "forbidden"
+	ErrorTypeNotImplemented = "not_implemented"
+)
+
+var ErrorStatus = map[string]int{
+	ErrorTypeCustom:         500,
+	ErrorTypeValidation:     400,
+	ErrorTypeInput:          400,
+	ErrorTypeUnauthorized:   401,
+	ErrorTypeForbidden:      403,
+	ErrorTypeNotImplemented: 501,
+}
+
+type Error struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+func RPCPath(prefix, name string) string {
+	p := strings.Trim(prefix, "/")
+	route := utils.NewIdentifierName(name).SnakeCase()
+	if p == "" {
+		return "/" + route
+	}
+	return "/" + p + "/" + route
+}
+
+func JSONName(name string) string {
+	return utils.NewIdentifierName(name).SnakeCase()
+}
+
+func ResultKey(t parser.TypeRef) string {
+	if t.Kind == parser.TypeIdent {
+		return utils.NewIdentifierName(t.Name).SnakeCase()
+	}
+	return "result"
+}
+
+func ModelIndex(schema *parser.Schema) map[string]parser.Model {
+	models := make(map[string]parser.Model, len(schema.Models))
+	for _, model := range schema.Models {
+		models[model.Name] = model
+	}
+	return models
+}
+
+// Decode parses a JSON document keeping numbers as json.Number so that
+// integers can be told apart from floats during validation.
+func Decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return value, nil
+}
+
+// ValidateParams checks a decoded request body against the RPC parameters.
+// Unknown keys are rejected and missing optional parameters are allowed.
+func ValidateParams(value any, params []parser.Field, models map[string]parser.Model) error {
+	if value == nil {
+		value = map[string]any{}
+	}
+	return validateFields(value, params, models, "")
+}
+
+// ValidateResult checks a decoded success body against the RPC return type.
+func ValidateResult(value any, rpc parser.RPC, models map[string]parser.Model) error {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("expected JSON object, got %s", describe(value))
+	}
+	if !rpc.HasReturn {
+		if len(obj) != 0 {
+			return fmt.Errorf("expected empty object, got keys %s", strings.Join(sortedKeys(obj), ", "))
+		}
+		return nil
+	}
+	key := ResultKey(rpc.Returns)
+	if len(obj) != 1 {
+		return fmt.Errorf("expected single key %q, got keys %s", key, strings.Join(sortedKeys(obj), ", "))
+	}
+	inner, ok := obj[key]
+	if !ok {
+		return fmt.Errorf("expected key %q, got keys %s", key, strings.Join(sortedKeys(obj), ", "))
+	}
+	return ValidateValue(inner, rpc.Returns, models, key)
+}
+
+// ValidateError checks a decoded error body against the error envelope and
+// the status code mapping.
+func ValidateError(value any, status int) (Error, error) {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return Error{}, fmt.Errorf("expected error object, got %s", describe(value))
+	}
+	if len(obj) != 2 {
+		return Error{}, fmt.Errorf("expected keys type, message, got %s", strings.Join(sortedKeys(obj), ", "))
+	}
+	errType, ok := obj["type"].(string)
+	if !ok {
+		return Error{}, fmt.Errorf("error type must be a string")
+	}
+	message, ok := obj["message"].(string)
+	if !ok {
+		return Error{}, fmt.Errorf("error message must be a string")
+	}
+	want, known := ErrorStatus[errType]
+	if !known {
+		return Error{}, fmt.Errorf("unknown error type %q", errType)
+	}
+	if status != want {
+		return Error{}, fmt.Errorf("error type %q must use status %d, got %d", errType, want, status)
+	}
+	return Error{Type: errType, Message: message}, nil
+}
+
+// ValidateValue checks a decoded JSON value against a schema type. path is
+// used as the prefix of error messages.
+func ValidateValue(value any, t parser.TypeRef, models map[string]parser.Model, path string) error {
+	if value == nil {
+		if t.Optional {
+			return nil
+		}
+		return fmt.Errorf("%s: expected %s, got null", path, parser.FormatType(t))
+	}
+	switch t.Kind {
+	case parser.TypeList:
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected list, got %s", path, describe(value))
+		}
+		if t.Elem == nil {
+			return nil
+		}
+		for i, item := range items {
+			if err := ValidateValue(item, *t.Elem, models, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case parser.TypeMap:
+		entries, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected map, got %s", path, describe(value))
+		}
+		if t.Value == nil {
+			return nil
+		}
+		for _, key := range sortedKeys(entries) {
+			if err := ValidateValue(entries[key], *t.Value, models, fmt.Sprintf("%s[%q]", path, key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	switch t.Name {
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: expected string, got %s", path, describe(value))
+		}
+	case "int":
+		if !isInteger(value) {
+			return fmt.Errorf("%s: expected int, got %s", path, describe(value))
+		}
+	case "bool":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected bool, got %s", path, describe(value))
+		}
+	case "json", "raw":
+	default:
+		model, ok := models[t.Name]
+		if !ok {
+			return fmt.Errorf("%s: unknown model %q", path, t.Name)
+		}
+		return validateFields(value, model.Fields, models, path)
+	}
+	return nil
+}
+
+func validateFields(value any, fields []parser.Field, models map[string]parser.Model, path string) error {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s: expected object, got %s", displayPath(path), describe(value))
+	}
+	known := make(map[string]parser.Field, len(fields))
+	for _, field := range fields {
+		known[JSONName(field.Name)] = field
+	}
+	for _, key := range sortedKeys(obj) {
+		if _, ok := known[key]; !ok {
+			return fmt.Errorf("%s: unknown field %q", displayPath(path), key)
+		}
+	}
+	for _, field := range fields {
+		key := JSONName(field.Name)
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+		fieldValue, ok := obj[key]
+		if !ok {
+			if field.Type.Optional {
+				continue
+			}
+			return fmt.Errorf("%s: missing required field", fieldPath)
+		}
+		if err := ValidateValue(fieldValue, field.Type, models, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isInteger(value any) bool {
+	switch v := value.(type) {
+	case json.Number:
+		_, err := strconv.ParseInt(v.String(), 10, 64)
+		return err == nil
+	case float64:
+		return v == float64(int64(v))
+	case int, int64:
+		return true
+	default:
+		return false
+	}
+}
+
+func describe(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case json.Number, float64, int, int64:
+		return "number"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "body"
+	}
+	return path
+}
+
+func sortedKeys(obj map[string]any) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,193 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/protocol"
+)
+
+func decode(t *testing.T, data string) any {
+	t.Helper()
+	value, err := protocol.Decode([]byte(data))
+	if err != nil {
+		t.Fatalf("decode %s: %v", data, err)
+	}
+	return value
+}
+
+func TestRPCPath(t *testing.T) {
+	tests := []struct {
+		prefix string
+		name   string
+		want   string
+	}{
+		{"rpc", "GetUser", "/rpc/get_user"},
+		{"/api/v1/", "GetUser", "/api/v1/get_user"},
+		{"", "GetUser", "/get_user"},
+	}
+	for _, tt := range tests {
+		if got := protocol.RPCPath(tt.prefix, tt.name); got != tt.want {
+			t.Fatalf("RPCPath(%q, %q) = %q, want %q", tt.prefix, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	schema, err := parser.Parse(`rpc GetText(
+    text_id: int,
+    lang: string?,
+)
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	models := protocol.ModelIndex(schema)
+	params := schema.RPCs[0].Parameters
+	tests := []struct {
+		body    string
+		wantErr string
+	}{
+		{`{"text_id": 1, "lang": "en"}`, ""},
+		{`{"text_id": 1}`, ""},
+		{`{"text_id": 1, "lang": null}`, ""},
+		{`{}`, "text_id: missing required field"},
+		{`{"text_id": 1.5}`, "text_id: expected int, got number"},
+		{`{"text_id": "1"}`, "text_id: expected int, got string"},
+		{`{"text_id": 1, "extra": true}`, `body: unknown field "extra"`},
+		{`[]`, "body: expected object, got list"},
+	}
+	for _, tt := range tests {
+		err := protocol.ValidateParams(decode(t, tt.body), params, models)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Fatalf("ValidateParams(%s): unexpected error: %v", tt.body, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tt.wantErr {
+			t.Fatalf("ValidateParams(%s) = %v, want %q", tt.body, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateResult(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string?
+    body: string
+}
+
+rpc GetText() Text
+
+rpc ListTexts() list[Text]
+
+rpc Ping()
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	models := protocol.ModelIndex(schema)
+	tests := []struct {
+		rpc     int
+		body    string
+		wantErr string
+	}{
+		{0, `{"text": {"title": null, "body": "b"}}`, ""},
+		{0, `{"text": {"body": 1}}`, "text.body: expected string, got number"},
+		{0, `{"result": {"body": "b"}}`, `expected key "text", got keys result`},
+		{1, `{"result": [{"body": "b"}]}`, ""},
+		{1, `{"result": [null]}`, "result[0]: expected Text, got null"},
+		{2, `{}`, ""},
+		{2, `{"ok": true}`, "expected empty object, got keys ok"},
+	}
+	for _, tt := range tests {
+		err := protocol.ValidateResult(decode(t, tt.body), schema.RPCs[tt.rpc], models)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Fatalf("ValidateResult(%s): unexpected error: %v", tt.body, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tt.wantErr {
+			t.Fatalf("ValidateResult(%s) = %v, want %q", tt.body, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateError(t *testing.T) {
+	if _, err := protocol.ValidateError(decode(t, `{"type": "forbidden", "message": "no"}`), 403); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err := protocol.ValidateError(decode(t, `{"type": "forbidden", "message": "no"}`), 500)
+	if err == nil || !strings.Contains(err.Error(), "must use status 403") {
+		t.Fatalf("expected status mismatch, got %v", err)
+	}
+	if _, err := protocol.ValidateError(decode(t, `{"type": "teapot", "message": "no"}`), 418); err == nil {
+		t.Fatalf("expected unknown type error")
+	}
+}
+
+func TestSampleParamsAreValid(t *testing.T) {
+	schema, err := parser.Parse(`model Text {
+    title: string?
+    body: string
+    tags: list[string]
+    meta: map[int]
+}
+
+rpc CreateText(
+    text: Text,
+    parent: Text?,
+    count: int,
+    lang: string?,
+    data: json,
+)
+
+rpc Ping()
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	models := protocol.ModelIndex(schema)
+	for _, rpc := range schema.RPCs {
+		for _, withOptional := range []bool{true, false} {
+			raw, err := json.Marshal(protocol.SampleParams(rpc.Parameters, models, withOptional))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := protocol.ValidateParams(decode(t, string(raw)), rpc.Parameters, models); err != nil {
+				t.Fatalf("sample params for %s are invalid: %v", rpc.Name, err)
+			}
+		}
+	}
+}
+
+func TestSampleValueRecursiveModels(t *testing.T) {
+	schema, err := parser.Parse(`model Node {
+    next: Node
+}
+
+model Tree {
+    leaf: Leaf
+}
+
+model Leaf {
+    tree: Tree
+}
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	models := protocol.ModelIndex(schema)
+	for _, name := range []string{"Node", "Tree"} {
+		value := protocol.SampleValue(parser.TypeRef{Kind: parser.TypeIdent, Name: name}, models)
+		if _, err := json.Marshal(value); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := value.(map[string]any); !ok {
+			t.Fatalf("expected an object for %s, got %v", name, value)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package protocol
+
+import (
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+)
+
+const maxSampleDepth = 4
+
+// SampleParams builds a deterministic request body for an RPC. Optional
+// parameters are included only when withOptional is set.
+func SampleParams(params []parser.Field, models map[string]parser.Model, withOptional bool) map[string]any {
+	out := make(map[string]any, len(params))
+	for _, param := range params {
+		if param.Type.Optional && !withOptional {
+			continue
+		}
+		out[JSONName(param.Name)] = SampleValue(param.Type, models)
+	}
+	return out
+}
+
+// SampleValue builds a deterministic value of the given type. Recursive
+// models are cut off with null, empty lists, empty maps or, where a model
+// is required, an empty object.
+func SampleValue(t parser.TypeRef, models map[string]parser.Model) any {
+	return sampleValue(t, models, 0)
+}
+
+func sampleValue(t parser.TypeRef, models map[string]parser.Model, depth int) any {
+	if depth > maxSampleDepth {
+		if t.Optional {
+			return nil
+		}
+		switch t.Kind {
+		case parser.TypeList:
+			return []any{}
+		case parser.TypeMap:
+			return map[string]any{}
+		}
+		if _, ok := models[t.Name]; ok {
+			return map[string]any{}
+		}
+	}
+	switch t.Kind {
+	case parser.TypeList:
+		if t.Elem == nil {
+			return []any{}
+		}
+		return []any{sampleValue(*t.Elem, models, depth+1)}
+	case parser.TypeMap:
+		if t.Value == nil {
+			return map[string]any{}
+		}
+		return map[string]any{"key": sampleValue(*t.Value, models, depth+1)}
+	}
+	switch t.Name {
+	case "string":
+		return "string"
+	case "int":
+		return 1
+	case "bool":
+		return true
+	case "json", "raw":
+		return map[string]any{}
+	}
+	model, ok := models[t.Name]
+	if !ok {
+		return map[string]any{}
+	}
+	out := make(map[string]any, len(model.Fields))
+	for _, field := range model.Fields {
+		out[JSONName(field.Name)] = sampleValue(field.Type, models, depth+1)
+	}
+	return out
+}
+
+// WrongValue returns a JSON value that no conforming server accepts for the
+// given type, or false when every value is acceptable (json and raw).
+func WrongValue(t parser.TypeRef) (any, bool) {
+	switch t.Kind {
+	case parser.TypeList:
+		return map[string]any{}, true
+	case parser.TypeMap:
+		return []any{}, true
+	}
+	switch t.Name {
+	case "string":
+		return 123, true
+	case "int":
+		return 1.5, true
+	case "bool":
+		return "maybe", true
+	case "json", "raw":
+		return nil, false
+	default:
+		return "not an object", true
+	}
+}
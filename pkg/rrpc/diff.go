@@ -0,0 +1,29 @@
+package rrpc
+
+import (
+	"github.com/Rapid-Vision/rRPC/internal/diff"
+)
+
+// Change is a difference between two versions of a schema, as reported by
+// rrpc diff. Code is a stable identifier such as "param_removed" (see
+// docs/diff.md), Path locates the change, e.g. "rpc GetText.lang",
+// and Message is meant for humans. Breaking changes are ones that can make
+// a client generated from the old schema fail against a server built from
+// the new one.
+type Change struct {
+	Code     string
+	Breaking bool
+	Path     string
+	Message  string
+}
+
+// Diff lists the changes from before to after: RPCs first, then models,
+// each in declaration order with additions last.
+func Diff(before, after *Schema) []Change {
+	changes := diff.Compare(before.schema, after.schema)
+	out := make([]Change, 0, len(changes))
+	for _, change := range changes {
+		out = append(out, Change(change))
+	}
+	return out
+}
@@ -0,0 +1,19 @@
+// Package rrpc is the Go API of the rRPC compiler, for build tools and
+// tests that want to parse, generate, format or compare schemas without
+// running the rrpc binary.
+//
+// # Stability
+//
+// The package follows semantic versioning together with the rrpc module:
+// within a major version, exported identifiers are not removed or
+// changed incompatibly. New functions, struct fields, constants and
+// diagnostic or change codes may be added in minor versions, so do not
+// rely on exhaustive switches or unkeyed struct literals.
+//
+// The generated code itself is not covered: it may change in any release,
+// like the output of the rrpc binary. Compare it with rrpc check or keep
+// it under version control.
+//
+// The internal packages of the module are the implementation and are not
+// importable.
+package rrpc
@@ -0,0 +1,20 @@
+package rrpc
+
+import (
+	"github.com/Rapid-Vision/rRPC/internal/formatter"
+)
+
+// Format returns the canonical formatting of a schema file, as rrpc format
+// does. Comments are kept. The error is Diagnostics when the file does
+// not parse.
+func Format(file File) ([]byte, error) {
+	schema, err := parseFile(file)
+	if err != nil {
+		return nil, err
+	}
+	formatted, err := formatter.FormatSchema(schema.schema)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(formatted), nil
+}
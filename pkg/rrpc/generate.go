@@ -0,0 +1,83 @@
+package rrpc
+
+import (
+	"fmt"
+
+	"github.com/Rapid-Vision/rRPC/internal/gen/override"
+	"github.com/Rapid-Vision/rRPC/internal/project"
+)
+
+const (
+	KindClient  = project.KindClient
+	KindServer  = project.KindServer
+	KindOpenAPI = project.KindOpenAPI
+)
+
+// Target selects a generator, as a target in rrpc.yaml does. Kind is
+// KindClient, KindServer or KindOpenAPI. Lang is go, python (py) or
+// typescript (ts), or plugin:<name> to run the rrpc-gen-<name> generator
+// plugin; OpenAPI takes no language. Package defaults to rpcclient or
+// rpcserver.
+type Target struct {
+	Kind    string
+	Lang    string
+	Package string
+}
+
+// Options configures generation. All fields are optional.
+type Options struct {
+	// Prefix is the URL path prefix; nil means "rpc" and "" no prefix.
+	Prefix *string
+	// TSZod and PyPydantic enable input validation in TypeScript and
+	// Python clients.
+	TSZod      bool
+	PyPydantic bool
+	// Title and Version fill the info object of OpenAPI documents.
+	Title   string
+	Version string
+	// Templates replaces embedded generator templates by file name, see
+	// docs/templates.md.
+	Templates map[string]string
+	// Plugin is passed to generator plugins.
+	Plugin map[string]string
+}
+
+// Generate renders the code of a target. The returned map is keyed by
+// slash-separated paths relative to the package directory, or to the
+// output directory for OpenAPI.
+func Generate(schema *Schema, target Target, opts Options) (map[string][]byte, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("schema is nil")
+	}
+	t := project.Target{
+		Kind:    target.Kind,
+		Lang:    target.Lang,
+		Package: target.Package,
+		Options: project.Options{
+			TSZod:      opts.TSZod,
+			PyPydantic: opts.PyPydantic,
+			Title:      opts.Title,
+			Version:    opts.Version,
+			Plugin:     opts.Plugin,
+		},
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	if _, ok := t.PluginName(); ok && len(opts.Templates) > 0 {
+		return nil, fmt.Errorf("templates do not apply to plugin targets")
+	}
+	prefix := "rpc"
+	if opts.Prefix != nil {
+		prefix = *opts.Prefix
+	}
+	files, err := project.GenerateWithTemplates(schema.schema, prefix, t, override.Templates(opts.Templates))
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]byte, len(files))
+	for name, contents := range files {
+		out[name] = []byte(contents)
+	}
+	return out, nil
+}
@@ -0,0 +1,151 @@
+package rrpc_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Rapid-Vision/rRPC/pkg/rrpc"
+)
+
+func ExampleParse() {
+	schema, diagnostics := rrpc.Parse(rrpc.File{Name: "text.rrpc", Source: []byte(`# Text is a document.
+model Text {
+    title: string?
+}
+
+rpc GetText(textId: int) Text
+`)})
+	if len(diagnostics) > 0 {
+		fmt.Println(rrpc.Diagnostics(diagnostics))
+		return
+	}
+	for _, model := range schema.Models() {
+		fmt.Printf("model %s at %s: %s\n", model.Name, model.Position, model.Doc)
+	}
+	for _, rpc := range schema.RPCs() {
+		fmt.Printf("%s -> POST %s, param %s, result %q\n", rpc.Name, rpc.Path("rpc"), rpc.Params[0].WireName, rpc.ResultKey)
+	}
+	// Output:
+	// model Text at text.rrpc:2:1: Text is a document.
+	// GetText -> POST /rpc/get_text, param text_id, result "text"
+}
+
+func ExampleParse_diagnostics() {
+	_, diagnostics := rrpc.Parse(rrpc.File{Name: "text.rrpc", Source: []byte("rpc GetText() Txt\n")})
+	for _, d := range diagnostics {
+		fmt.Println(d.Code, d)
+	}
+	// Output:
+	// unknown_type text.rrpc:1:15: rpc "GetText" returns: unknown type "Txt"
+}
+
+func ExampleGenerate() {
+	schema, diagnostics := rrpc.Parse(rrpc.File{Name: "text.rrpc", Source: []byte("rpc Ping()\n")})
+	if len(diagnostics) > 0 {
+		fmt.Println(rrpc.Diagnostics(diagnostics))
+		return
+	}
+	files, err := rrpc.Generate(schema, rrpc.Target{Kind: rrpc.KindClient, Lang: "go", Package: "pingclient"}, rrpc.Options{})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Println(strings.Join(names, " "))
+	// Output:
+	// client.go errors.go fake.go memory.go models.go rpcs.go transport.go
+}
+
+func ExampleFormat() {
+	formatted, err := rrpc.Format(rrpc.File{Name: "text.rrpc", Source: []byte("model Text{title:string # shown in lists\n}")})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Print(string(formatted))
+	// Output:
+	// model Text {
+	//     title: string # shown in lists
+	// }
+}
+
+func ExampleDiff() {
+	before, _ := rrpc.Parse(rrpc.File{Name: "old.rrpc", Source: []byte("rpc GetText(id: int, lang: string?)\n")})
+	after, _ := rrpc.Parse(rrpc.File{Name: "new.rrpc", Source: []byte("rpc GetText(id: int)\n")})
+	for _, change := range rrpc.Diff(before, after) {
+		fmt.Println(change.Code, change.Breaking, change.Path)
+	}
+	// Output:
+	// param_removed true rpc GetText.lang
+}
+
+func TestParseFiles(t *testing.T) {
+	schema, diagnostics := rrpc.Parse(
+		rrpc.File{Name: "models.rrpc", Source: []byte("model Text {\n    title: string\n}")},
+		rrpc.File{Name: "rpcs.rrpc", Source: []byte("# Fetch a text.\n# rrpc:query\nrpc GetText() Text\n")},
+	)
+	if len(diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", rrpc.Diagnostics(diagnostics))
+	}
+	rpc := schema.RPCs()[0]
+	if rpc.Position != (rrpc.Position{File: "rpcs.rrpc", Line: 3, Column: 1}) || rpc.Doc != "Fetch a text." || !slices.Equal(rpc.Annotations, []string{"query"}) {
+		t.Fatalf("unexpected rpc: %+v", rpc)
+	}
+	if rpc.Returns.Kind != rrpc.TypeModel || rpc.Returns.Name != "Text" {
+		t.Fatalf("unexpected return type: %+v", rpc.Returns)
+	}
+
+	_, diagnostics = rrpc.Parse(
+		rrpc.File{Name: "a.rrpc", Source: []byte("model Text {\n    title: string\n}\n")},
+		rrpc.File{Name: "b.rrpc", Source: []byte("\nmodel Text {\n    body: string\n}\n")},
+	)
+	if len(diagnostics) != 1 || diagnostics[0].Code != "duplicate_model" || diagnostics[0].Start.File != "b.rrpc" || diagnostics[0].Start.Line != 2 {
+		t.Fatalf("unexpected diagnostics: %+v", diagnostics)
+	}
+	if _, diagnostics := rrpc.Parse(); len(diagnostics) != 1 {
+		t.Fatalf("expected a diagnostic for no files")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	schema, diagnostics := rrpc.Parse(rrpc.File{Source: []byte("rpc Ping()\n")})
+	if len(diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", rrpc.Diagnostics(diagnostics))
+	}
+	prefix := "api"
+	files, err := rrpc.Generate(schema, rrpc.Target{Kind: rrpc.KindOpenAPI}, rrpc.Options{Prefix: &prefix, Title: "Ping API"})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if spec := string(files["openapi.json"]); !strings.Contains(spec, `"/api/ping"`) || !strings.Contains(spec, `"title": "Ping API"`) {
+		t.Fatalf("unexpected spec:\n%s", spec)
+	}
+	files, err = rrpc.Generate(schema, rrpc.Target{Kind: rrpc.KindServer, Lang: "go"}, rrpc.Options{
+		Templates: map[string]string{"header.go.tmpl": "// Copyright Example Corp.\n\npackage {{.Package}}\n"},
+	})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if !strings.HasPrefix(string(files["rpcs.go"]), "// Copyright Example Corp.\n\npackage rpcserver\n") {
+		t.Fatalf("template override not applied:\n%s", files["rpcs.go"])
+	}
+	if _, err := rrpc.Generate(schema, rrpc.Target{Kind: rrpc.KindServer, Lang: "ts"}, rrpc.Options{}); err == nil {
+		t.Fatalf("expected error for typescript server")
+	}
+}
+
+func TestFormatError(t *testing.T) {
+	_, err := rrpc.Format(rrpc.File{Name: "bad.rrpc", Source: []byte("model {")})
+	var diagnostics rrpc.Diagnostics
+	if !errors.As(err, &diagnostics) || diagnostics[0].Start.File != "bad.rrpc" {
+		t.Fatalf("expected diagnostics, got %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+package rrpc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Rapid-Vision/rRPC/internal/ir"
+	"github.com/Rapid-Vision/rRPC/internal/parser"
+	"github.com/Rapid-Vision/rRPC/internal/protocol"
+)
+
+// File is a schema source file. Name is only used in positions.
+type File struct {
+	Name   string
+	Source []byte
+}
+
+// Position is a 1-based location in a schema file. Columns count runes.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Severity of a Diagnostic.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Diagnostic is a problem found in a schema. Code is a stable identifier
+// such as "unknown_type"; Message is meant for humans and may change. End
+// is exclusive.
+type Diagnostic struct {
+	Start    Position
+	End      Position
+	Severity Severity
+	Code     string
+	Message  string
+}
+
+func (d Diagnostic) Error() string {
+	return d.Start.String() + ": " + d.Message
+}
+
+// Diagnostics is returned as the error of functions that parse a schema.
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) Error() string {
+	if len(d) == 1 {
+		return d[0].Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d errors:", len(d))
+	for _, diagnostic := range d {
+		b.WriteString("\n  ")
+		b.WriteString(diagnostic.Error())
+	}
+	return b.String()
+}
+
+// Schema is a parsed and validated schema. It is immutable.
+type Schema struct {
+	schema   *parser.Schema
+	models   []Model
+	rpcs     []RPC
+	comments []Comment
+}
+
+// Models returns the model declarations in source order.
+func (s *Schema) Models() []Model { return s.models }
+
+// RPCs returns the RPC declarations in source order.
+func (s *Schema) RPCs() []RPC { return s.rpcs }
+
+// Comments returns every comment in the sources, in order.
+func (s *Schema) Comments() []Comment { return s.comments }
+
+// Model is a model declaration. Doc is the text of the comment lines
+// directly above the declaration, without the # markers. Annotations are
+// the "# rrpc:" directive lines among them, without the prefix.
+type Model struct {
+	Name        string
+	Doc         string
+	Annotations []string
+	Position    Position
+	Fields      []Field
+}
+
+// Field is a model field or an RPC parameter. WireName is its JSON key.
+type Field struct {
+	Name        string
+	WireName    string
+	Doc         string
+	Annotations []string
+	Position    Position
+	Type        Type
+}
+
+// TypeKind is the kind of a Type.
+type TypeKind string
+
+const (
+	TypeBuiltin TypeKind = "builtin"
+	TypeModel   TypeKind = "model"
+	TypeList    TypeKind = "list"
+	TypeMap     TypeKind = "map"
+)
+
+// Type is a type reference. Name is set for builtin types (string, int,
+// bool, json, raw) and models. Elem is the element type of lists and the
+// value type of maps, whose keys are always strings.
+type Type struct {
+	Kind     TypeKind
+	Name     string
+	Elem     *Type
+	Optional bool
+}
+
+// RPC is an RPC declaration. Returns is nil when the RPC has no result;
+// ResultKey is the key of the result in the response object.
+type RPC struct {
+	Name        string
+	Doc         string
+	Annotations []string
+	Position    Position
+	Params      []Field
+	Returns     *Type
+	ResultKey   string
+}
+
+// Path returns the HTTP route of the RPC under the URL path prefix.
+func (r RPC) Path(prefix string) string {
+	return protocol.RPCPath(prefix, r.Name)
+}
+
+// Comment is a comment as written in the source, including the leading #.
+type Comment struct {
+	Position Position
+	Text     string
+}
+
+// Parse parses and validates a schema made of one or more files. The files
+// are read in order as one source, so declarations may refer to models in
+// other files. The schema is nil when any error is found; diagnostics are
+// sorted by file and position.
+func Parse(files ...File) (*Schema, []Diagnostic) {
+	if len(files) == 0 {
+		return nil, []Diagnostic{{Severity: SeverityError, Code: "no_files", Message: "no schema files"}}
+	}
+	var source strings.Builder
+	lines := make(lineMap, 0, len(files))
+	line := 1
+	for _, file := range files {
+		lines = append(lines, fileStart{name: file.Name, line: line})
+		text := string(file.Source)
+		if !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		source.WriteString(text)
+		line += strings.Count(text, "\n")
+	}
+
+	schema, parsed := parser.ParseWithDiagnostics(source.String())
+	if len(parsed) > 0 {
+		diagnostics := make([]Diagnostic, 0, len(parsed))
+		for _, d := range parsed {
+			diagnostics = append(diagnostics, Diagnostic{
+				Start:    lines.position(d.Range.Start.Line, d.Range.Start.Col),
+				End:      lines.position(d.Range.End.Line, d.Range.End.Col),
+				Severity: Severity(d.Severity),
+				Code:     d.Code,
+				Message:  d.Message,
+			})
+		}
+		sort.SliceStable(diagnostics, func(i, j int) bool {
+			a, b := diagnostics[i].Start, diagnostics[j].Start
+			if a.File != b.File {
+				return a.File < b.File
+			}
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			return a.Column < b.Column
+		})
+		return nil, diagnostics
+	}
+	return newSchema(schema, lines), nil
+}
+
+// parseFile parses a single file, returning Diagnostics as the error.
+func parseFile(file File) (*Schema, error) {
+	schema, diagnostics := Parse(file)
+	if len(diagnostics) > 0 {
+		return nil, Diagnostics(diagnostics)
+	}
+	return schema, nil
+}
+
+func newSchema(schema *parser.Schema, lines lineMap) *Schema {
+	repr := ir.FromSchema(schema, "")
+	out := &Schema{schema: schema}
+	for _, model := range repr.Models {
+		out.models = append(out.models, Model{
+			Name:        model.Name,
+			Doc:         model.Doc,
+			Annotations: model.Annotations,
+			Position:    lines.position(model.Position.Line, model.Position.Column),
+			Fields:      fields(model.Fields, lines),
+		})
+	}
+	for _, rpc := range repr.RPCs {
+		out.rpcs = append(out.rpcs, RPC{
+			Name:        rpc.Name,
+			Doc:         rpc.Doc,
+			Annotations: rpc.Annotations,
+			Position:    lines.position(rpc.Position.Line, rpc.Position.Column),
+			Params:      fields(rpc.Params, lines),
+			Returns:     typeRef(rpc.Returns),
+			ResultKey:   rpc.ResultKey,
+		})
+	}
+	for _, comment := range repr.Comments {
+		out.comments = append(out.comments, Comment{
+			Position: lines.position(comment.Position.Line, comment.Position.Column),
+			Text:     comment.Text,
+		})
+	}
+	return out
+}
+
+func fields(in []ir.Field, lines lineMap) []Field {
+	out := make([]Field, 0, len(in))
+	for _, field := range in {
+		out = append(out, Field{
+			Name:        field.Name,
+			WireName:    field.WireName,
+			Doc:         field.Doc,
+			Annotations: field.Annotations,
+			Position:    lines.position(field.Position.Line, field.Position.Column),
+			Type:        *typeRef(&field.Type),
+		})
+	}
+	return out
+}
+
+func typeRef(t *ir.Type) *Type {
+	if t == nil {
+		return nil
+	}
+	return &Type{
+		Kind:     TypeKind(t.Kind),
+		Name:     t.Name,
+		Elem:     typeRef(t.Elem),
+		Optional: t.Optional,
+	}
+}
+
+type fileStart struct {
+	name string
+	line int
+}
+
+// lineMap maps lines of the joined source back to their files.
+type lineMap []fileStart
+
+func (m lineMap) position(line, col int) Position {
+	for i := len(m) - 1; i >= 0; i-- {
+		if line >= m[i].line {
+			return Position{File: m[i].name, Line: line - m[i].line + 1, Column: col}
+		}
+	}
+	return Position{Line: line, Column: col}
+}